@@ -0,0 +1,418 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/layout"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+)
+
+// DefaultRootFontSize is the root font size used to resolve rem and em
+// units when laying out the tree
+const DefaultRootFontSize = 16
+
+// ErrTerminated is returned by Run when the app was ended by a signal such
+// as SIGTERM or SIGHUP
+var ErrTerminated = errors.New("app: terminated by signal")
+
+// Options configures an App
+type Options struct {
+	In  *os.File // Terminal input (default os.Stdin)
+	Out *os.File // Terminal output (default os.Stdout)
+
+	Mouse bool // Report clicks, wheel and drags
+	Hover bool // Also report mouse motion with no button held
+	Focus bool // Report the terminal gaining and losing focus
+
+	// Keyboard requests kitty keyboard enhancements; terminals without the
+	// protocol keep sending legacy input
+	Keyboard input.KeyboardFlags
+
+	// DisableSuspend delivers Ctrl+Z like any other key instead of
+	// suspending the app, for example to a TextArea for undo
+	DisableSuspend bool
+
+	MaxFPS        int           // Frame rate cap (0 = renderer.DefaultMaxFPS)
+	EscapeTimeout time.Duration // Wait for the rest of an escape sequence (0 = input.DefaultEscapeTimeout)
+
+	// OnEvent is called for every input event. Changes made to the tree are
+	// drawn in the next frame. Mouse events are first dispatched to the
+	// listeners of the nodes under the pointer (see StyledNode.On). Key
+	// events go to the focused node first and only reach OnEvent if no
+	// listener prevented them and they did not move focus.
+	OnEvent func(a *App, event input.Event)
+
+	// OnResize is called when the terminal size changes, before the tree is
+	// laid out at the new size
+	OnResize func(a *App, width, height int)
+}
+
+// App runs a full-screen terminal application without bubbletea. It owns
+// the terminal while running: raw mode, the alternate screen and the input
+// modes in Options. Its StyledNode tree is the single source of rendering:
+// handlers change the tree, and every frame lays it out at the terminal size
+// and draws it through one Screen, rewriting only the rows that changed.
+// Frames are drawn by a renderer.Scheduler, so changes made in quick
+// succession are coalesced into one frame.
+//
+// The terminal is restored when Run returns, including when it ends by
+// SIGTERM or SIGHUP or by a panic in a handler or while rendering. Ctrl+Z
+// suspends the app to the shell and redraws it on resume.
+type App struct {
+	opts Options
+	in   *os.File
+	out  *os.File
+
+	// mu guards the tree and screen; handlers run holding it
+	mu     sync.Mutex
+	root   *renderer.StyledNode
+	screen *renderer.Screen
+	lines  []string // Rows on screen, nil to redraw every row
+	cursor bool     // Terminal cursor shown after the last frame
+	events *renderer.EventDispatcher
+	focus  *renderer.FocusManager
+	width  atomic.Int32
+	height atomic.Int32
+
+	// termMu guards the terminal state and serializes output
+	termMu sync.Mutex
+	state  *term.State
+	active bool
+
+	scheduler *renderer.Scheduler
+	quit      chan struct{}
+	quitOnce  sync.Once
+}
+
+// New creates an app drawing root. Call Run to start it.
+func New(root *renderer.StyledNode, opts Options) *App {
+	in, out := opts.In, opts.Out
+	if in == nil {
+		in = os.Stdin
+	}
+	if out == nil {
+		out = os.Stdout
+	}
+
+	screen := renderer.NewScreen(0, 0)
+	screen.SetOutputMode(renderer.OutputModeANSI)
+	screen.SetCursorEmulation(false) // The terminal cursor is shown instead
+
+	a := &App{
+		opts:   opts,
+		in:     in,
+		out:    out,
+		root:   root,
+		screen: screen,
+		events: renderer.NewEventDispatcher(nil),
+		focus:  renderer.NewFocusManager(root),
+		quit:   make(chan struct{}),
+	}
+	a.events.SetFocusManager(a.focus)
+	a.scheduler = renderer.NewScheduler(frameWriter{a}, a.frame, renderer.SchedulerOptions{
+		MaxFPS:       opts.MaxFPS,
+		Synchronized: renderer.DetectSynchronizedOutput(),
+	})
+	return a
+}
+
+// Root returns the tree being drawn. Use it from handlers or Update.
+func (a *App) Root() *renderer.StyledNode {
+	return a.root
+}
+
+// SetRoot replaces the tree being drawn. Use it from handlers or Update.
+func (a *App) SetRoot(root *renderer.StyledNode) {
+	a.root = root
+	a.focus.SetRoot(root)
+	a.Invalidate()
+}
+
+// Focus returns the focus manager of the tree. Use it from handlers or
+// Update.
+func (a *App) Focus() *renderer.FocusManager {
+	return a.focus
+}
+
+// Size returns the terminal size in cells
+func (a *App) Size() (width, height int) {
+	return int(a.width.Load()), int(a.height.Load())
+}
+
+// Invalidate requests a new frame. It is safe to call from any goroutine.
+func (a *App) Invalidate() {
+	a.scheduler.Request()
+}
+
+// Update runs fn holding the tree lock and then requests a frame. Use it to
+// change the tree from goroutines other than the handlers, for example from
+// timers driving an animation; calling it from a handler deadlocks.
+func (a *App) Update(fn func()) {
+	a.withTree(fn)
+	a.Invalidate()
+}
+
+// withTree runs fn holding the tree lock, releasing it if fn panics
+func (a *App) withTree(fn func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fn()
+}
+
+// Quit ends Run. It is safe to call from any goroutine.
+func (a *App) Quit() {
+	a.quitOnce.Do(func() { close(a.quit) })
+}
+
+// Run takes over the terminal and handles input, resizes and signals until
+// Quit is called, the input ends or a terminating signal arrives. The
+// terminal is restored before Run returns.
+func (a *App) Run() error {
+	if !term.IsTerminal(int(a.in.Fd())) || !term.IsTerminal(int(a.out.Fd())) {
+		return errors.New("app: input and output must be terminals")
+	}
+
+	// Subscribe before touching the terminal so no signal is missed
+	signals := make(chan os.Signal, 4)
+	signal.Notify(signals, append(resizeSignals, terminateSignals...)...)
+	defer signal.Stop(signals)
+
+	if err := a.setupTerminal(); err != nil {
+		return err
+	}
+	defer a.restoreTerminal()
+	a.updateSize()
+
+	a.scheduler.Start()
+	defer a.scheduler.Stop()
+
+	reader, err := cancelreader.NewReader(a.in)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	defer reader.Cancel()
+
+	// Keys typed while the renderer was querying the terminal come first
+	var source io.Reader = reader
+	if pending := renderer.PendingInput(); len(pending) > 0 {
+		source = io.MultiReader(bytes.NewReader(pending), reader)
+	}
+	events := input.NewReader(source, a.opts.EscapeTimeout)
+	events.Start()
+	a.Invalidate()
+
+	for {
+		select {
+		case event, ok := <-events.Events():
+			if !ok {
+				if err := events.Err(); err != nil && !errors.Is(err, cancelreader.ErrCanceled) {
+					return err
+				}
+				return nil
+			}
+			a.dispatch(event)
+
+		case sig := <-signals:
+			if !isResize(sig) {
+				return ErrTerminated
+			}
+			a.resize()
+
+		case <-a.quit:
+			return nil
+		}
+	}
+}
+
+// dispatch delivers mouse events to the listeners of the tree as last
+// drawn and key and paste events to the focused node, then hands every
+// event but those used there to OnEvent. Ctrl+Z suspends instead.
+func (a *App) dispatch(event input.Event) {
+	if key, ok := event.(input.KeyEvent); ok && !a.opts.DisableSuspend {
+		if key.Key == input.KeyRune && key.Rune == 'z' && key.Mod&^(input.ModCapsLock|input.ModNumLock) == input.ModCtrl {
+			if key.Action == input.KeyPress {
+				a.suspend()
+			}
+			return
+		}
+	}
+
+	a.withTree(func() {
+		switch event := event.(type) {
+		case input.MouseEvent:
+			a.events.Dispatch(event)
+		case input.KeyEvent:
+			if a.focus.HandleKey(event) {
+				return
+			}
+		case input.PasteEvent:
+			if a.focus.HandlePaste(event.Text) {
+				return
+			}
+		}
+		if a.opts.OnEvent != nil {
+			a.opts.OnEvent(a, event)
+		}
+	})
+	a.Invalidate()
+}
+
+// resize reads the new terminal size and redraws in full
+func (a *App) resize() {
+	a.withTree(a.updateSize)
+	a.Invalidate()
+}
+
+// updateSize reads the terminal size, reports a change to OnResize and
+// schedules a full redraw
+func (a *App) updateSize() {
+	width, height, err := term.GetSize(int(a.out.Fd()))
+	if err != nil {
+		return
+	}
+	if int32(width) == a.width.Load() && int32(height) == a.height.Load() {
+		return
+	}
+
+	a.width.Store(int32(width))
+	a.height.Store(int32(height))
+	a.lines = nil
+	if a.opts.OnResize != nil {
+		a.opts.OnResize(a, width, height)
+	}
+}
+
+// suspend restores the terminal, stops the process until the shell resumes
+// it, then takes the terminal back and redraws
+func (a *App) suspend() {
+	if len(continueSignals) == 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	resumed := make(chan os.Signal, 1)
+	signal.Notify(resumed, continueSignals...)
+	defer signal.Stop(resumed)
+
+	a.restoreTerminal()
+	if err := suspendProcess(); err == nil {
+		<-resumed
+	}
+	if err := a.setupTerminal(); err != nil {
+		return
+	}
+
+	a.lines, a.cursor = nil, false
+	a.updateSize()
+	a.Invalidate()
+}
+
+// frame lays out and renders the tree, returning the rows that changed.
+// It runs on the scheduler's goroutine, so a panic restores the terminal
+// before it propagates.
+func (a *App) frame() string {
+	defer func() {
+		if r := recover(); r != nil {
+			a.restoreTerminal()
+			panic(r)
+		}
+	}()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	width, height := a.Size()
+	if a.root == nil || a.root.Node == nil || width <= 0 || height <= 0 {
+		return ""
+	}
+
+	ctx := layout.NewLayoutContext(float64(width), float64(height), DefaultRootFontSize)
+	layout.Layout(a.root.Node, layout.Tight(float64(width), float64(height)), ctx)
+
+	a.focus.SetRoot(a.root)
+	a.screen.Resize(width, height)
+	a.screen.Render(a.root)
+	a.events.SetRoot(a.root)
+	lines := a.screen.Lines()
+
+	x, y, shape, show := a.screen.Cursor()
+	out := diffFrame(a.lines, lines)
+	if a.cursor && out != "" {
+		// Hide the cursor while rows are rewritten under it
+		out = cursorHide + out
+	}
+	if show || a.cursor {
+		out += cursorSequence(x, y, shape, show)
+	}
+	a.lines, a.cursor = lines, show
+	return out
+}
+
+// setupTerminal enters raw mode and the alternate screen
+func (a *App) setupTerminal() error {
+	a.termMu.Lock()
+	defer a.termMu.Unlock()
+	if a.active {
+		return nil
+	}
+
+	state, err := term.MakeRaw(int(a.in.Fd()))
+	if err != nil {
+		return err
+	}
+	a.state = state
+	a.active = true
+	_, err = io.WriteString(a.out, enterSequence(a.opts))
+	return err
+}
+
+// restoreTerminal leaves the alternate screen and raw mode. It may be
+// called more than once.
+func (a *App) restoreTerminal() {
+	a.termMu.Lock()
+	defer a.termMu.Unlock()
+	if !a.active {
+		return
+	}
+
+	io.WriteString(a.out, exitSequence(a.opts))
+	term.Restore(int(a.in.Fd()), a.state)
+	a.state = nil
+	a.active = false
+}
+
+// frameWriter writes frames while the app holds the terminal and drops
+// them while it is suspended or has been restored
+type frameWriter struct {
+	a *App
+}
+
+func (w frameWriter) Write(p []byte) (int, error) {
+	w.a.termMu.Lock()
+	defer w.a.termMu.Unlock()
+	if !w.a.active {
+		return len(p), nil
+	}
+	return w.a.out.Write(p)
+}
+
+func isResize(sig os.Signal) bool {
+	for _, s := range resizeSignals {
+		if sig == s {
+			return true
+		}
+	}
+	return false
+}
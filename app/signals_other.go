@@ -0,0 +1,23 @@
+//go:build !unix
+
+package app
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// resizeSignals is empty; there is no resize signal outside Unix
+var resizeSignals []os.Signal
+
+// terminateSignals end the app, restoring the terminal first
+var terminateSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// continueSignals is empty; suspending is not supported outside Unix
+var continueSignals []os.Signal
+
+// suspendProcess reports that job control is not available
+func suspendProcess() error {
+	return errors.ErrUnsupported
+}
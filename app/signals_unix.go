@@ -0,0 +1,23 @@
+//go:build unix
+
+package app
+
+import (
+	"os"
+	"syscall"
+)
+
+// resizeSignals report a change in the terminal size
+var resizeSignals = []os.Signal{syscall.SIGWINCH}
+
+// terminateSignals end the app, restoring the terminal first
+var terminateSignals = []os.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP}
+
+// continueSignals resume the process after suspendProcess
+var continueSignals = []os.Signal{syscall.SIGCONT}
+
+// suspendProcess stops the process group as the shell does for Ctrl+Z,
+// which raw mode delivers as a key instead of a signal
+func suspendProcess() error {
+	return syscall.Kill(0, syscall.SIGTSTP)
+}
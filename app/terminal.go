@@ -0,0 +1,95 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+)
+
+// Terminal modes enabled while an app runs
+const (
+	bracketedPasteOn  = "\x1b[?2004h"
+	bracketedPasteOff = "\x1b[?2004l"
+	focusOn           = "\x1b[?1004h"
+	focusOff          = "\x1b[?1004l"
+	mouseButtonsOn    = "\x1b[?1002h\x1b[?1006h" // Clicks, wheel and drags in SGR encoding
+	mouseAllMotionOn  = "\x1b[?1003h\x1b[?1006h" // Also motion with no button held
+	mouseOff          = "\x1b[?1006l\x1b[?1003l\x1b[?1002l"
+	cursorHide        = "\x1b[?25l"
+)
+
+// enterSequence switches the terminal to the alternate screen and enables
+// the input modes requested in opts
+func enterSequence(opts Options) string {
+	r := renderer.NewANSIRendererWithMode(renderer.ColorModeNone)
+
+	var buf strings.Builder
+	buf.WriteString(r.EnterAltScreen())
+	buf.WriteString(r.HideCursor())
+	buf.WriteString(r.ClearScreen())
+	buf.WriteString(bracketedPasteOn)
+	if opts.Focus {
+		buf.WriteString(focusOn)
+	}
+	switch {
+	case opts.Hover:
+		buf.WriteString(mouseAllMotionOn)
+	case opts.Mouse:
+		buf.WriteString(mouseButtonsOn)
+	}
+	if opts.Keyboard != 0 {
+		buf.WriteString(input.EnableKeyboardEnhancements(opts.Keyboard))
+	}
+	return buf.String()
+}
+
+// exitSequence undoes enterSequence, leaving the main screen as it was
+func exitSequence(opts Options) string {
+	r := renderer.NewANSIRendererWithMode(renderer.ColorModeNone)
+
+	var buf strings.Builder
+	if opts.Keyboard != 0 {
+		buf.WriteString(input.DisableKeyboardEnhancements())
+	}
+	if opts.Mouse || opts.Hover {
+		buf.WriteString(mouseOff)
+	}
+	if opts.Focus {
+		buf.WriteString(focusOff)
+	}
+	buf.WriteString(bracketedPasteOff)
+	buf.WriteString(r.Reset())
+	buf.WriteString(renderer.CursorDefault.Sequence())
+	buf.WriteString(r.ShowCursor())
+	buf.WriteString(r.ExitAltScreen())
+	return buf.String()
+}
+
+// diffFrame returns the output that turns the rows on screen into lines,
+// rewriting only rows that changed. A nil previous frame redraws every row.
+func diffFrame(previous, lines []string) string {
+	r := renderer.NewANSIRendererWithMode(renderer.ColorModeNone)
+
+	var buf strings.Builder
+	for y, line := range lines {
+		if previous != nil && y < len(previous) && previous[y] == line {
+			continue
+		}
+		// Rows are positioned absolutely; raw mode turns off the newline
+		// translation that would return the cursor to the first column
+		buf.WriteString(r.MoveCursor(0, y))
+		buf.WriteString(line)
+	}
+	return buf.String()
+}
+
+// cursorSequence moves the terminal cursor to a cell and shows it with a
+// shape after a frame is drawn, or hides it
+func cursorSequence(x, y int, shape renderer.CursorShape, show bool) string {
+	if !show {
+		return cursorHide
+	}
+	r := renderer.NewANSIRendererWithMode(renderer.ColorModeNone)
+	return r.MoveCursor(x, y) + shape.Sequence() + r.ShowCursor()
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+)
+
+func TestDiffFrame(t *testing.T) {
+	lines := []string{"abc", "def", "ghi"}
+	if got, expected := diffFrame(nil, lines), "\x1b[1;1Habc\x1b[2;1Hdef\x1b[3;1Hghi"; got != expected {
+		t.Errorf("Expected a full redraw %q, got %q", expected, got)
+	}
+
+	previous := []string{"abc", "xyz", "ghi"}
+	if got, expected := diffFrame(previous, lines), "\x1b[2;1Hdef"; got != expected {
+		t.Errorf("Expected only the changed row %q, got %q", expected, got)
+	}
+
+	if got := diffFrame(lines, lines); got != "" {
+		t.Errorf("Expected no output for an unchanged frame, got %q", got)
+	}
+}
+
+func TestTerminalSequences(t *testing.T) {
+	opts := Options{Mouse: true, Focus: true, Keyboard: input.KeyboardDisambiguate}
+	enter := enterSequence(opts)
+	for _, want := range []string{"\x1b[?1049h", "\x1b[?25l", bracketedPasteOn, focusOn, mouseButtonsOn, "\x1b[>1u"} {
+		if !strings.Contains(enter, want) {
+			t.Errorf("Expected enter sequence to contain %q, got %q", want, enter)
+		}
+	}
+
+	exit := exitSequence(opts)
+	for _, want := range []string{"\x1b[?1049l", "\x1b[?25h", bracketedPasteOff, focusOff, mouseOff, "\x1b[<u"} {
+		if !strings.Contains(exit, want) {
+			t.Errorf("Expected exit sequence to contain %q, got %q", want, exit)
+		}
+	}
+
+	// The main screen comes back last, after every mode is undone
+	if !strings.HasSuffix(exit, "\x1b[?1049l") {
+		t.Errorf("Expected exit sequence to end by leaving the alternate screen, got %q", exit)
+	}
+	if strings.Contains(enterSequence(Options{}), "\x1b[?1002h") {
+		t.Error("Mouse reporting should be off unless requested")
+	}
+}
+
+func TestCursorSequence(t *testing.T) {
+	if got, expected := cursorSequence(4, 2, renderer.CursorBar, true), "\x1b[3;5H\x1b[6 q\x1b[?25h"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if got := cursorSequence(4, 2, renderer.CursorBar, false); got != cursorHide {
+		t.Errorf("Expected a hidden cursor, got %q", got)
+	}
+	if !strings.Contains(exitSequence(Options{}), "\x1b[0 q") {
+		t.Error("Expected the exit sequence to restore the cursor shape")
+	}
+}
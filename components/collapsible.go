@@ -1,9 +1,10 @@
 package components
 
 import (
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
 	"github.com/SCKelemen/color"
 	"github.com/SCKelemen/layout"
-	"github.com/SCKelemen/cli/renderer"
 )
 
 // Collapsible represents a collapsible section with a header and content
@@ -15,6 +16,15 @@ type Collapsible struct {
 	TitleColor  *color.Color
 	Border      renderer.BorderChars
 	BorderColor *color.Color
+
+	// ID identifies the header across rebuilt trees so it keeps focus.
+	// The title is used when empty.
+	ID string
+
+	// OnToggle is called after a click on the header, or Enter or space
+	// while it has focus, toggles the section, so the caller can rebuild
+	// its tree
+	OnToggle func(c *Collapsible)
 }
 
 // NewCollapsible creates a new collapsible section
@@ -38,6 +48,14 @@ func (c *Collapsible) Toggle() {
 	c.Expanded = !c.Expanded
 }
 
+// toggleFromHeader toggles the section for the header's event handlers
+func (c *Collapsible) toggleFromHeader() {
+	c.Toggle()
+	if c.OnToggle != nil {
+		c.OnToggle(c)
+	}
+}
+
 // ToStyledNode converts the collapsible to a styled node
 func (c *Collapsible) ToStyledNode() *renderer.StyledNode {
 	// Create root container
@@ -75,6 +93,21 @@ func (c *Collapsible) ToStyledNode() *renderer.StyledNode {
 
 	headerStyled := renderer.NewStyledNode(headerNode, headerStyle)
 	headerStyled.Content = headerText
+	headerStyled.Focusable = true
+	headerStyled.ID = "collapsible:" + c.ID
+	if c.ID == "" {
+		headerStyled.ID = "collapsible:" + c.Title
+	}
+	headerStyled.On(renderer.EventClick, func(e *renderer.Event) {
+		c.toggleFromHeader()
+	})
+	headerStyled.On(renderer.EventKeyDown, func(e *renderer.Event) {
+		mod := e.Key.Mod &^ (input.ModCapsLock | input.ModNumLock)
+		if mod == 0 && (e.Key.Key == input.KeyEnter || e.Key.Key == input.KeyRune && e.Key.Rune == ' ') {
+			c.toggleFromHeader()
+			e.PreventDefault()
+		}
+	})
 	rootStyled.AddChild(headerStyled)
 
 	// Add content if expanded
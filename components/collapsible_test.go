@@ -0,0 +1,34 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+)
+
+func TestCollapsibleKeys(t *testing.T) {
+	c := NewCollapsible("Details", "body")
+	toggles := 0
+	c.OnToggle = func(*Collapsible) { toggles++ }
+	root := c.ToStyledNode()
+	focus := renderer.NewFocusManager(root)
+	focus.Focus(root.Children[0])
+
+	tests := []struct {
+		name    string
+		key     input.KeyEvent
+		toggles int
+	}{
+		{"Enter", input.KeyEvent{Key: input.KeyEnter}, 1},
+		{"SpaceWithCapsLock", input.KeyEvent{Key: input.KeyRune, Rune: ' ', Mod: input.ModCapsLock}, 2},
+		{"EnterWithNumLock", input.KeyEvent{Key: input.KeyEnter, Mod: input.ModNumLock}, 3},
+		{"CtrlEnter", input.KeyEvent{Key: input.KeyEnter, Mod: input.ModCtrl}, 3},
+	}
+	for _, tt := range tests {
+		focus.HandleKey(tt.key)
+		if toggles != tt.toggles {
+			t.Errorf("%s: expected %d toggles, got %d", tt.name, tt.toggles, toggles)
+		}
+	}
+}
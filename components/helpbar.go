@@ -0,0 +1,311 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/SCKelemen/cli/keymap"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// keyLabels are shorter names for keys shown in help, on terminals with
+// Unicode
+var keyLabels = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+	"space": "␣",
+}
+
+// HelpBar shows the key bindings active in the current focus context on
+// one row, dropping entries that do not fit the width. When Full is set it
+// shows every active binding in a panel that opens upward over the content
+// above the bar, so the bar keeps its one-row place in the layout.
+type HelpBar struct {
+	Keymap *keymap.Keymap
+	Scopes []string // Active focus scopes, innermost first (see keymap.FocusScopes)
+	Width  int      // Available width in cells
+	Full   bool     // Show the full help panel
+
+	// HelpAction names the binding that toggles the full help. It is kept
+	// at the end of the bar when other entries are dropped.
+	HelpAction string
+
+	Separator      string
+	KeyColor       *color.Color
+	DescColor      *color.Color
+	SeparatorColor *color.Color
+	BorderColor    *color.Color
+	Background     *color.Color
+
+	ascii bool // Show keys and cut-off entries in ASCII
+}
+
+// NewHelpBar creates a help bar for the bindings of km.
+// Falls back to ASCII key names and marks when the terminal lacks Unicode
+// support.
+func NewHelpBar(km *keymap.Keymap, width int) *HelpBar {
+	key, _ := color.ParseColor("#FAFAFA")
+	desc, _ := color.ParseColor("#888888")
+	sep, _ := color.ParseColor("#5A5A5A")
+	border, _ := color.ParseColor("#7D56F4")
+	bg, _ := color.ParseColor("#1A1A2E")
+	ascii := !renderer.DetectUnicode()
+	separator := " • "
+	if ascii {
+		separator = " - "
+	}
+	return &HelpBar{
+		Keymap:         km,
+		Width:          width,
+		HelpAction:     "help",
+		Separator:      separator,
+		KeyColor:       &key,
+		DescColor:      &desc,
+		SeparatorColor: &sep,
+		BorderColor:    &border,
+		Background:     &bg,
+		ascii:          ascii,
+	}
+}
+
+// WithScopes sets the active focus scopes
+func (h *HelpBar) WithScopes(scopes ...string) *HelpBar {
+	h.Scopes = scopes
+	return h
+}
+
+// WithWidth sets the available width
+func (h *HelpBar) WithWidth(width int) *HelpBar {
+	h.Width = width
+	return h
+}
+
+// ToggleFull shows or hides the full help
+func (h *HelpBar) ToggleFull() {
+	h.Full = !h.Full
+}
+
+// helpEntry is a binding as shown in help
+type helpEntry struct {
+	keys string
+	help string
+}
+
+// entries returns the active bindings that have help, and the help toggle
+// separately
+func (h *HelpBar) entries(allKeys bool) (entries []helpEntry, toggle *helpEntry) {
+	for _, b := range h.Keymap.Bindings(h.Scopes...) {
+		if b.Help == "" {
+			continue
+		}
+		keys := b.Keys[:1]
+		if allKeys {
+			keys = b.Keys
+		}
+		labels := make([]string, len(keys))
+		for i, key := range keys {
+			labels[i] = h.keyLabel(key)
+		}
+
+		entry := helpEntry{keys: strings.Join(labels, "/"), help: b.Help}
+		if b.Action == h.HelpAction && toggle == nil {
+			toggle = &entry
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, toggle
+}
+
+// keyLabel shortens the key names of a sequence such as "shift+up". In
+// ASCII the names are kept.
+func (h *HelpBar) keyLabel(sequence string) string {
+	if h.ascii {
+		return sequence
+	}
+	chords := strings.Fields(sequence)
+	for i, chord := range chords {
+		mods, name := "", chord
+		if j := strings.LastIndex(chord, "+"); j > 0 && j < len(chord)-1 {
+			mods, name = chord[:j+1], chord[j+1:]
+		}
+		if label, ok := keyLabels[name]; ok {
+			chords[i] = mods + label
+		}
+	}
+	return strings.Join(chords, " ")
+}
+
+// ToStyledNode converts the help bar to a styled node
+func (h *HelpBar) ToStyledNode() *renderer.StyledNode {
+	if h.Full {
+		return h.fullHelp()
+	}
+
+	rowNode := &layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionRow,
+			Height:        layout.Px(1),
+		},
+	}
+	row := renderer.NewStyledNode(rowNode, nil)
+
+	// A partly typed sequence comes first, such as "g…"
+	if pending := h.Keymap.Pending(); pending != "" {
+		h.addText(row, h.keyLabel(pending)+h.ellipsis()+" ", &renderer.Style{Foreground: h.KeyColor, Bold: true})
+	}
+
+	entries, toggle := h.entries(false)
+	sepWidth := renderer.TextWidth(h.Separator)
+	width := func(entries []helpEntry) int {
+		w := 0
+		for i, e := range entries {
+			if i > 0 {
+				w += sepWidth
+			}
+			w += renderer.TextWidth(e.keys)
+			if e.help != "" {
+				w += 1 + renderer.TextWidth(e.help)
+			}
+		}
+		return w
+	}
+
+	used := 0
+	for _, child := range row.Children {
+		used += renderer.TextWidth(child.Content)
+	}
+	var tail []helpEntry
+	if toggle != nil {
+		tail = append(tail, *toggle)
+	}
+
+	// Drop entries from the end until they fit, with an ellipsis and the
+	// help toggle after them
+	if used+width(append(entries, tail...)) > h.Width {
+		tail = append([]helpEntry{{keys: h.ellipsis()}}, tail...)
+		for len(entries) > 0 && used+width(append(entries, tail...)) > h.Width {
+			entries = entries[:len(entries)-1]
+		}
+	}
+	entries = append(entries, tail...)
+
+	for i, e := range entries {
+		if i > 0 {
+			h.addText(row, h.Separator, &renderer.Style{Foreground: h.SeparatorColor})
+		}
+		h.addText(row, e.keys, &renderer.Style{Foreground: h.KeyColor, Bold: true})
+		if e.help != "" {
+			h.addText(row, " "+e.help, &renderer.Style{Foreground: h.DescColor})
+		}
+	}
+	return row
+}
+
+// ellipsis returns the mark for a partly typed sequence and dropped entries
+func (h *HelpBar) ellipsis() string {
+	if h.ascii {
+		return "..."
+	}
+	return "…"
+}
+
+// addText appends a one-row text node sized to its content
+func (h *HelpBar) addText(row *renderer.StyledNode, content string, style *renderer.Style) {
+	node := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:    layout.DisplayBlock,
+			Width:      layout.Px(float64(renderer.TextWidth(content))),
+			Height:     layout.Px(1),
+			FlexShrink: 0,
+		},
+	}, &renderer.Style{
+		Foreground: style.Foreground,
+		Bold:       style.Bold,
+		WhiteSpace: renderer.WhiteSpacePre,
+	})
+	node.Content = content
+	row.AddChild(node)
+}
+
+// fullHelp lays every active binding out in as many columns as fit the
+// width, in a bordered panel raised over the content above the bar
+func (h *HelpBar) fullHelp() *renderer.StyledNode {
+	entries, toggle := h.entries(true)
+	if toggle != nil {
+		entries = append(entries, *toggle)
+	}
+
+	keyWidth, helpWidth := 0, 0
+	for _, e := range entries {
+		keyWidth = max(keyWidth, renderer.TextWidth(e.keys))
+		helpWidth = max(helpWidth, renderer.TextWidth(e.help))
+	}
+	const gap = 3
+	columnWidth := keyWidth + 1 + helpWidth
+	inner := max(h.Width-4, columnWidth) // Border and padding
+	columns := max(1, min(len(entries), (inner+gap)/(columnWidth+gap)))
+	rows := max(1, (len(entries)+columns-1)/columns)
+	height := rows + 2 // Rows between the top and bottom border
+
+	panelStyle := &renderer.Style{BorderColor: h.BorderColor, Background: h.Background}
+	panelStyle.WithBorder(renderer.RoundedBorder)
+	panelStyle.WithBorderTitle("Help", renderer.TextAlignLeft)
+	panel := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionRow,
+			Height:        layout.Px(float64(rows)),
+			Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(2), Bottom: layout.Px(1), Left: layout.Px(2)},
+			// Rise over the content above, keeping one row in the flow
+			Margin: layout.Spacing{Top: layout.Px(float64(1 - height))},
+			ZIndex: 1,
+		},
+	}, panelStyle)
+
+	for c := 0; c < columns; c++ {
+		start := c * rows
+		if start >= len(entries) {
+			break
+		}
+		column := entries[start:min(start+rows, len(entries))]
+
+		keys := make([]string, len(column))
+		helps := make([]string, len(column))
+		for i, e := range column {
+			keys[i] = e.keys
+			helps[i] = e.help
+		}
+
+		marginLeft := 0
+		if c > 0 {
+			marginLeft = gap
+		}
+		h.addColumn(panel, strings.Join(keys, "\n"), keyWidth, marginLeft, &renderer.Style{Foreground: h.KeyColor, Bold: true})
+		h.addColumn(panel, strings.Join(helps, "\n"), helpWidth, 1, &renderer.Style{Foreground: h.DescColor})
+	}
+	return panel
+}
+
+// addColumn appends a column of lines to the full help panel
+func (h *HelpBar) addColumn(panel *renderer.StyledNode, content string, width, marginLeft int, style *renderer.Style) {
+	node := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:    layout.DisplayBlock,
+			Width:      layout.Px(float64(width)),
+			FlexShrink: 0,
+			Margin:     layout.Spacing{Left: layout.Px(float64(marginLeft))},
+		},
+	}, &renderer.Style{
+		Foreground: style.Foreground,
+		Background: h.Background,
+		Bold:       style.Bold,
+		WhiteSpace: renderer.WhiteSpacePre,
+	})
+	node.Content = content
+	panel.AddChild(node)
+}
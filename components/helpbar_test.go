@@ -0,0 +1,134 @@
+package components
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/keymap"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/layout"
+)
+
+// testHelpBar creates a help bar for a few bindings
+func testHelpBar(t *testing.T, width int) (*HelpBar, *keymap.Keymap) {
+	t.Helper()
+	km := keymap.New()
+	err := km.Add(
+		keymap.Binding{Action: "up", Keys: []string{"up", "k"}, Help: "up"},
+		keymap.Binding{Action: "down", Keys: []string{"down", "j"}, Help: "down"},
+		keymap.Binding{Action: "top", Keys: []string{"g g"}, Help: "top"},
+		keymap.Binding{Action: "select", Keys: []string{"space"}, Help: "select"},
+		keymap.Binding{Action: "quit", Keys: []string{"q"}},
+		keymap.Binding{Action: "help", Keys: []string{"?"}, Help: "more"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewHelpBar(km, width)
+	h.ascii = false
+	h.Separator = " • "
+	return h, km
+}
+
+// renderHelpBar draws a help bar on the last row of a screen, below room
+// for the full help, and returns the rows as plain text
+func renderHelpBar(h *HelpBar, height int) []string {
+	root := renderer.NewStyledNode(&layout.Node{Style: layout.Style{
+		Display:        layout.DisplayFlex,
+		FlexDirection:  layout.FlexDirectionColumn,
+		JustifyContent: layout.JustifyContentFlexEnd,
+	}}, nil)
+	root.AddChild(h.ToStyledNode())
+	ctx := layout.NewLayoutContext(float64(h.Width), float64(height), 16)
+	layout.Layout(root.Node, layout.Tight(float64(h.Width), float64(height)), ctx)
+
+	screen := renderer.NewScreen(h.Width, height)
+	screen.SetUnicode(true)
+	screen.SetOutputMode(renderer.OutputModePlain)
+	screen.Render(root)
+	return screen.Lines()
+}
+
+func TestHelpBarASCII(t *testing.T) {
+	h, km := testHelpBar(t, 30)
+	h.ascii, h.Separator = true, " - "
+
+	if got := renderHelpBar(h, 1)[0]; got != "up up - ... - ? more" {
+		t.Errorf("Expected ASCII key names and marks, got %q", got)
+	}
+	km.Handle(input.KeyEvent{Key: input.KeyRune, Rune: 'g'})
+	if got := renderHelpBar(h, 1)[0]; got != "g... up up - ... - ? more" {
+		t.Errorf("Expected an ASCII pending sequence, got %q", got)
+	}
+}
+
+func TestHelpBarTruncates(t *testing.T) {
+	tests := []struct {
+		width    int
+		expected string
+	}{
+		{60, "↑ up • ↓ down • g g top • ␣ select • ? more"},
+		{30, "↑ up • ↓ down • … • ? more"},
+		{12, "… • ? more"},
+	}
+
+	for _, tt := range tests {
+		h, _ := testHelpBar(t, tt.width)
+		if got := renderHelpBar(h, 1)[0]; got != tt.expected {
+			t.Errorf("Width %d: expected %q, got %q", tt.width, tt.expected, got)
+		}
+	}
+}
+
+func TestHelpBarWithoutToggle(t *testing.T) {
+	h, _ := testHelpBar(t, 30)
+	h.HelpAction = ""
+
+	// The toggle is then an ordinary entry, dropped like the others
+	if got := renderHelpBar(h, 1)[0]; got != "↑ up • ↓ down • g g top • …" {
+		t.Errorf("Expected entries cut off with an ellipsis, got %q", got)
+	}
+}
+
+func TestHelpBarPending(t *testing.T) {
+	h, km := testHelpBar(t, 60)
+	km.Handle(input.KeyEvent{Key: input.KeyRune, Rune: 'g'})
+
+	if got := renderHelpBar(h, 1)[0]; got != "g… ↑ up • ↓ down • g g top • ␣ select • ? more" {
+		t.Errorf("Expected the pending sequence first, got %q", got)
+	}
+	km.Reset()
+	if got := renderHelpBar(h, 1)[0]; got != "↑ up • ↓ down • g g top • ␣ select • ? more" {
+		t.Errorf("Expected no pending sequence after a reset, got %q", got)
+	}
+}
+
+func TestHelpBarFull(t *testing.T) {
+	h, _ := testHelpBar(t, 40)
+	h.ToggleFull()
+
+	expected := []string{
+		"",
+		"",
+		"╭─ Help ───────────────────────────────╮",
+		"│ ↑/k up       g g top      ?   more   │",
+		"│ ↓/j down     ␣   select              │",
+		"╰──────────────────────────────────────╯",
+	}
+	if got := renderHelpBar(h, 6); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the panel over the rows above the bar:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(got, "\n"))
+	}
+
+	// A narrow bar stacks the entries in one column
+	h.Width = 24
+	if got := renderHelpBar(h, 8); got[1] != "╭─ Help ───────────────╮" || got[6] != "│ ?   more             │" {
+		t.Errorf("Expected one column of entries, got:\n%s", strings.Join(got, "\n"))
+	}
+
+	h.ToggleFull()
+	if got := renderHelpBar(h, 1)[0]; got != "↑ up • … • ? more" {
+		t.Errorf("Expected the bar back after toggling, got %q", got)
+	}
+}
@@ -82,15 +82,20 @@ type SpinnerDots struct {
 	frames     []string
 }
 
-// NewSpinnerDots creates a new spinner dots component
+// NewSpinnerDots creates a new spinner dots component.
+// Falls back to ASCII frames when the terminal lacks Unicode support.
 func NewSpinnerDots() *SpinnerDots {
 	fg, _ := color.ParseColor("#7D56F4")
+	frames := []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	if !renderer.DetectUnicode() {
+		frames = []string{"|", "/", "-", "\\"}
+	}
 	return &SpinnerDots{
 		Phase:      0,
 		Interval:   100 * time.Millisecond,
 		LastUpdate: time.Now(),
 		Foreground: &fg,
-		frames:     []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
+		frames:     frames,
 	}
 }
 
@@ -132,17 +137,26 @@ type ProgressBar struct {
 	Width      int
 	Foreground *color.Color
 	Background *color.Color
+	FilledChar string // Glyph for the completed portion
+	EmptyChar  string // Glyph for the remaining portion
 }
 
-// NewProgressBar creates a new progress bar
+// NewProgressBar creates a new progress bar.
+// Falls back to ASCII glyphs when the terminal lacks Unicode support.
 func NewProgressBar(width int) *ProgressBar {
 	fg, _ := color.ParseColor("#7D56F4")
 	bg, _ := color.ParseColor("#3C3C3C")
+	filled, empty := "█", "░"
+	if !renderer.DetectUnicode() {
+		filled, empty = "#", "-"
+	}
 	return &ProgressBar{
 		Progress:   0.0,
 		Width:      width,
 		Foreground: &fg,
 		Background: &bg,
+		FilledChar: filled,
+		EmptyChar:  empty,
 	}
 }
 
@@ -162,7 +176,15 @@ func (p *ProgressBar) ToStyledNode() *renderer.StyledNode {
 	filled := int(float64(p.Width) * p.Progress)
 	empty := p.Width - filled
 
-	text := strings.Repeat("█", filled) + strings.Repeat("░", empty)
+	filledChar, emptyChar := p.FilledChar, p.EmptyChar
+	if filledChar == "" {
+		filledChar = "█"
+	}
+	if emptyChar == "" {
+		emptyChar = "░"
+	}
+
+	text := strings.Repeat(filledChar, filled) + strings.Repeat(emptyChar, empty)
 
 	node := &layout.Node{
 		Style: layout.Style{
@@ -0,0 +1,662 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// ColumnSizing is how a table column takes its width
+type ColumnSizing int
+
+const (
+	ColumnAuto  ColumnSizing = iota // As wide as its widest cell or title
+	ColumnFixed                     // Width cells
+	ColumnFr                        // Width shares of the space the other columns leave
+)
+
+// Column describes a table column
+type Column struct {
+	Title  string
+	Sizing ColumnSizing
+	Width  int // Cells for ColumnFixed, shares for ColumnFr
+	Align  renderer.TextAlign
+
+	// Less orders cells when sorting by the column. By default numbers
+	// compare by value and other text ignoring case.
+	Less func(a, b string) bool
+}
+
+// AutoColumn creates a column as wide as its widest cell
+func AutoColumn(title string) Column {
+	return Column{Title: title, Sizing: ColumnAuto}
+}
+
+// FixedColumn creates a column width cells wide
+func FixedColumn(title string, width int) Column {
+	return Column{Title: title, Sizing: ColumnFixed, Width: width}
+}
+
+// FrColumn creates a column taking shares of the width left by the fixed
+// and auto columns, like a CSS fr track
+func FrColumn(title string, shares int) Column {
+	return Column{Title: title, Sizing: ColumnFr, Width: shares}
+}
+
+// WithAlign sets the alignment of the column's cells
+func (c Column) WithAlign(align renderer.TextAlign) Column {
+	c.Align = align
+	return c
+}
+
+// WithLess sets the sort order of the column
+func (c Column) WithLess(less func(a, b string) bool) Column {
+	c.Less = less
+	return c
+}
+
+// TableCell is a cell of a table row
+type TableCell struct {
+	Text  string
+	Style *renderer.Style // Used instead of the table's style when set
+}
+
+// Table is a data table with a header that stays in place while the rows
+// scroll beneath it. Columns are fixed, fr or auto width, so fr columns
+// grow and shrink with the space the layout gives the table. Rows can be
+// sorted by a column, narrowed by a filter and selected.
+//
+// The up and down arrows, Ctrl+P/N and k/j move the selection, Page Up
+// and Page Down move by a page, and Home, End, g and G go to the first
+// and last row. Enter submits the selected row. The digit keys 1 to 9 sort
+// by that column, and the same digit again reverses the order. Clicking a
+// row selects it, double-clicking submits it, and clicking a title sorts
+// by its column.
+type Table struct {
+	Columns []Column
+
+	Width  int // Width in cells, including the border; 0 fills the container
+	Height int // Height in rows, including the border and header; 0 fits every row
+
+	// OnSelect is called after the selection moves, and OnSubmit when a
+	// row is submitted
+	OnSelect func(t *Table)
+	OnSubmit func(t *Table)
+
+	// ID identifies the table across rebuilt trees so it keeps focus.
+	// A name unique to this table is used when empty.
+	ID string
+
+	Foreground    *color.Color
+	HeaderColor   *color.Color
+	BorderColor   *color.Color
+	SelectedColor *color.Color // Background of the selected row
+
+	rows       [][]TableCell
+	query      string
+	filter     func(row []TableCell) bool
+	sortColumn int  // Column rows are sorted by, or -1
+	descending bool // Sort order
+	view       []int
+	widths     []int // Widths of the fixed and auto columns' text
+	selected   int   // Position of the selected row in view
+	top        int   // Position of the first row shown
+	ascii      bool  // Mark the sort order with ASCII, for terminals without Unicode
+	node       *renderer.StyledNode
+}
+
+// NewTable creates an empty table.
+// Falls back to ASCII sort marks when the terminal lacks Unicode support.
+func NewTable(columns []Column, width, height int) *Table {
+	fg, _ := color.ParseColor("#FAFAFA")
+	header, _ := color.ParseColor("#7D56F4")
+	border, _ := color.ParseColor("#5A5A5A")
+	selected, _ := color.ParseColor("#3C3C5A")
+	return &Table{
+		Columns:       columns,
+		Width:         width,
+		Height:        height,
+		Foreground:    &fg,
+		HeaderColor:   &header,
+		BorderColor:   &border,
+		SelectedColor: &selected,
+		sortColumn:    -1,
+		ascii:         !renderer.DetectUnicode(),
+	}
+}
+
+// SetRows replaces the rows
+func (t *Table) SetRows(rows [][]TableCell) {
+	t.rows = rows
+	t.update()
+}
+
+// AddRow appends a row of unstyled cells
+func (t *Table) AddRow(cells ...string) {
+	row := make([]TableCell, len(cells))
+	for i, text := range cells {
+		row[i] = TableCell{Text: text}
+	}
+	t.AddCells(row...)
+}
+
+// AddCells appends a row of cells
+func (t *Table) AddCells(cells ...TableCell) {
+	t.rows = append(t.rows, cells)
+	t.update()
+}
+
+// Rows returns every row, in the order they were added
+func (t *Table) Rows() [][]TableCell {
+	return t.rows
+}
+
+// Visible returns the indexes in Rows of the rows passing the filter, in
+// the order shown
+func (t *Table) Visible() []int {
+	return t.view
+}
+
+// Selected returns the index in Rows of the selected row, and false when
+// no row is shown
+func (t *Table) Selected() (int, bool) {
+	if len(t.view) == 0 {
+		return 0, false
+	}
+	return t.view[t.selected], true
+}
+
+// SelectedRow returns the cells of the selected row, or nil
+func (t *Table) SelectedRow() []TableCell {
+	if row, ok := t.Selected(); ok {
+		return t.rows[row]
+	}
+	return nil
+}
+
+// Select selects the row at an index in Rows if it is shown. It does not
+// call OnSelect.
+func (t *Table) Select(row int) {
+	for i, r := range t.view {
+		if r == row {
+			t.selected = i
+			t.refresh(true)
+			return
+		}
+	}
+}
+
+// SortBy orders the rows by a column, or restores the order they were
+// added in when column is -1
+func (t *Table) SortBy(column int, descending bool) {
+	if column < 0 || column >= len(t.Columns) {
+		column, descending = -1, false
+	}
+	t.sortColumn, t.descending = column, descending
+	t.update()
+}
+
+// SortColumn returns the column the rows are sorted by, or -1, and
+// whether the order is descending
+func (t *Table) SortColumn() (column int, descending bool) {
+	return t.sortColumn, t.descending
+}
+
+// SetFilter shows only the rows with a cell containing query, ignoring case
+func (t *Table) SetFilter(query string) {
+	t.query = query
+	t.update()
+}
+
+// Filter returns the filter query
+func (t *Table) Filter() string {
+	return t.query
+}
+
+// SetFilterFunc shows only the rows keep accepts, in addition to the
+// filter query. A nil func accepts every row.
+func (t *Table) SetFilterFunc(keep func(row []TableCell) bool) {
+	t.filter = keep
+	t.update()
+}
+
+// toggleSort sorts by a column, reversing the order if already sorted by it
+func (t *Table) toggleSort(column int) {
+	if column == t.sortColumn {
+		t.SortBy(column, !t.descending)
+	} else {
+		t.SortBy(column, false)
+	}
+}
+
+// update rebuilds the shown rows after the rows, filter or order change,
+// keeping the same row selected while it is still shown
+func (t *Table) update() {
+	previous, hadSelection := t.Selected()
+
+	t.view = nil
+	query := strings.ToLower(t.query)
+	for i, row := range t.rows {
+		if t.keep(row, query) {
+			t.view = append(t.view, i)
+		}
+	}
+	if t.sortColumn >= 0 {
+		less := t.Columns[t.sortColumn].Less
+		if less == nil {
+			less = lessCell
+		}
+		col := t.sortColumn
+		sort.SliceStable(t.view, func(i, j int) bool {
+			a, b := rowText(t.rows[t.view[i]], col), rowText(t.rows[t.view[j]], col)
+			if t.descending {
+				return less(b, a)
+			}
+			return less(a, b)
+		})
+	}
+
+	t.measure()
+	t.selected = max(0, min(t.selected, len(t.view)-1))
+	if hadSelection {
+		for i, r := range t.view {
+			if r == previous {
+				t.selected = i
+				break
+			}
+		}
+	}
+	t.refresh(true)
+}
+
+// keep reports whether a row passes the filter func and lower-cased query
+func (t *Table) keep(row []TableCell, query string) bool {
+	if t.filter != nil && !t.filter(row) {
+		return false
+	}
+	if query == "" {
+		return true
+	}
+	for _, cell := range row {
+		if strings.Contains(strings.ToLower(cell.Text), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// rowText returns the text of a row's cell, or "" for a missing cell
+func rowText(row []TableCell, col int) string {
+	if col < len(row) {
+		return row[col].Text
+	}
+	return ""
+}
+
+// lessCell orders numbers by value before other text, which is compared
+// ignoring case, so a column of numbers with placeholders such as "-"
+// still sorts consistently
+func lessCell(a, b string) bool {
+	x, errA := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	y, errB := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil || errB == nil:
+		return errA == nil
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// HandleKey moves the selection, sorts or submits for a key press and
+// reports whether the key was used
+func (t *Table) HandleKey(key input.KeyEvent) bool {
+	if key.Action == input.KeyRelease {
+		return false
+	}
+	mod := key.Mod &^ (input.ModCapsLock | input.ModNumLock)
+	page := max(1, t.bodyRows()-1)
+
+	switch key.Key {
+	case input.KeyUp:
+		t.moveTo(t.selected - 1)
+	case input.KeyDown:
+		t.moveTo(t.selected + 1)
+	case input.KeyPageUp:
+		t.moveTo(t.selected - page)
+	case input.KeyPageDown:
+		t.moveTo(t.selected + page)
+	case input.KeyHome:
+		t.moveTo(0)
+	case input.KeyEnd:
+		t.moveTo(len(t.view) - 1)
+	case input.KeyEnter:
+		if mod != 0 || len(t.view) == 0 {
+			return false
+		}
+		if t.OnSubmit != nil {
+			t.OnSubmit(t)
+		}
+	case input.KeyRune:
+		return t.handleRune(key, mod)
+	default:
+		return false
+	}
+	return true
+}
+
+// handleRune runs the letter and digit bindings
+func (t *Table) handleRune(key input.KeyEvent, mod input.Modifier) bool {
+	r := key.Rune
+	if mod == input.ModShift {
+		if key.ShiftedRune != 0 {
+			r = key.ShiftedRune
+		} else {
+			r = unicode.ToUpper(r)
+		}
+		mod = 0
+	}
+
+	switch {
+	case mod == input.ModCtrl && unicode.ToLower(r) == 'p':
+		t.moveTo(t.selected - 1)
+	case mod == input.ModCtrl && unicode.ToLower(r) == 'n':
+		t.moveTo(t.selected + 1)
+	case mod != 0:
+		return false
+	case r == 'k':
+		t.moveTo(t.selected - 1)
+	case r == 'j':
+		t.moveTo(t.selected + 1)
+	case r == 'g':
+		t.moveTo(0)
+	case r == 'G':
+		t.moveTo(len(t.view) - 1)
+	case r >= '1' && r <= '9' && int(r-'1') < len(t.Columns):
+		t.toggleSort(int(r - '1'))
+	default:
+		return false
+	}
+	return true
+}
+
+// moveTo selects the row at a position of the view and scrolls it into view
+func (t *Table) moveTo(pos int) {
+	pos = max(0, min(pos, len(t.view)-1))
+	if pos == t.selected {
+		return
+	}
+	t.selected = pos
+	t.refresh(true)
+	if t.OnSelect != nil {
+		t.OnSelect(t)
+	}
+}
+
+// bodyRows returns how many rows are shown below the header
+func (t *Table) bodyRows() int {
+	if t.Height > 0 {
+		return max(1, t.Height-4)
+	}
+	return max(1, len(t.view))
+}
+
+// measure works out the widths of the fixed and auto columns' text. Auto
+// columns leave room for the sort mark after the title, so sorting does
+// not resize them.
+func (t *Table) measure() {
+	t.widths = make([]int, len(t.Columns))
+	for col, c := range t.Columns {
+		switch c.Sizing {
+		case ColumnFixed:
+			t.widths[col] = max(0, c.Width)
+		case ColumnAuto:
+			t.widths[col] = renderer.TextWidth(c.Title) + 2
+		}
+	}
+	for _, row := range t.rows {
+		for col, c := range t.Columns {
+			if c.Sizing == ColumnAuto {
+				t.widths[col] = max(t.widths[col], renderer.TextWidth(rowText(row, col)))
+			}
+		}
+	}
+}
+
+// id returns the ID of the table's node
+func (t *Table) id() string {
+	if t.ID != "" {
+		return "table:" + t.ID
+	}
+	return fmt.Sprintf("table:%p", t)
+}
+
+// ToStyledNode converts the table to a styled node. The node is redrawn in
+// place as the selection, order and filter change, so the tree does not
+// have to be rebuilt for each key.
+func (t *Table) ToStyledNode() *renderer.StyledNode {
+	style := layout.Style{
+		Display:       layout.DisplayFlex,
+		FlexDirection: layout.FlexDirectionColumn,
+		Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(1), Bottom: layout.Px(1), Left: layout.Px(1)},
+		FlexShrink:    0,
+	}
+	if t.Width > 0 {
+		style.Width = layout.Px(float64(max(1, t.Width-2)))
+		style.AlignSelf = layout.AlignItemsFlexStart // Keep the width in a stretching column
+	}
+	node := renderer.NewStyledNode(&layout.Node{Style: style}, nil)
+	node.Focusable = true
+	node.ID = t.id()
+
+	node.On(renderer.EventKeyDown, func(e *renderer.Event) {
+		if t.HandleKey(e.Key) {
+			e.PreventDefault()
+		}
+	})
+	node.On(renderer.EventWheel, func(e *renderer.Event) {
+		switch e.Button {
+		case input.MouseWheelUp:
+			t.top -= 3
+		case input.MouseWheelDown:
+			t.top += 3
+		default:
+			return
+		}
+		t.refresh(false)
+	})
+
+	t.node = node
+	t.refresh(true)
+	return node
+}
+
+// refresh redraws the table's node. With follow it scrolls to keep the
+// selected row in view.
+func (t *Table) refresh(follow bool) {
+	if t.node == nil {
+		return
+	}
+	body := t.bodyRows()
+	if follow {
+		t.top = min(t.top, t.selected)
+		t.top = max(t.top, t.selected-body+1)
+	}
+	t.top = max(0, min(t.top, len(t.view)-body))
+
+	// Without a height the table grows and shrinks with its rows
+	t.node.Node.Style.Height = layout.Px(float64(body + 2))
+
+	// The border joins the rules of the rows into grid lines
+	style := &renderer.Style{
+		BorderColor:    t.BorderColor,
+		BorderCollapse: true,
+		RowRule:        renderer.NewRule(renderer.NormalBorder, t.BorderColor),
+		Overflow:       renderer.OverflowHidden,
+	}
+	style.WithBorder(renderer.RoundedBorder)
+	t.node.Style = style
+
+	if len(t.widths) != len(t.Columns) {
+		t.measure()
+	}
+	if len(t.node.Children) != body+1 || len(t.node.Children[0].Children) != len(t.Columns) {
+		t.build(body)
+	}
+
+	header := t.node.Children[0]
+	header.Style = t.rowStyle(nil)
+	for col, c := range t.Columns {
+		title := c.Title
+		if col == t.sortColumn {
+			title += t.sortMark()
+		}
+		t.setCell(header.Children[col], col, title, &renderer.Style{Foreground: t.HeaderColor, Bold: true})
+	}
+
+	for j, row := range t.node.Children[1:] {
+		i := t.top + j
+		if i >= len(t.view) {
+			// Empty rows pad the body so the grid lines reach the bottom
+			row.Style = t.rowStyle(nil)
+			for col, cell := range row.Children {
+				t.setCell(cell, col, "", nil)
+			}
+			continue
+		}
+
+		var bg *color.Color
+		if i == t.selected {
+			bg = t.SelectedColor
+		}
+		row.Style = t.rowStyle(bg)
+		cells := t.rows[t.view[i]]
+		for col, cell := range row.Children {
+			style := &renderer.Style{Foreground: t.Foreground}
+			if col < len(cells) && cells[col].Style != nil {
+				copied := *cells[col].Style
+				style = &copied
+			}
+			if bg != nil {
+				style.Background = bg
+			}
+			t.setCell(cell, col, rowText(cells, col), style)
+		}
+	}
+}
+
+// sortMark returns the mark after the title of the column rows are sorted by
+func (t *Table) sortMark() string {
+	switch {
+	case t.ascii && t.descending:
+		return " v"
+	case t.ascii:
+		return " ^"
+	case t.descending:
+		return " ▼"
+	}
+	return " ▲"
+}
+
+// build creates the nodes of the header and of body rows, which refresh
+// fills in. They are kept while the number of rows and columns stays the
+// same, so a row is still the same node when a press on it is released or
+// it is clicked again.
+func (t *Table) build(body int) {
+	t.node.Children, t.node.Node.Children = nil, nil
+
+	header := t.addRow()
+	header.Node.Style.Margin = layout.Spacing{Bottom: layout.Px(1)} // Gap for the row rule
+	for col := range t.Columns {
+		cell := t.addCell(header)
+		cell.On(renderer.EventMouseDown, func(e *renderer.Event) {
+			t.toggleSort(col)
+		})
+	}
+
+	for j := 0; j < body; j++ {
+		row := t.addRow()
+		for range t.Columns {
+			t.addCell(row)
+		}
+
+		// The row shows whichever row is scrolled to its place
+		row.On(renderer.EventMouseDown, func(e *renderer.Event) {
+			if pos := t.top + j; pos < len(t.view) {
+				t.moveTo(pos)
+			}
+		})
+		row.On(renderer.EventDoubleClick, func(e *renderer.Event) {
+			if pos := t.top + j; pos < len(t.view) {
+				t.moveTo(pos)
+				if t.OnSubmit != nil {
+					t.OnSubmit(t)
+				}
+			}
+		})
+	}
+}
+
+// addRow appends a row of cells separated by column rules
+func (t *Table) addRow() *renderer.StyledNode {
+	row := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionRow,
+			Height:        layout.Px(1),
+			FlexGap:       layout.Px(1), // Gap for the column rule
+			FlexShrink:    0,
+		},
+	}, nil)
+	t.node.AddChild(row)
+	return row
+}
+
+// rowStyle returns the style of a row with a background
+func (t *Table) rowStyle(bg *color.Color) *renderer.Style {
+	return &renderer.Style{
+		Background: bg,
+		ColumnRule: renderer.NewRule(renderer.NormalBorder, t.BorderColor),
+	}
+}
+
+// addCell appends an empty cell to a row
+func (t *Table) addCell(row *renderer.StyledNode) *renderer.StyledNode {
+	node := renderer.NewStyledNode(&layout.Node{}, nil)
+	row.AddChild(node)
+	return node
+}
+
+// setCell sizes a cell for its column and sets its text and style
+func (t *Table) setCell(cell *renderer.StyledNode, col int, text string, style *renderer.Style) {
+	c := t.Columns[col]
+	box := layout.Style{
+		Display: layout.DisplayBlock,
+		Height:  layout.Px(1),
+		Padding: layout.Spacing{Left: layout.Px(1), Right: layout.Px(1)},
+	}
+	if c.Sizing == ColumnFr {
+		box.FlexBasis = layout.Px(0)
+		box.FlexGrow = float64(max(1, c.Width))
+		box.FlexShrink = 1
+	} else {
+		box.Width = layout.Px(float64(t.widths[col]))
+	}
+	cell.Node.Style = box
+
+	if style == nil {
+		style = &renderer.Style{}
+	}
+	style.WhiteSpace = renderer.WhiteSpacePre
+	style.TextOverflow = renderer.TextOverflowEllipsis
+	style.TextAlign = c.Align
+	cell.Style = style
+	cell.Content = text
+}
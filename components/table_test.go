@@ -0,0 +1,290 @@
+package components
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+func TestTableDoubleClickSubmits(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Name"), FrColumn("Note", 1)}, 30, 0)
+	table.AddRow("a", "first")
+	table.AddRow("b", "second")
+	table.AddRow("c", "third")
+	submitted := -1
+	table.OnSubmit = func(t *Table) {
+		submitted, _ = t.Selected()
+	}
+
+	node := table.ToStyledNode()
+	relayout := func() {
+		ctx := layout.NewLayoutContext(40, 10, 16)
+		layout.Layout(node.Node, layout.Tight(40, 10), ctx)
+	}
+	relayout()
+	events := renderer.NewEventDispatcher(node)
+
+	// The second body row, below the border, header and row rule
+	row := node.Children[2]
+	x, y := int(node.Node.Rect.X+row.Node.Rect.X)+2, int(node.Node.Rect.Y+row.Node.Rect.Y)
+	for i := 0; i < 2; i++ {
+		events.Dispatch(input.MouseEvent{X: x, Y: y, Button: input.MouseLeft, Action: input.MousePress})
+		relayout()
+		events.Dispatch(input.MouseEvent{X: x, Y: y, Button: input.MouseLeft, Action: input.MouseRelease})
+		relayout()
+	}
+
+	if selected, _ := table.Selected(); selected != 1 {
+		t.Errorf("Expected the clicked row to be selected, got %d", selected)
+	}
+	if submitted != 1 {
+		t.Errorf("Expected a double click to submit the row, got %d", submitted)
+	}
+}
+
+func TestTableFitsRows(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Name")}, 20, 0)
+	table.AddRow("a")
+	node := table.ToStyledNode()
+
+	table.AddRow("b")
+	table.AddRow("c")
+	if node.Node.Style.Height != layout.Px(5) || len(node.Children) != 4 {
+		t.Errorf("Expected the table to grow to three rows, got height %v with %d rows", node.Node.Style.Height, len(node.Children))
+	}
+	table.SetFilter("b")
+	if node.Node.Style.Height != layout.Px(3) || len(node.Children) != 2 {
+		t.Errorf("Expected the table to shrink to the filtered row, got height %v with %d rows", node.Node.Style.Height, len(node.Children))
+	}
+}
+
+func TestTableSortsMixedColumn(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Size")}, 20, 0)
+	for _, size := range []string{"10", "9", "1a", "x", "2", "-"} {
+		table.AddRow(size)
+	}
+
+	table.SortBy(0, false)
+	var sorted []string
+	for _, row := range table.Visible() {
+		sorted = append(sorted, table.Rows()[row][0].Text)
+	}
+	if expected := []string{"2", "9", "10", "-", "1a", "x"}; !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Expected numbers by value before text, got %v", sorted)
+	}
+}
+
+// renderTable lays a table's node out at the top of a screen and returns
+// the rows as plain text
+func renderTable(node *renderer.StyledNode, width, height int) []string {
+	root := renderer.NewStyledNode(&layout.Node{Style: layout.Style{
+		Display:       layout.DisplayFlex,
+		FlexDirection: layout.FlexDirectionColumn,
+	}}, nil)
+	root.AddChild(node)
+	ctx := layout.NewLayoutContext(float64(width), float64(height), 16)
+	layout.Layout(root.Node, layout.Tight(float64(width), float64(height)), ctx)
+
+	screen := renderer.NewScreen(width, height)
+	screen.SetUnicode(true)
+	screen.SetOutputMode(renderer.OutputModePlain)
+	screen.Render(root)
+	return screen.Lines()
+}
+
+func TestTableSortMarkASCII(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Name")}, 0, 0)
+	table.ascii = true
+	table.AddRow("a")
+	node := table.ToStyledNode()
+
+	table.SortBy(0, false)
+	if got := renderTable(node, 12, 5)[1]; got != "│Name ^    │" {
+		t.Errorf("Expected an ASCII ascending mark, got %q", got)
+	}
+	table.SortBy(0, true)
+	if got := renderTable(node, 12, 5)[1]; got != "│Name v    │" {
+		t.Errorf("Expected an ASCII descending mark, got %q", got)
+	}
+}
+
+// columnWidths returns the widths between the rules of a rendered row
+func columnWidths(line string) []int {
+	var widths []int
+	for _, cell := range strings.Split(strings.Trim(line, "│"), "│") {
+		widths = append(widths, renderer.TextWidth(cell))
+	}
+	return widths
+}
+
+// visibleText returns the first cell of each shown row
+func visibleText(table *Table) []string {
+	var texts []string
+	for _, row := range table.Visible() {
+		texts = append(texts, table.Rows()[row][0].Text)
+	}
+	return texts
+}
+
+func TestTableColumnWidths(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Name"), FixedColumn("Qty", 4), FrColumn("A", 1), FrColumn("B", 2)}, 40, 0)
+	table.AddRow("apple", "3", "x", "y")
+	node := table.ToStyledNode()
+
+	// Auto columns fit the title and sort mark, fixed ones their width,
+	// plus padding
+	widths := columnWidths(renderTable(node, 44, 8)[1])
+	if widths[0] != 8 || widths[1] != 6 {
+		t.Errorf("Expected auto and fixed columns of 8 and 6 cells, got %v", widths)
+	}
+	if widths[2] >= widths[3] {
+		t.Errorf("Expected the fr column with more shares to be wider, got %v", widths)
+	}
+
+	// An auto column grows with its widest cell
+	table.AddRow("watermelon", "12", "x", "y")
+	if widths := columnWidths(renderTable(node, 44, 8)[1]); widths[0] != 12 || widths[1] != 6 {
+		t.Errorf("Expected the auto column to fit watermelon, got %v", widths)
+	}
+}
+
+func TestTableSortToggle(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Name"), AutoColumn("Qty")}, 0, 0)
+	table.AddRow("b", "2")
+	table.AddRow("a", "10")
+	table.AddRow("c", "1")
+
+	tests := []struct {
+		key        rune
+		column     int
+		descending bool
+		order      []string
+	}{
+		{'1', 0, false, []string{"a", "b", "c"}},
+		{'1', 0, true, []string{"c", "b", "a"}},
+		{'2', 1, false, []string{"c", "b", "a"}},
+		{'2', 1, true, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		if !table.HandleKey(input.KeyEvent{Key: input.KeyRune, Rune: tt.key}) {
+			t.Fatalf("Expected %c to be handled", tt.key)
+		}
+		column, descending := table.SortColumn()
+		if column != tt.column || descending != tt.descending {
+			t.Errorf("%c: expected column %d descending %v, got %d %v", tt.key, tt.column, tt.descending, column, descending)
+		}
+		if got := visibleText(table); !reflect.DeepEqual(got, tt.order) {
+			t.Errorf("%c: expected %v, got %v", tt.key, tt.order, got)
+		}
+	}
+
+	table.SortBy(-1, false)
+	if got := visibleText(table); !reflect.DeepEqual(got, []string{"b", "a", "c"}) {
+		t.Errorf("Expected the added order back, got %v", got)
+	}
+	if table.HandleKey(input.KeyEvent{Key: input.KeyRune, Rune: '3'}) {
+		t.Error("Expected a digit past the last column to be passed on")
+	}
+}
+
+func TestTableFilterKeepsSelection(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Fruit")}, 0, 0)
+	for _, fruit := range []string{"apple", "banana", "cherry", "date"} {
+		table.AddRow(fruit)
+	}
+	table.Select(2)
+
+	table.SetFilter("E")
+	if got := visibleText(table); !reflect.DeepEqual(got, []string{"apple", "cherry", "date"}) {
+		t.Fatalf("Expected the rows containing e, got %v", got)
+	}
+	if row, _ := table.Selected(); row != 2 {
+		t.Errorf("Expected cherry to stay selected, got row %d", row)
+	}
+
+	// Once the selected row is filtered out, the one at its place is selected
+	table.SetFilterFunc(func(row []TableCell) bool { return row[0].Text != "cherry" })
+	if row, _ := table.Selected(); row != 3 {
+		t.Errorf("Expected date to be selected, got row %d", row)
+	}
+
+	table.SetFilter("xyz")
+	if _, ok := table.Selected(); ok || table.SelectedRow() != nil {
+		t.Error("Expected no selection without rows")
+	}
+	table.SetFilter("")
+	table.SetFilterFunc(nil)
+	if len(table.Visible()) != 4 {
+		t.Errorf("Expected every row without a filter, got %v", visibleText(table))
+	}
+}
+
+func TestTableScrolling(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("N")}, 20, 8) // Four rows shown
+	for i := 0; i < 10; i++ {
+		table.AddRow(strconv.Itoa(i))
+	}
+	selects := 0
+	table.OnSelect = func(*Table) { selects++ }
+	node := table.ToStyledNode()
+
+	tests := []struct {
+		key      input.Key
+		selected int
+		first    string
+	}{
+		{input.KeyPageDown, 3, "│0"},
+		{input.KeyPageDown, 6, "│3"},
+		{input.KeyPageDown, 9, "│6"},
+		{input.KeyPageDown, 9, "│6"},
+		{input.KeyPageUp, 6, "│6"},
+		{input.KeyPageUp, 3, "│3"},
+		{input.KeyHome, 0, "│0"},
+		{input.KeyEnd, 9, "│6"},
+	}
+
+	for _, tt := range tests {
+		table.HandleKey(input.KeyEvent{Key: tt.key})
+		if row, _ := table.Selected(); row != tt.selected {
+			t.Errorf("%v: expected row %d selected, got %d", tt.key, tt.selected, row)
+		}
+		if got := renderTable(node, 20, 10)[3]; !strings.HasPrefix(got, tt.first) {
+			t.Errorf("%v: expected the first row shown to start %q, got %q", tt.key, tt.first, got)
+		}
+	}
+	if selects != 7 {
+		t.Errorf("Expected OnSelect for each move, got %d calls", selects)
+	}
+}
+
+func TestTableCellStyle(t *testing.T) {
+	table := NewTable([]Column{AutoColumn("Name"), AutoColumn("State")}, 0, 0)
+	red, _ := color.ParseColor("#FF0000")
+	table.AddCells(TableCell{Text: "web"}, TableCell{Text: "down", Style: &renderer.Style{Foreground: &red}})
+	table.AddRow("db", "up")
+	node := table.ToStyledNode()
+
+	selected := node.Children[1].Children
+	if selected[1].Style.Foreground != &red || selected[1].Style.Background != table.SelectedColor {
+		t.Errorf("Expected the cell's own color on the selection background, got %+v", selected[1].Style)
+	}
+	if selected[0].Style.Foreground != table.Foreground {
+		t.Errorf("Expected an unstyled cell in the table's color, got %+v", selected[0].Style)
+	}
+
+	table.HandleKey(input.KeyEvent{Key: input.KeyDown})
+	styled := node.Children[1].Children[1].Style
+	if styled.Foreground != &red || styled.Background != nil {
+		t.Errorf("Expected the cell's color without a background once deselected, got %+v", styled)
+	}
+	if table.Rows()[0][1].Style.Background != nil {
+		t.Error("Expected the row's own style to be left unchanged")
+	}
+}
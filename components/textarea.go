@@ -0,0 +1,754 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/SCKelemen/cli/editor"
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// pasteBreaks normalizes the line breaks of pasted text
+var pasteBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// TextArea is a multiline text editor with any number of cursors. The text
+// is kept in an editor.Editor, which records undo history. Lines wider than
+// the field wrap when SoftWrap is set and scroll sideways otherwise. The
+// terminal cursor follows the primary cursor; other cursors are drawn as
+// reversed cells.
+//
+// Keys follow TextInput, by row of the wrapped text where it matters:
+// the arrows, Home, End, Page Up and Page Down move, with Shift to select
+// and Alt or Ctrl to jump by word; Ctrl+Home and Ctrl+End go to the start
+// and end of the text. The Emacs bindings Ctrl+A/E/B/F/P/N, Alt+B/F,
+// Ctrl+D, Ctrl+H, Ctrl+K, Ctrl+U, Ctrl+W and Alt+D work as well. Ctrl+Z and
+// Ctrl+_ undo, and Ctrl+Y and Ctrl+Shift+Z redo; in an app.App, Ctrl+Z
+// only reaches the editor with Options.DisableSuspend set.
+//
+// Ctrl+Alt+Up and Ctrl+Alt+Down add a cursor on the row above or below,
+// Alt+N selects the word under the cursor and then adds its next match,
+// Alt+click adds a cursor and Esc goes back to one cursor. A paste with as
+// many lines as there are cursors puts one line at each.
+type TextArea struct {
+	Editor *editor.Editor
+
+	Width       int    // Width in cells, including the border
+	Height      int    // Height in rows, including the border
+	SoftWrap    bool   // Wrap long lines rather than scroll sideways
+	LineNumbers bool   // Number lines in a gutter
+	Placeholder string // Shown while the text is empty
+
+	// TabSize is how many spaces Tab inserts. With 0, Tab is left to move
+	// focus.
+	TabSize int
+
+	// Search highlights its matches, and is the query of FindNext,
+	// ReplaceNext and ReplaceAll
+	Search string
+
+	// OnChange is called after the text changes
+	OnChange func(t *TextArea)
+
+	// ID identifies the field across rebuilt trees so it keeps focus.
+	// A name unique to this editor is used when empty.
+	ID string
+
+	CursorShape      renderer.CursorShape
+	Foreground       *color.Color
+	LineNumberColor  *color.Color
+	PlaceholderColor *color.Color
+	MatchColor       *color.Color // Background of search matches
+	BorderColor      *color.Color
+
+	top   int   // First row shown
+	left  int   // First column shown, without soft wrap
+	goals []int // Columns kept by vertical moves, one per selection
+	node  *renderer.StyledNode
+
+	// Rows laid out for rowsText at rowsWidth, or without soft wrap
+	rowsCache []visualRow
+	rowsText  string
+	rowsWidth int
+}
+
+// NewTextArea creates an editor for text
+func NewTextArea(text string, width, height int) *TextArea {
+	fg, _ := color.ParseColor("#FAFAFA")
+	num, _ := color.ParseColor("#5A5A5A")
+	ph, _ := color.ParseColor("#5A5A5A")
+	match, _ := color.ParseColor("#5A4A00")
+	border, _ := color.ParseColor("#5A5A5A")
+	return &TextArea{
+		Editor:           editor.New(text),
+		Width:            width,
+		Height:           height,
+		SoftWrap:         true,
+		CursorShape:      renderer.CursorBlinkingBar,
+		Foreground:       &fg,
+		LineNumberColor:  &num,
+		PlaceholderColor: &ph,
+		MatchColor:       &match,
+		BorderColor:      &border,
+	}
+}
+
+// WithLineNumbers sets whether lines are numbered
+func (t *TextArea) WithLineNumbers(enabled bool) *TextArea {
+	t.LineNumbers = enabled
+	return t
+}
+
+// WithSoftWrap sets whether long lines wrap
+func (t *TextArea) WithSoftWrap(enabled bool) *TextArea {
+	t.SoftWrap = enabled
+	return t
+}
+
+// Value returns the text
+func (t *TextArea) Value() string {
+	return t.Editor.Text()
+}
+
+// FindNext selects the next match of Search after the cursor
+func (t *TextArea) FindNext() bool {
+	found := t.Editor.FindNext(t.Search)
+	t.refresh(true)
+	return found
+}
+
+// ReplaceNext replaces the selected match of Search and selects the next
+func (t *TextArea) ReplaceNext(replacement string) bool {
+	replaced := t.Editor.Replace(t.Search, replacement)
+	t.changed()
+	return replaced
+}
+
+// ReplaceAll replaces every match of Search and returns how many there were
+func (t *TextArea) ReplaceAll(replacement string) int {
+	n := t.Editor.ReplaceAll(t.Search, replacement)
+	if n > 0 {
+		t.changed()
+	}
+	return n
+}
+
+// Paste inserts pasted text at every cursor, or one line at each when
+// there are as many lines as cursors
+func (t *TextArea) Paste(text string) {
+	text = pasteBreaks.Replace(text)
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if len(lines) < 2 || !t.Editor.InsertEach(lines) {
+		t.Editor.Insert(text)
+	}
+	t.changed()
+}
+
+// changed redraws after an edit and calls OnChange
+func (t *TextArea) changed() {
+	t.goals = nil
+	t.refresh(true)
+	if t.OnChange != nil {
+		t.OnChange(t)
+	}
+}
+
+// visualRow is a row of the wrapped text, as byte offsets
+type visualRow struct {
+	start, end int
+	line       int  // Index of the line it belongs to
+	first      bool // First row of its line
+	last       bool // Last row of its line
+}
+
+// gutterWidth returns the width of the line numbers and their gap
+func (t *TextArea) gutterWidth() int {
+	if !t.LineNumbers {
+		return 0
+	}
+	return len(strconv.Itoa(strings.Count(t.Editor.Text(), "\n")+1)) + 1
+}
+
+// textWidth is the number of cells for text, leaving the border, padding
+// and gutter
+func (t *TextArea) textWidth() int {
+	return max(1, t.Width-4-t.gutterWidth())
+}
+
+// viewRows is the number of rows shown
+func (t *TextArea) viewRows() int {
+	return max(1, t.Height-2)
+}
+
+// rows lays the text out in visual rows. Soft-wrapped rows keep a cell
+// free at the end for the cursor. The rows are kept until the text or the
+// wrap width changes.
+func (t *TextArea) rows() []visualRow {
+	text, width := t.Editor.Text(), 0
+	if t.SoftWrap {
+		width = max(1, t.textWidth()-1)
+	}
+	if t.rowsCache != nil && text == t.rowsText && width == t.rowsWidth {
+		return t.rowsCache
+	}
+
+	var rows []visualRow
+	offset := 0
+	for i, line := range strings.Split(text, "\n") {
+		segments := []string{line}
+		if width > 0 {
+			segments = renderer.WrapText(line, width)
+		}
+		for j, segment := range segments {
+			rows = append(rows, visualRow{
+				start: offset,
+				end:   offset + len(segment),
+				line:  i,
+				first: j == 0,
+				last:  j == len(segments)-1,
+			})
+			offset += len(segment)
+		}
+		offset++ // The line break
+	}
+	t.rowsCache, t.rowsText, t.rowsWidth = rows, text, width
+	return rows
+}
+
+// rowOf returns the index of the row showing offset. An offset where a line
+// wraps is at the start of the later row.
+func rowOf(rows []visualRow, offset int) int {
+	i := sort.Search(len(rows), func(i int) bool { return rows[i].start > offset })
+	return max(0, i-1)
+}
+
+// column returns the cell column of offset within its row
+func (t *TextArea) column(row visualRow, offset int) int {
+	return renderer.TextWidth(t.Editor.Text()[row.start:offset])
+}
+
+// offsetAt returns the offset of the grapheme cluster at a column of a
+// row, or the end of the row. A wrapped row ends before its last cluster,
+// since its end is shown at the start of the next row.
+func (t *TextArea) offsetAt(row visualRow, col int) int {
+	text := t.Editor.Text()
+	offset, w := row.start, 0
+	for _, g := range renderer.Graphemes(text[row.start:row.end]) {
+		w += renderer.TextWidth(g)
+		if w > col {
+			return offset
+		}
+		offset += len(g)
+	}
+	if !row.last && offset > row.start {
+		return t.Editor.PrevBoundary(offset)
+	}
+	return offset
+}
+
+// moveVertical moves every cursor by n rows, keeping its column
+func (t *TextArea) moveVertical(n int, extend bool) {
+	rows := t.rows()
+	selections := t.Editor.Selections()
+	if len(t.goals) != len(selections) {
+		t.goals = make([]int, len(selections))
+		for i, s := range selections {
+			t.goals[i] = t.column(rows[rowOf(rows, s.Head)], s.Head)
+		}
+	}
+
+	i := 0
+	t.Editor.MoveCursors(func(s editor.Selection) int {
+		goal := t.goals[i]
+		i++
+		target := rowOf(rows, s.Head) + n
+		switch {
+		case target < 0:
+			return 0
+		case target >= len(rows):
+			return t.Editor.Len()
+		}
+		return t.offsetAt(rows[target], goal)
+	}, extend)
+
+	// Cursors that merged lose their goals
+	if len(t.goals) != len(t.Editor.Selections()) {
+		t.goals = nil
+	}
+}
+
+// addCursorVertical adds a cursor n rows from the primary one
+func (t *TextArea) addCursorVertical(n int) {
+	rows := t.rows()
+	head := t.Editor.Primary().Head
+	r := rowOf(rows, head)
+	if target := r + n; target >= 0 && target < len(rows) {
+		t.Editor.AddSelection(editor.Cursor(t.offsetAt(rows[target], t.column(rows[r], head))))
+	}
+}
+
+// rowEdge returns the start or end of the row showing offset
+func (t *TextArea) rowEdge(offset int, end bool) int {
+	rows := t.rows()
+	row := rows[rowOf(rows, offset)]
+	if !end {
+		return row.start
+	}
+	if !row.last {
+		return t.Editor.PrevBoundary(row.end)
+	}
+	return row.end
+}
+
+// killLine returns where Ctrl+K deletes to: the end of the line, or past
+// the line break when already there
+func (t *TextArea) killLine(offset int) int {
+	if end := t.Editor.LineEnd(offset); end != offset {
+		return end
+	}
+	return offset + 1
+}
+
+// HandleKey edits or moves the cursors for a key press and reports whether
+// the key was used. Tab, unless TabSize is set, and Esc with one cursor are
+// left for the app.
+func (t *TextArea) HandleKey(key input.KeyEvent) bool {
+	if key.Action == input.KeyRelease {
+		return false
+	}
+	mod := key.Mod &^ (input.ModCapsLock | input.ModNumLock)
+	shift := mod&input.ModShift != 0
+	mod &^= input.ModShift
+	word := mod == input.ModAlt || mod == input.ModCtrl
+	e := t.Editor
+
+	vertical := false
+	edited := false
+	switch key.Key {
+	case input.KeyRune:
+		var ok bool
+		vertical, edited, ok = t.handleRune(key, mod, shift)
+		if !ok {
+			return false
+		}
+	case input.KeyLeft:
+		switch {
+		case word:
+			e.MoveCursors(func(s editor.Selection) int { return e.WordLeft(s.Head) }, shift)
+		case mod == 0:
+			t.moveHorizontal(-1, shift)
+		default:
+			return false
+		}
+	case input.KeyRight:
+		switch {
+		case word:
+			e.MoveCursors(func(s editor.Selection) int { return e.WordRight(s.Head) }, shift)
+		case mod == 0:
+			t.moveHorizontal(1, shift)
+		default:
+			return false
+		}
+	case input.KeyUp, input.KeyDown:
+		n := 1
+		if key.Key == input.KeyUp {
+			n = -1
+		}
+		switch mod {
+		case 0:
+			t.moveVertical(n, shift)
+			vertical = true
+		case input.ModCtrl | input.ModAlt:
+			t.addCursorVertical(n)
+		default:
+			return false
+		}
+	case input.KeyPageUp, input.KeyPageDown:
+		n := t.viewRows()
+		if key.Key == input.KeyPageUp {
+			n = -n
+		}
+		t.moveVertical(n, shift)
+		vertical = true
+	case input.KeyHome:
+		if mod == input.ModCtrl {
+			e.MoveCursors(func(editor.Selection) int { return 0 }, shift)
+		} else {
+			e.MoveCursors(func(s editor.Selection) int { return t.rowEdge(s.Head, false) }, shift)
+		}
+	case input.KeyEnd:
+		if mod == input.ModCtrl {
+			e.MoveCursors(func(editor.Selection) int { return e.Len() }, shift)
+		} else {
+			e.MoveCursors(func(s editor.Selection) int { return t.rowEdge(s.Head, true) }, shift)
+		}
+	case input.KeyBackspace:
+		if word {
+			e.Delete(e.WordLeft)
+		} else {
+			e.Delete(e.PrevBoundary)
+		}
+		edited = true
+	case input.KeyDelete:
+		if word {
+			e.Delete(e.WordRight)
+		} else {
+			e.Delete(e.NextBoundary)
+		}
+		edited = true
+	case input.KeyEnter:
+		if mod != 0 {
+			return false
+		}
+		e.Insert("\n")
+		edited = true
+	case input.KeyTab:
+		if t.TabSize <= 0 || mod != 0 || shift {
+			return false
+		}
+		e.Insert(strings.Repeat(" ", t.TabSize))
+		edited = true
+	case input.KeyEscape:
+		if !e.CollapseSelections() {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if edited {
+		t.changed()
+		return true
+	}
+	if !vertical {
+		t.goals = nil
+	}
+	t.refresh(true)
+	return true
+}
+
+// handleRune types a character or runs a binding
+func (t *TextArea) handleRune(key input.KeyEvent, mod input.Modifier, shift bool) (vertical, edited, ok bool) {
+	e := t.Editor
+	switch mod {
+	case 0:
+		text := key.Text
+		if text == "" {
+			r := key.Rune
+			switch {
+			case shift && key.ShiftedRune != 0:
+				r = key.ShiftedRune
+			case shift:
+				r = unicode.ToUpper(r)
+			}
+			if !unicode.IsPrint(r) && r != ' ' {
+				return false, false, false
+			}
+			text = string(r)
+		}
+		e.Insert(text)
+		return false, true, true
+
+	case input.ModCtrl:
+		switch unicode.ToLower(key.Rune) {
+		case 'a':
+			e.MoveCursors(func(s editor.Selection) int { return t.rowEdge(s.Head, false) }, shift)
+		case 'e':
+			e.MoveCursors(func(s editor.Selection) int { return t.rowEdge(s.Head, true) }, shift)
+		case 'b':
+			t.moveHorizontal(-1, shift)
+		case 'f':
+			t.moveHorizontal(1, shift)
+		case 'p':
+			t.moveVertical(-1, shift)
+			return true, false, true
+		case 'n':
+			t.moveVertical(1, shift)
+			return true, false, true
+		case 'h':
+			e.Delete(e.PrevBoundary)
+			return false, true, true
+		case 'd':
+			e.Delete(e.NextBoundary)
+			return false, true, true
+		case 'k':
+			e.Delete(t.killLine)
+			return false, true, true
+		case 'u':
+			e.Delete(e.LineStart)
+			return false, true, true
+		case 'w':
+			e.Delete(e.WordLeft)
+			return false, true, true
+		case 'z':
+			if shift {
+				return false, e.Redo(), true
+			}
+			return false, e.Undo(), true
+		case '_':
+			return false, e.Undo(), true
+		case 'y':
+			return false, e.Redo(), true
+		default:
+			return false, false, false
+		}
+		return false, false, true
+
+	case input.ModAlt:
+		switch unicode.ToLower(key.Rune) {
+		case 'b':
+			e.MoveCursors(func(s editor.Selection) int { return e.WordLeft(s.Head) }, shift)
+		case 'f':
+			e.MoveCursors(func(s editor.Selection) int { return e.WordRight(s.Head) }, shift)
+		case 'd':
+			e.Delete(e.WordRight)
+			return false, true, true
+		case 'n':
+			e.SelectNextMatch()
+		default:
+			return false, false, false
+		}
+		return false, false, true
+	}
+	return false, false, false
+}
+
+// moveHorizontal moves every cursor by one grapheme cluster. Without
+// extend, selections collapse to their edge in that direction instead.
+func (t *TextArea) moveHorizontal(n int, extend bool) {
+	e := t.Editor
+	e.MoveCursors(func(s editor.Selection) int {
+		switch {
+		case !s.Empty() && !extend && n < 0:
+			return s.Start()
+		case !s.Empty() && !extend:
+			return s.End()
+		case n < 0:
+			return e.PrevBoundary(s.Head)
+		}
+		return e.NextBoundary(s.Head)
+	}, extend)
+}
+
+// pointAt returns the offset under a cell relative to the field's node
+func (t *TextArea) pointAt(x, y int) int {
+	rows := t.rows()
+	r := max(0, min(t.top+y-1, len(rows)-1))
+	return t.offsetAt(rows[r], x-2-t.gutterWidth()+t.left)
+}
+
+// id returns the ID of the field's node
+func (t *TextArea) id() string {
+	if t.ID != "" {
+		return "textarea:" + t.ID
+	}
+	return fmt.Sprintf("textarea:%p", t)
+}
+
+// ToStyledNode converts the text area to a styled node. The node is redrawn
+// in place as it is edited, so the tree does not have to be rebuilt for
+// each key.
+func (t *TextArea) ToStyledNode() *renderer.StyledNode {
+	node := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionColumn,
+			Width:         layout.Px(float64(max(1, t.Width-4))),
+			Height:        layout.Px(float64(t.viewRows())),
+			Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(2), Bottom: layout.Px(1), Left: layout.Px(2)},
+			FlexShrink:    0,
+			AlignSelf:     layout.AlignItemsFlexStart, // Keep the width in a stretching column
+		},
+	}, nil)
+	node.Focusable = true
+	node.ID = t.id()
+
+	node.On(renderer.EventKeyDown, func(e *renderer.Event) {
+		if t.HandleKey(e.Key) {
+			e.PreventDefault()
+		}
+	})
+	node.On(renderer.EventPaste, func(e *renderer.Event) {
+		t.Paste(e.Text)
+		e.PreventDefault()
+	})
+	node.On(renderer.EventMouseDown, func(e *renderer.Event) {
+		offset := t.pointAt(e.LocalX, e.LocalY)
+		switch {
+		case e.Mod&input.ModAlt != 0:
+			t.Editor.AddSelection(editor.Cursor(offset))
+		case e.Mod&input.ModShift != 0:
+			p := t.Editor.Primary()
+			t.Editor.SetSelections(editor.Selection{Anchor: p.Anchor, Head: offset})
+		default:
+			t.Editor.SetSelections(editor.Cursor(offset))
+		}
+		t.Editor.BreakUndoGroup()
+		t.goals = nil
+		t.refresh(true)
+	})
+	node.On(renderer.EventDrag, func(e *renderer.Event) {
+		p := t.Editor.Primary()
+		t.Editor.SetSelections(editor.Selection{Anchor: p.Anchor, Head: t.pointAt(e.LocalX, e.LocalY)})
+		t.refresh(true)
+	})
+	node.On(renderer.EventWheel, func(e *renderer.Event) {
+		switch e.Button {
+		case input.MouseWheelUp:
+			t.top -= 3
+		case input.MouseWheelDown:
+			t.top += 3
+		default:
+			return
+		}
+		t.refresh(false)
+	})
+
+	t.node = node
+	t.refresh(true)
+	return node
+}
+
+// cellKind is how a grapheme cluster is drawn
+type cellKind int
+
+const (
+	cellText cellKind = iota
+	cellMatch
+	cellSelected
+)
+
+// refresh redraws the editor's node. With follow it scrolls to keep the
+// primary cursor in view.
+func (t *TextArea) refresh(follow bool) {
+	if t.node == nil {
+		return
+	}
+	rows := t.rows()
+	view := t.viewRows()
+	head := t.Editor.Primary().Head
+	cursorRow := rowOf(rows, head)
+	cursorCol := t.column(rows[cursorRow], head)
+
+	if follow {
+		t.top = min(t.top, cursorRow)
+		t.top = max(t.top, cursorRow-view+1)
+		if !t.SoftWrap {
+			t.left = min(t.left, cursorCol)
+			t.left = max(t.left, cursorCol-t.textWidth()+1)
+		} else {
+			t.left = 0
+		}
+	}
+	t.top = max(0, min(t.top, len(rows)-view))
+
+	style := &renderer.Style{BorderColor: t.BorderColor, Overflow: renderer.OverflowHidden}
+	style.WithBorder(renderer.RoundedBorder)
+	t.node.Style = style
+	t.node.Children, t.node.Node.Children = nil, nil
+
+	t.node.Cursor = nil
+	if cursorRow >= t.top && cursorRow < t.top+view {
+		t.node.Cursor = &renderer.Cursor{
+			X:     1 + t.gutterWidth() + cursorCol - t.left,
+			Y:     cursorRow - t.top,
+			Shape: t.CursorShape,
+		}
+	}
+
+	text := t.Editor.Text()
+	selections := t.Editor.Selections()
+	primary := t.Editor.Primary()
+	var matches []int
+	if t.Search != "" {
+		matches = t.Editor.Matches(t.Search)
+	}
+	kindAt := func(offset int) cellKind {
+		for _, s := range selections {
+			if offset >= s.Start() && offset < s.End() {
+				return cellSelected
+			}
+		}
+		for _, m := range matches {
+			if offset >= m && offset < m+len(t.Search) {
+				return cellMatch
+			}
+		}
+		return cellText
+	}
+	// Cursors other than the primary one are drawn as reversed cells
+	secondary := map[int]bool{}
+	for _, s := range selections {
+		if s != primary {
+			secondary[s.Head] = true
+		}
+	}
+
+	digits := t.gutterWidth() - 1
+	for r := t.top; r < min(len(rows), t.top+view); r++ {
+		row := rows[r]
+		rowNode := renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{
+				Display:       layout.DisplayFlex,
+				FlexDirection: layout.FlexDirectionRow,
+				Height:        layout.Px(1),
+				FlexShrink:    0,
+			},
+		}, nil)
+		t.node.AddChild(rowNode)
+
+		if digits > 0 {
+			number := strings.Repeat(" ", digits+1)
+			if row.first {
+				number = fmt.Sprintf("%*d ", digits, row.line+1)
+			}
+			addInputText(rowNode, number, &renderer.Style{Foreground: t.LineNumberColor})
+		}
+
+		if text == "" && t.Placeholder != "" {
+			addInputText(rowNode, t.Placeholder, &renderer.Style{Foreground: t.PlaceholderColor})
+			continue
+		}
+
+		// Group the row's clusters into runs drawn alike
+		var run strings.Builder
+		runKind, runCursor := cellText, false
+		flush := func() {
+			if run.Len() == 0 {
+				return
+			}
+			s := &renderer.Style{Foreground: t.Foreground, Reverse: runKind == cellSelected || runCursor}
+			if runKind == cellMatch {
+				s.Background = t.MatchColor
+			}
+			addInputText(rowNode, run.String(), s)
+			run.Reset()
+		}
+
+		offset, col := row.start, 0
+		for _, g := range renderer.Graphemes(text[row.start:row.end]) {
+			w := renderer.TextWidth(g)
+			if col >= t.left {
+				kind, cursor := kindAt(offset), secondary[offset]
+				if kind != runKind || cursor != runCursor {
+					flush()
+					runKind, runCursor = kind, cursor
+				}
+				run.WriteString(g)
+			}
+			offset += len(g)
+			col += w
+		}
+		flush()
+
+		// A cursor at the end of a line has no cluster to reverse
+		if row.last && secondary[row.end] && col >= t.left {
+			addInputText(rowNode, " ", &renderer.Style{Reverse: true})
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SCKelemen/cli/editor"
+	"github.com/SCKelemen/cli/input"
+)
+
+func TestTextAreaSoftWrapRows(t *testing.T) {
+	// Rows of seven cells, keeping one free for the cursor
+	ta := NewTextArea("hello world foo\nab", 12, 6)
+	rows := ta.rows()
+
+	expected := []visualRow{
+		{start: 0, end: 7, line: 0, first: true},
+		{start: 7, end: 14, line: 0},
+		{start: 14, end: 15, line: 0, last: true},
+		{start: 16, end: 18, line: 1, first: true, last: true},
+	}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Fatalf("Expected rows %+v, got %+v", expected, rows)
+	}
+	if rowOf(rows, 7) != 1 || rowOf(rows, 6) != 0 || rowOf(rows, 17) != 3 {
+		t.Error("Expected a wrap offset to belong to the later row")
+	}
+
+	tests := []struct {
+		name     string
+		got      int
+		expected int
+	}{
+		{"OffsetAtColumn", ta.offsetAt(rows[0], 3), 3},
+		{"OffsetPastWrappedRow", ta.offsetAt(rows[0], 20), 6},
+		{"OffsetPastLastRow", ta.offsetAt(rows[2], 20), 15},
+		{"RowStart", ta.rowEdge(8, false), 7},
+		{"WrappedRowEnd", ta.rowEdge(8, true), 13},
+		{"LineEnd", ta.rowEdge(14, true), 15},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.expected {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.expected, tt.got)
+		}
+	}
+
+	ta.SoftWrap = false
+	if rows := ta.rows(); len(rows) != 2 || rows[0].end != 15 {
+		t.Errorf("Expected one row per line without soft wrap, got %+v", rows)
+	}
+}
+
+func TestTextAreaVerticalGoal(t *testing.T) {
+	ta := NewTextArea("abcdef\nab\nabcdef", 20, 6).WithSoftWrap(false)
+	ta.ToStyledNode()
+	ta.Editor.SetSelections(editor.Cursor(5))
+
+	// The short middle line does not lose the column
+	for _, tt := range []struct {
+		key    input.Key
+		offset int
+	}{
+		{input.KeyDown, 9},
+		{input.KeyDown, 15},
+		{input.KeyUp, 9},
+		{input.KeyUp, 5},
+	} {
+		ta.HandleKey(input.KeyEvent{Key: tt.key})
+		if head := ta.Editor.Primary().Head; head != tt.offset {
+			t.Errorf("%v: expected the cursor at %d, got %d", tt.key, tt.offset, head)
+		}
+	}
+
+	// A horizontal move sets a new goal
+	ta.HandleKey(input.KeyEvent{Key: input.KeyLeft})
+	ta.HandleKey(input.KeyEvent{Key: input.KeyDown})
+	ta.HandleKey(input.KeyEvent{Key: input.KeyDown})
+	if head := ta.Editor.Primary().Head; head != 14 {
+		t.Errorf("Expected the cursor at column 4 of the last line, got %d", head)
+	}
+}
+
+func TestTextAreaPaste(t *testing.T) {
+	ta := NewTextArea("one\ntwo\nthree", 20, 6)
+	ta.ToStyledNode()
+	ta.Editor.SetSelections(editor.Cursor(0), editor.Cursor(4), editor.Cursor(8))
+
+	ta.Paste("1. \r\n2. \r\n3. \r\n")
+	if got := ta.Value(); got != "1. one\n2. two\n3. three" {
+		t.Errorf("Expected one pasted line at each cursor, got %q", got)
+	}
+
+	ta.Editor.SetSelections(editor.Cursor(0), editor.Cursor(7))
+	ta.Paste("a\nb\nc")
+	if got := ta.Value(); got != "a\nb\nc1. one\na\nb\nc2. two\n3. three" {
+		t.Errorf("Expected the whole paste at each cursor, got %q", got)
+	}
+}
+
+func TestTextAreaCursorKeys(t *testing.T) {
+	ta := NewTextArea("abc\nabc\nabc", 20, 6)
+	ta.ToStyledNode()
+	ta.Editor.SetSelections(editor.Cursor(1))
+	ctrlAlt := input.ModCtrl | input.ModAlt
+
+	// Each new cursor becomes primary; adding one onto the middle cursor
+	// makes that one primary again
+	ta.HandleKey(input.KeyEvent{Key: input.KeyDown, Mod: ctrlAlt})
+	ta.HandleKey(input.KeyEvent{Key: input.KeyDown, Mod: ctrlAlt | input.ModCapsLock})
+	ta.HandleKey(input.KeyEvent{Key: input.KeyUp, Mod: ctrlAlt})
+	expected := []editor.Selection{editor.Cursor(1), editor.Cursor(5), editor.Cursor(9)}
+	if got := ta.Editor.Selections(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected cursors %v, got %v", expected, got)
+	}
+
+	ta.HandleKey(input.KeyEvent{Key: input.KeyRune, Rune: 'X'})
+	if got := ta.Value(); got != "aXbc\naXbc\naXbc" {
+		t.Errorf("Expected typing at every cursor, got %q", got)
+	}
+
+	if !ta.HandleKey(input.KeyEvent{Key: input.KeyEscape}) {
+		t.Error("Expected Esc to be used with several cursors")
+	}
+	if got := ta.Editor.Selections(); len(got) != 1 || got[0] != editor.Cursor(7) {
+		t.Errorf("Expected only the primary cursor to stay, got %v", got)
+	}
+	if ta.HandleKey(input.KeyEvent{Key: input.KeyEscape}) {
+		t.Error("Expected Esc with one cursor to be left for the app")
+	}
+}
@@ -0,0 +1,575 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// lineBreaks turns line breaks in inserted text into spaces
+var lineBreaks = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ")
+
+// TextInput is a single-line text field. It edits by grapheme cluster, so
+// an emoji or a letter with combining marks moves and deletes as one
+// character, and scrolls horizontally when the text is wider than the
+// field. While focused it shows the terminal cursor at the insertion point.
+//
+// Besides the arrow, Home, End, Backspace and Delete keys it takes the
+// Emacs bindings: Ctrl+A/E start and end, Ctrl+B/F and Alt+B/F back and
+// forward by character and word, Ctrl+D and Ctrl+H delete, Ctrl+K and
+// Ctrl+U kill to the end and start, Ctrl+W and Alt+D kill a word and
+// Ctrl+Y yanks the last kill. Alt or Ctrl with the arrows, Backspace and
+// Delete work by word, and Shift with the movement keys selects.
+type TextInput struct {
+	Placeholder string // Shown while the field is empty
+	Prompt      string // Shown before the text
+	Width       int    // Width in cells, including the border
+
+	// Mask replaces every character on screen, for passwords. Word
+	// movement then jumps to the start or end, so it cannot reveal where
+	// words break.
+	Mask rune
+
+	// MaxLength limits the text to this many characters; 0 is unlimited
+	MaxLength int
+
+	// Validate checks the text after every change. Its result is kept in
+	// Err, and the border shows ErrorColor while it is not nil.
+	Validate func(value string) error
+	Err      error
+
+	// OnChange is called after the text changes, and OnSubmit when Enter
+	// is pressed
+	OnChange func(t *TextInput)
+	OnSubmit func(t *TextInput)
+
+	// ID identifies the field across rebuilt trees so it keeps focus.
+	// A name unique to this input is used when empty.
+	ID string
+
+	CursorShape      renderer.CursorShape
+	Foreground       *color.Color
+	PromptColor      *color.Color
+	PlaceholderColor *color.Color
+	BorderColor      *color.Color
+	ErrorColor       *color.Color
+
+	graphemes []string
+	cursor    int    // Insertion point, in graphemes
+	anchor    int    // Other end of the selection, or -1
+	offset    int    // First grapheme shown
+	killed    string // Text removed by the last kill, for yank
+	node      *renderer.StyledNode
+}
+
+// NewTextInput creates an empty text input
+func NewTextInput(placeholder string, width int) *TextInput {
+	fg, _ := color.ParseColor("#FAFAFA")
+	prompt, _ := color.ParseColor("#7D56F4")
+	ph, _ := color.ParseColor("#5A5A5A")
+	border, _ := color.ParseColor("#5A5A5A")
+	errColor, _ := color.ParseColor("#FF5F87")
+	return &TextInput{
+		Placeholder:      placeholder,
+		Prompt:           "> ",
+		Width:            width,
+		CursorShape:      renderer.CursorBlinkingBar,
+		Foreground:       &fg,
+		PromptColor:      &prompt,
+		PlaceholderColor: &ph,
+		BorderColor:      &border,
+		ErrorColor:       &errColor,
+		anchor:           -1,
+	}
+}
+
+// WithMask hides the text behind mask, such as '•' for a password
+func (t *TextInput) WithMask(mask rune) *TextInput {
+	t.Mask = mask
+	return t
+}
+
+// WithMaxLength limits the text to n characters
+func (t *TextInput) WithMaxLength(n int) *TextInput {
+	t.MaxLength = n
+	return t
+}
+
+// WithValidate sets the validation hook
+func (t *TextInput) WithValidate(validate func(value string) error) *TextInput {
+	t.Validate = validate
+	t.Err = validate(t.Value())
+	return t
+}
+
+// Value returns the text
+func (t *TextInput) Value() string {
+	return strings.Join(t.graphemes, "")
+}
+
+// SetValue replaces the text and moves the cursor to its end. It does not
+// call OnChange.
+func (t *TextInput) SetValue(value string) {
+	t.graphemes = t.limit(renderer.Graphemes(lineBreaks.Replace(value)), len(t.graphemes))
+	t.cursor, t.anchor = len(t.graphemes), -1
+	if t.Validate != nil {
+		t.Err = t.Validate(t.Value())
+	}
+	t.refresh()
+}
+
+// Cursor returns the insertion point, in characters from the start
+func (t *TextInput) Cursor() int {
+	return t.cursor
+}
+
+// SetCursor moves the insertion point and clears the selection
+func (t *TextInput) SetCursor(pos int) {
+	t.moveTo(pos, false)
+	t.refresh()
+}
+
+// Selection returns the selected range of characters, start before end,
+// and false when nothing is selected
+func (t *TextInput) Selection() (start, end int, ok bool) {
+	if t.anchor < 0 || t.anchor == t.cursor {
+		return t.cursor, t.cursor, false
+	}
+	return min(t.anchor, t.cursor), max(t.anchor, t.cursor), true
+}
+
+// SelectedText returns the selected text
+func (t *TextInput) SelectedText() string {
+	start, end, _ := t.Selection()
+	return strings.Join(t.graphemes[start:end], "")
+}
+
+// Insert types text at the cursor, replacing any selection. Line breaks
+// become spaces and text past MaxLength is dropped.
+func (t *TextInput) Insert(text string) {
+	start, end, _ := t.Selection()
+	t.replace(start, end, text)
+	t.refresh()
+}
+
+// HandleKey edits or moves the cursor for a key press and reports whether
+// the key was used. Keys it does not use, such as Tab and the up and down
+// arrows, are left for focus navigation.
+func (t *TextInput) HandleKey(key input.KeyEvent) bool {
+	if key.Action == input.KeyRelease {
+		return false
+	}
+	mod := key.Mod &^ (input.ModCapsLock | input.ModNumLock)
+	shift := mod&input.ModShift != 0
+	mod &^= input.ModShift
+	word := mod == input.ModAlt || mod == input.ModCtrl
+
+	switch key.Key {
+	case input.KeyRune:
+		return t.handleRune(key, mod, shift)
+	case input.KeyLeft:
+		switch {
+		case word:
+			t.moveTo(t.wordLeft(t.cursor), shift)
+		case mod == 0:
+			t.moveBy(-1, shift)
+		default:
+			return false
+		}
+	case input.KeyRight:
+		switch {
+		case word:
+			t.moveTo(t.wordRight(t.cursor), shift)
+		case mod == 0:
+			t.moveBy(1, shift)
+		default:
+			return false
+		}
+	case input.KeyHome:
+		t.moveTo(0, shift)
+	case input.KeyEnd:
+		t.moveTo(len(t.graphemes), shift)
+	case input.KeyBackspace:
+		if word {
+			t.kill(t.wordLeft(t.cursor), t.cursor)
+		} else {
+			t.deleteBy(-1)
+		}
+	case input.KeyDelete:
+		if word {
+			t.kill(t.cursor, t.wordRight(t.cursor))
+		} else {
+			t.deleteBy(1)
+		}
+	case input.KeyEnter:
+		if mod != 0 || shift {
+			return false
+		}
+		if t.OnSubmit != nil {
+			t.OnSubmit(t)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// handleRune types a character or runs an Emacs binding
+func (t *TextInput) handleRune(key input.KeyEvent, mod input.Modifier, shift bool) bool {
+	switch mod {
+	case 0:
+		text := key.Text
+		if text == "" {
+			r := key.Rune
+			switch {
+			case shift && key.ShiftedRune != 0:
+				r = key.ShiftedRune
+			case shift:
+				r = unicode.ToUpper(r)
+			}
+			if !unicode.IsPrint(r) && r != ' ' {
+				return false
+			}
+			text = string(r)
+		}
+		t.Insert(text)
+		return true
+
+	case input.ModCtrl:
+		switch unicode.ToLower(key.Rune) {
+		case 'a':
+			t.moveTo(0, shift)
+		case 'e':
+			t.moveTo(len(t.graphemes), shift)
+		case 'b':
+			t.moveBy(-1, shift)
+		case 'f':
+			t.moveBy(1, shift)
+		case 'h':
+			t.deleteBy(-1)
+		case 'd':
+			t.deleteBy(1)
+		case 'k':
+			t.kill(t.cursor, len(t.graphemes))
+		case 'u':
+			t.kill(0, t.cursor)
+		case 'w':
+			t.kill(t.wordLeft(t.cursor), t.cursor)
+		case 'y':
+			t.Insert(t.killed)
+		default:
+			return false
+		}
+		return true
+
+	case input.ModAlt:
+		switch unicode.ToLower(key.Rune) {
+		case 'b':
+			t.moveTo(t.wordLeft(t.cursor), shift)
+		case 'f':
+			t.moveTo(t.wordRight(t.cursor), shift)
+		case 'd':
+			t.kill(t.cursor, t.wordRight(t.cursor))
+		default:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// moveTo moves the cursor, extending the selection when extend is set and
+// clearing it otherwise
+func (t *TextInput) moveTo(pos int, extend bool) {
+	pos = max(0, min(pos, len(t.graphemes)))
+	if extend {
+		if t.anchor < 0 {
+			t.anchor = t.cursor
+		}
+	} else {
+		t.anchor = -1
+	}
+	t.cursor = pos
+}
+
+// moveBy moves the cursor by n characters. Without extend, a selection
+// collapses to its edge in that direction instead.
+func (t *TextInput) moveBy(n int, extend bool) {
+	if start, end, ok := t.Selection(); ok && !extend {
+		if n < 0 {
+			t.moveTo(start, false)
+		} else {
+			t.moveTo(end, false)
+		}
+		return
+	}
+	t.moveTo(t.cursor+n, extend)
+}
+
+// deleteBy deletes the selection, or n characters from the cursor
+func (t *TextInput) deleteBy(n int) {
+	if start, end, ok := t.Selection(); ok {
+		t.replace(start, end, "")
+		return
+	}
+	other := max(0, min(t.cursor+n, len(t.graphemes)))
+	t.replace(min(t.cursor, other), max(t.cursor, other), "")
+}
+
+// kill deletes a range, keeping it for yank
+func (t *TextInput) kill(start, end int) {
+	if start >= end {
+		return
+	}
+	t.killed = strings.Join(t.graphemes[start:end], "")
+	t.replace(start, end, "")
+}
+
+// replace swaps the characters from start to end for text and moves the
+// cursor after it
+func (t *TextInput) replace(start, end int, text string) {
+	inserted := t.limit(renderer.Graphemes(lineBreaks.Replace(text)), end-start)
+	if start == end && len(inserted) == 0 {
+		t.anchor = -1
+		return
+	}
+
+	// Segment again, since an inserted combining mark joins the character
+	// before it
+	before := strings.Join(t.graphemes[:start], "") + strings.Join(inserted, "")
+	after := strings.Join(t.graphemes[end:], "")
+	t.graphemes = renderer.Graphemes(before + after)
+	t.cursor, t.anchor = len(renderer.Graphemes(before)), -1
+
+	if t.Validate != nil {
+		t.Err = t.Validate(t.Value())
+	}
+	if t.OnChange != nil {
+		t.OnChange(t)
+	}
+}
+
+// limit drops inserted characters past MaxLength, given that replaced
+// characters are removed
+func (t *TextInput) limit(inserted []string, replaced int) []string {
+	if t.MaxLength <= 0 {
+		return inserted
+	}
+	room := max(0, t.MaxLength-(len(t.graphemes)-replaced))
+	return inserted[:min(len(inserted), room)]
+}
+
+// isWordChar reports whether a character belongs to a word
+func isWordChar(grapheme string) bool {
+	r, _ := utf8.DecodeRuneInString(grapheme)
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// wordLeft returns the start of the word before pos
+func (t *TextInput) wordLeft(pos int) int {
+	if t.Mask != 0 {
+		return 0
+	}
+	for pos > 0 && !isWordChar(t.graphemes[pos-1]) {
+		pos--
+	}
+	for pos > 0 && isWordChar(t.graphemes[pos-1]) {
+		pos--
+	}
+	return pos
+}
+
+// wordRight returns the end of the word after pos
+func (t *TextInput) wordRight(pos int) int {
+	if t.Mask != 0 {
+		return len(t.graphemes)
+	}
+	for pos < len(t.graphemes) && !isWordChar(t.graphemes[pos]) {
+		pos++
+	}
+	for pos < len(t.graphemes) && isWordChar(t.graphemes[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// shown returns a character as drawn, masked if Mask is set
+func (t *TextInput) shown(i int) string {
+	if t.Mask != 0 {
+		return string(t.Mask)
+	}
+	return t.graphemes[i]
+}
+
+// cells returns the width of the characters from start to end as drawn
+func (t *TextInput) cells(start, end int) int {
+	w := 0
+	for i := start; i < end; i++ {
+		w += renderer.TextWidth(t.shown(i))
+	}
+	return w
+}
+
+// textWidth is the number of cells for text, leaving the border, padding
+// and prompt
+func (t *TextInput) textWidth() int {
+	return max(1, t.Width-4-renderer.TextWidth(t.Prompt))
+}
+
+// scroll moves the first shown character so the cursor, with a cell after
+// it, stays in view, showing as much text as fits
+func (t *TextInput) scroll() {
+	avail := t.textWidth()
+	t.offset = min(t.offset, t.cursor)
+	for t.offset < t.cursor && t.cells(t.offset, t.cursor)+1 > avail {
+		t.offset++
+	}
+	for t.offset > 0 && t.cells(t.offset-1, len(t.graphemes))+1 <= avail {
+		t.offset--
+	}
+}
+
+// visibleEnd returns the end of the characters that fit from the offset
+func (t *TextInput) visibleEnd() int {
+	avail, w := t.textWidth(), 0
+	end := t.offset
+	for end < len(t.graphemes) {
+		w += renderer.TextWidth(t.shown(end))
+		if w > avail {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// indexAt returns the character under a cell of the text, counted from
+// the first shown character
+func (t *TextInput) indexAt(col int) int {
+	w := 0
+	for i := t.offset; i < t.visibleEnd(); i++ {
+		w += renderer.TextWidth(t.shown(i))
+		if w > col {
+			return i
+		}
+	}
+	return t.visibleEnd()
+}
+
+// id returns the ID of the field's node
+func (t *TextInput) id() string {
+	if t.ID != "" {
+		return "textinput:" + t.ID
+	}
+	return fmt.Sprintf("textinput:%p", t)
+}
+
+// ToStyledNode converts the text input to a styled node. The node is
+// redrawn in place as it is edited, so the tree does not have to be
+// rebuilt for each key.
+func (t *TextInput) ToStyledNode() *renderer.StyledNode {
+	node := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionRow,
+			Width:         layout.Px(float64(max(1, t.Width-4))),
+			Height:        layout.Px(1),
+			Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(2), Bottom: layout.Px(1), Left: layout.Px(2)},
+			FlexShrink:    0,
+			AlignSelf:     layout.AlignItemsFlexStart, // Keep the width in a stretching column
+		},
+	}, nil)
+	node.Focusable = true
+	node.ID = t.id()
+
+	node.On(renderer.EventKeyDown, func(e *renderer.Event) {
+		if t.HandleKey(e.Key) {
+			t.refresh()
+			e.PreventDefault()
+		}
+	})
+	node.On(renderer.EventPaste, func(e *renderer.Event) {
+		t.Insert(e.Text)
+		e.PreventDefault()
+	})
+	node.On(renderer.EventMouseDown, func(e *renderer.Event) {
+		t.moveTo(t.indexAt(e.LocalX-2-renderer.TextWidth(t.Prompt)), e.Mod&input.ModShift != 0)
+		t.refresh()
+	})
+	node.On(renderer.EventDrag, func(e *renderer.Event) {
+		t.moveTo(t.indexAt(e.LocalX-2-renderer.TextWidth(t.Prompt)), true)
+		t.refresh()
+	})
+
+	t.node = node
+	t.refresh()
+	return node
+}
+
+// refresh redraws the field's node for the current text and cursor
+func (t *TextInput) refresh() {
+	if t.node == nil {
+		return
+	}
+	t.scroll()
+
+	style := &renderer.Style{BorderColor: t.BorderColor, Overflow: renderer.OverflowHidden}
+	if t.Err != nil {
+		style.BorderColor = t.ErrorColor
+	}
+	style.WithBorder(renderer.RoundedBorder)
+	t.node.Style = style
+	t.node.Children, t.node.Node.Children = nil, nil
+
+	promptWidth := renderer.TextWidth(t.Prompt)
+	t.node.Cursor = &renderer.Cursor{
+		X:     1 + promptWidth + t.cells(t.offset, t.cursor),
+		Shape: t.CursorShape,
+	}
+
+	if t.Prompt != "" {
+		addInputText(t.node, t.Prompt, &renderer.Style{Foreground: t.PromptColor})
+	}
+	if len(t.graphemes) == 0 {
+		if t.Placeholder != "" {
+			addInputText(t.node, t.Placeholder, &renderer.Style{Foreground: t.PlaceholderColor})
+		}
+		return
+	}
+
+	// Draw the shown text in runs before, inside and after the selection
+	end := t.visibleEnd()
+	selStart, selEnd, _ := t.Selection()
+	bounds := []int{t.offset, max(t.offset, min(selStart, end)), max(t.offset, min(selEnd, end)), end}
+	for i := 0; i < 3; i++ {
+		if bounds[i] >= bounds[i+1] {
+			continue
+		}
+		var run strings.Builder
+		for j := bounds[i]; j < bounds[i+1]; j++ {
+			run.WriteString(t.shown(j))
+		}
+		addInputText(t.node, run.String(), &renderer.Style{Foreground: t.Foreground, Reverse: i == 1})
+	}
+}
+
+// addInputText appends a one-row text node sized to its content
+func addInputText(row *renderer.StyledNode, content string, style *renderer.Style) {
+	style.WhiteSpace = renderer.WhiteSpacePre
+	node := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:    layout.DisplayBlock,
+			Width:      layout.Px(float64(renderer.TextWidth(content))),
+			Height:     layout.Px(1),
+			FlexShrink: 0,
+		},
+	}, style)
+	node.Content = content
+	row.AddChild(node)
+}
@@ -0,0 +1,252 @@
+package components
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+)
+
+func TestTextInputSetValueMaxLength(t *testing.T) {
+	input := NewTextInput("", 20).WithMaxLength(5)
+
+	input.SetValue("abc")
+	input.SetValue("vwxyz")
+	if got := input.Value(); got != "vwxyz" {
+		t.Errorf("Expected SetValue to replace the whole value, got %q", got)
+	}
+	input.SetValue("abcdefg")
+	if got := input.Value(); got != "abcde" {
+		t.Errorf("Expected the value to be cut at MaxLength, got %q", got)
+	}
+}
+
+func runeKey(r rune, mod input.Modifier) input.KeyEvent {
+	return input.KeyEvent{Key: input.KeyRune, Rune: r, Mod: mod}
+}
+
+// shownText returns the text drawn after the prompt
+func shownText(input *TextInput) string {
+	var text strings.Builder
+	for _, child := range input.node.Children[1:] {
+		text.WriteString(child.Content)
+	}
+	return text.String()
+}
+
+func TestTextInputGraphemes(t *testing.T) {
+	field := NewTextInput("", 20)
+	field.SetValue("é👍🏽x")
+	if field.Cursor() != 3 {
+		t.Fatalf("Expected three characters, got cursor %d", field.Cursor())
+	}
+
+	field.HandleKey(input.KeyEvent{Key: input.KeyLeft})
+	field.HandleKey(input.KeyEvent{Key: input.KeyLeft})
+	if field.Cursor() != 1 {
+		t.Errorf("Expected to move over the emoji as one, got cursor %d", field.Cursor())
+	}
+	field.HandleKey(input.KeyEvent{Key: input.KeyDelete})
+	if got := field.Value(); got != "éx" {
+		t.Errorf("Expected the emoji deleted whole, got %q", got)
+	}
+	field.HandleKey(input.KeyEvent{Key: input.KeyBackspace})
+	if got := field.Value(); got != "x" {
+		t.Errorf("Expected the accented letter deleted whole, got %q", got)
+	}
+
+	// A combining mark typed after a letter joins it
+	field.HandleKey(input.KeyEvent{Key: input.KeyEnd})
+	field.Insert("́")
+	if got := field.Value(); got != "x́" || field.Cursor() != 1 {
+		t.Errorf("Expected the mark to join x, got %q at %d", got, field.Cursor())
+	}
+}
+
+func TestTextInputWords(t *testing.T) {
+	field := NewTextInput("", 30)
+	field.SetValue("foo bar_baz, qux")
+
+	tests := []struct {
+		key    input.KeyEvent
+		cursor int
+	}{
+		{input.KeyEvent{Key: input.KeyLeft, Mod: input.ModCtrl}, 13},
+		{runeKey('b', input.ModAlt), 4},
+		{input.KeyEvent{Key: input.KeyLeft, Mod: input.ModAlt}, 0},
+		{runeKey('f', input.ModAlt), 3},
+		{input.KeyEvent{Key: input.KeyRight, Mod: input.ModCtrl}, 11},
+	}
+	for _, tt := range tests {
+		field.HandleKey(tt.key)
+		if field.Cursor() != tt.cursor {
+			t.Errorf("%v: expected cursor %d, got %d", tt.key, tt.cursor, field.Cursor())
+		}
+	}
+
+	// A masked field does not reveal where words break
+	field.WithMask('*')
+	field.HandleKey(runeKey('b', input.ModAlt))
+	if field.Cursor() != 0 {
+		t.Errorf("Expected a masked word jump to the start, got %d", field.Cursor())
+	}
+}
+
+func TestTextInputSelection(t *testing.T) {
+	field := NewTextInput("", 20)
+	field.SetValue("hello world")
+
+	for i := 0; i < 5; i++ {
+		field.HandleKey(input.KeyEvent{Key: input.KeyLeft, Mod: input.ModShift})
+	}
+	if start, end, ok := field.Selection(); !ok || start != 6 || end != 11 || field.SelectedText() != "world" {
+		t.Fatalf("Expected world selected, got %d-%d %q", start, end, field.SelectedText())
+	}
+
+	field.HandleKey(runeKey('a', input.ModCtrl|input.ModShift))
+	if field.SelectedText() != "hello world" {
+		t.Errorf("Expected Shift+Ctrl+A to extend to the start, got %q", field.SelectedText())
+	}
+	field.HandleKey(input.KeyEvent{Key: input.KeyRight})
+	if _, _, ok := field.Selection(); ok || field.Cursor() != 11 {
+		t.Errorf("Expected Right to collapse to the end, got cursor %d", field.Cursor())
+	}
+
+	field.HandleKey(input.KeyEvent{Key: input.KeyLeft, Mod: input.ModShift | input.ModCtrl})
+	field.HandleKey(runeKey('x', 0))
+	if got := field.Value(); got != "hello x" {
+		t.Errorf("Expected typing to replace the selection, got %q", got)
+	}
+}
+
+func TestTextInputKill(t *testing.T) {
+	field := NewTextInput("", 30)
+	field.SetValue("one two three")
+
+	tests := []struct {
+		name  string
+		key   input.KeyEvent
+		value string
+	}{
+		{"KillWord", runeKey('w', input.ModCtrl), "one two "},
+		{"Yank", runeKey('y', input.ModCtrl), "one two three"},
+		{"KillToStart", runeKey('u', input.ModCtrl), ""},
+		{"YankLine", runeKey('y', input.ModCtrl), "one two three"},
+		{"Start", runeKey('a', input.ModCtrl), "one two three"},
+		{"KillWordForward", runeKey('d', input.ModAlt), " two three"},
+		{"KillToEnd", runeKey('k', input.ModCtrl), ""},
+		{"YankEnd", runeKey('y', input.ModCtrl), " two three"},
+		{"DeleteBack", runeKey('h', input.ModCtrl), " two thre"},
+		{"Back", runeKey('b', input.ModCtrl), " two thre"},
+		{"DeleteForward", runeKey('d', input.ModCtrl), " two thr"},
+	}
+	for _, tt := range tests {
+		if !field.HandleKey(tt.key) {
+			t.Errorf("%s: expected the key to be used", tt.name)
+		}
+		if got := field.Value(); got != tt.value {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.value, got)
+		}
+	}
+}
+
+func TestTextInputMask(t *testing.T) {
+	field := NewTextInput("", 20).WithMask('*')
+	field.ToStyledNode()
+	field.Insert("s3cret")
+
+	if got := shownText(field); got != "******" {
+		t.Errorf("Expected the text masked, got %q", got)
+	}
+	if field.Value() != "s3cret" {
+		t.Errorf("Expected the value kept, got %q", field.Value())
+	}
+}
+
+func TestTextInputValidate(t *testing.T) {
+	changes := 0
+	field := NewTextInput("", 20).WithValidate(func(value string) error {
+		if value == "" {
+			return errors.New("required")
+		}
+		return nil
+	})
+	field.OnChange = func(*TextInput) { changes++ }
+	node := field.ToStyledNode()
+
+	if field.Err == nil || node.Style.BorderColor != field.ErrorColor {
+		t.Error("Expected an empty field to be invalid with the error border")
+	}
+	field.HandleKey(runeKey('a', 0))
+	if field.Err != nil || node.Style.BorderColor != field.BorderColor {
+		t.Errorf("Expected the field to be valid after typing, got %v", field.Err)
+	}
+	field.HandleKey(input.KeyEvent{Key: input.KeyBackspace})
+	if field.Err == nil {
+		t.Error("Expected the field to be invalid once emptied")
+	}
+	if changes != 2 {
+		t.Errorf("Expected OnChange for each edit, got %d calls", changes)
+	}
+
+	field.SetValue("x")
+	if field.Err != nil || changes != 2 {
+		t.Errorf("Expected SetValue to validate without OnChange, got %v after %d calls", field.Err, changes)
+	}
+}
+
+func TestTextInputScroll(t *testing.T) {
+	// Four cells of text beside the border, padding and prompt
+	field := NewTextInput("", 10)
+	node := field.ToStyledNode()
+	field.SetValue("abcdefgh")
+
+	tests := []struct {
+		name   string
+		key    input.KeyEvent
+		shown  string
+		cursor int
+	}{
+		{"End", input.KeyEvent{Key: input.KeyEnd}, "fgh", 6},
+		{"Left", input.KeyEvent{Key: input.KeyLeft}, "fgh", 5},
+		{"PastFirst", input.KeyEvent{Key: input.KeyLeft, Mod: input.ModCtrl}, "abcd", 3},
+		{"Right", input.KeyEvent{Key: input.KeyRight}, "abcd", 4},
+		{"Forward", runeKey('f', input.ModCtrl), "abcd", 5},
+		{"ToLast", input.KeyEvent{Key: input.KeyRight}, "abcd", 6},
+		{"PastLast", input.KeyEvent{Key: input.KeyRight}, "bcde", 6},
+	}
+	for _, tt := range tests {
+		field.HandleKey(tt.key)
+		field.refresh()
+		if got := shownText(field); got != tt.shown {
+			t.Errorf("%s: expected %q shown, got %q", tt.name, tt.shown, got)
+		}
+		if node.Cursor.X != tt.cursor {
+			t.Errorf("%s: expected the cursor at %d, got %d", tt.name, tt.cursor, node.Cursor.X)
+		}
+	}
+}
+
+func TestTextInputCursorX(t *testing.T) {
+	field := NewTextInput("", 30)
+	node := field.ToStyledNode()
+	field.Insert("日本x")
+
+	// Past the border, the prompt and two wide characters and a narrow one
+	if node.Cursor.X != 1+2+5 {
+		t.Errorf("Expected the cursor at %d, got %d", 1+2+5, node.Cursor.X)
+	}
+	field.HandleKey(input.KeyEvent{Key: input.KeyLeft})
+	field.HandleKey(input.KeyEvent{Key: input.KeyLeft})
+	field.refresh()
+	if node.Cursor.X != 1+2+2 {
+		t.Errorf("Expected the cursor after the first wide character, got %d", node.Cursor.X)
+	}
+
+	field.Prompt = ""
+	field.refresh()
+	if node.Cursor.X != 1+2 {
+		t.Errorf("Expected the cursor without a prompt at 3, got %d", node.Cursor.X)
+	}
+}
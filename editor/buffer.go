@@ -0,0 +1,158 @@
+package editor
+
+import "strings"
+
+// Buffer holds text as a piece table: the original text and an
+// append-only buffer of everything inserted since, with a list of pieces
+// that spell the current text out of the two. Edits only split and add
+// pieces, and Slice reads only the pieces it spans. String joins the
+// pieces into one string, which is kept until the next edit, so the first
+// read of the whole text after an edit copies all of it.
+type Buffer struct {
+	original string
+	added    []byte
+	pieces   []piece
+	length   int
+
+	text  string // Text of the pieces, valid while cached is set
+	cache bool
+}
+
+// piece is a run of the original or added text
+type piece struct {
+	added         bool
+	start, length int
+}
+
+// NewBuffer creates a buffer holding text
+func NewBuffer(text string) *Buffer {
+	b := &Buffer{original: text, length: len(text)}
+	if text != "" {
+		b.pieces = []piece{{start: 0, length: len(text)}}
+	}
+	return b
+}
+
+// Len returns the length of the text in bytes
+func (b *Buffer) Len() int {
+	return b.length
+}
+
+// String returns the text
+func (b *Buffer) String() string {
+	if !b.cache {
+		var text strings.Builder
+		text.Grow(b.length)
+		for _, p := range b.pieces {
+			text.WriteString(b.pieceText(p))
+		}
+		b.text, b.cache = text.String(), true
+	}
+	return b.text
+}
+
+// Slice returns the text from byte start to end
+func (b *Buffer) Slice(start, end int) string {
+	if b.cache {
+		return b.text[start:end]
+	}
+	var text strings.Builder
+	text.Grow(end - start)
+	pos := 0
+	for _, p := range b.pieces {
+		pStart, pEnd := pos, pos+p.length
+		pos = pEnd
+		if pEnd <= start || pStart >= end {
+			continue
+		}
+		t := b.pieceText(p)
+		text.WriteString(t[max(start, pStart)-pStart : min(end, pEnd)-pStart])
+	}
+	return text.String()
+}
+
+func (b *Buffer) pieceText(p piece) string {
+	if p.added {
+		return string(b.added[p.start : p.start+p.length])
+	}
+	return b.original[p.start : p.start+p.length]
+}
+
+// Insert inserts text at a byte offset
+func (b *Buffer) Insert(offset int, text string) {
+	if text == "" {
+		return
+	}
+	offset = max(0, min(offset, b.length))
+	start := len(b.added)
+	b.added = append(b.added, text...)
+	b.length += len(text)
+	b.cache = false
+
+	i, within := b.find(offset)
+
+	// Typing extends the piece it follows when that piece ends the added text
+	if within == 0 && i > 0 {
+		prev := &b.pieces[i-1]
+		if prev.added && prev.start+prev.length == start {
+			prev.length += len(text)
+			return
+		}
+	}
+
+	inserted := piece{added: true, start: start, length: len(text)}
+	if within == 0 {
+		b.pieces = append(b.pieces[:i], append([]piece{inserted}, b.pieces[i:]...)...)
+		return
+	}
+	p := b.pieces[i]
+	left := piece{added: p.added, start: p.start, length: within}
+	right := piece{added: p.added, start: p.start + within, length: p.length - within}
+	b.pieces = append(b.pieces[:i], append([]piece{left, inserted, right}, b.pieces[i+1:]...)...)
+}
+
+// Delete removes length bytes from a byte offset
+func (b *Buffer) Delete(offset, length int) {
+	offset = max(0, min(offset, b.length))
+	length = min(length, b.length-offset)
+	if length <= 0 {
+		return
+	}
+	b.length -= length
+	b.cache = false
+
+	end := offset + length
+	var kept []piece
+	pos := 0
+	for _, p := range b.pieces {
+		pStart, pEnd := pos, pos+p.length
+		pos = pEnd
+		if pEnd <= offset || pStart >= end {
+			kept = append(kept, p)
+			continue
+		}
+		// Keep the parts of the piece before and after the deleted range
+		if pStart < offset {
+			kept = append(kept, piece{added: p.added, start: p.start, length: offset - pStart})
+		}
+		if pEnd > end {
+			cut := end - pStart
+			kept = append(kept, piece{added: p.added, start: p.start + cut, length: pEnd - end})
+		}
+	}
+	b.pieces = kept
+}
+
+// find returns the index of the piece holding a byte offset and the offset
+// within it. An offset between pieces is at the start of the later one,
+// and the end of the text is past the last piece.
+func (b *Buffer) find(offset int) (index, within int) {
+	pos := 0
+	for i, p := range b.pieces {
+		if offset < pos+p.length {
+			return i, offset - pos
+		}
+		pos += p.length
+	}
+	return len(b.pieces), 0
+}
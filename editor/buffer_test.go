@@ -0,0 +1,54 @@
+package editor
+
+import "testing"
+
+func TestBufferEdits(t *testing.T) {
+	b := NewBuffer("hello world")
+	b.Insert(5, ",")
+	b.Insert(12, "!")
+	b.Insert(0, ">> ")
+	if got := b.String(); got != ">> hello, world!" {
+		t.Fatalf("Expected inserts in the middle and at both ends, got %q", got)
+	}
+
+	b.Delete(3, 7) // "hello, "
+	if got := b.String(); got != ">> world!" {
+		t.Errorf("Expected a delete across pieces, got %q", got)
+	}
+	if b.Len() != len(">> world!") {
+		t.Errorf("Expected length %d, got %d", len(">> world!"), b.Len())
+	}
+	if got := b.Slice(3, 8); got != "world" {
+		t.Errorf("Expected slice \"world\", got %q", got)
+	}
+
+	b.Delete(0, 100)
+	if b.String() != "" || b.Len() != 0 {
+		t.Errorf("Expected an over-long delete to empty the buffer, got %q", b.String())
+	}
+}
+
+func TestBufferTypingExtendsPiece(t *testing.T) {
+	b := NewBuffer("ab")
+	for i, r := range "xyz" {
+		b.Insert(1+i, string(r))
+	}
+	if got := b.String(); got != "axyzb" {
+		t.Fatalf("Expected \"axyzb\", got %q", got)
+	}
+	if len(b.pieces) != 3 {
+		t.Errorf("Expected typing to extend one added piece, got %d pieces", len(b.pieces))
+	}
+}
+
+func TestBufferSliceAcrossPieces(t *testing.T) {
+	b := NewBuffer("hello world")
+	b.Insert(5, ",")
+	b.Delete(0, 1)
+	if got := b.Slice(2, 8); got != "lo, wo" {
+		t.Errorf("Expected slice \"llo, w\", got %q", got)
+	}
+	if b.cache {
+		t.Error("Expected Slice to read the pieces without joining the text")
+	}
+}
@@ -0,0 +1,633 @@
+package editor
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SCKelemen/cli/renderer"
+)
+
+// DefaultUndoGroupInterval is the longest pause between edits of the same
+// kind that are still undone together
+const DefaultUndoGroupInterval = time.Second
+
+// Selection is a range of text with a cursor at one end. Offsets are in
+// bytes and always fall between grapheme clusters.
+type Selection struct {
+	Anchor int // End that stays put when the selection is extended
+	Head   int // End with the cursor
+}
+
+// Cursor returns an empty selection at offset
+func Cursor(offset int) Selection {
+	return Selection{Anchor: offset, Head: offset}
+}
+
+// Start returns the lower end of the selection
+func (s Selection) Start() int {
+	return min(s.Anchor, s.Head)
+}
+
+// End returns the upper end of the selection
+func (s Selection) End() int {
+	return max(s.Anchor, s.Head)
+}
+
+// Empty reports whether the selection is only a cursor
+func (s Selection) Empty() bool {
+	return s.Anchor == s.Head
+}
+
+// editKind classifies edits for undo grouping
+type editKind int
+
+const (
+	editOther  editKind = iota // Never grouped
+	editInsert                 // Typing
+	editDelete                 // Deleting a character at a time
+)
+
+// change is one replacement in the buffer
+type change struct {
+	offset   int
+	deleted  string
+	inserted string
+}
+
+// undoGroup is the changes undone and redone together, with the
+// selections before and after them
+type undoGroup struct {
+	changes []change
+	before  []Selection
+	after   []Selection
+	kind    editKind
+	at      time.Time
+}
+
+// edit replaces a range for one selection
+type edit struct {
+	start, end int
+	text       string
+}
+
+// Editor edits a Buffer with any number of selections, each with its own
+// cursor. Every edit applies at all of them at once. Edits are recorded for
+// undo and redo; consecutive typing or deleting within UndoGroupInterval
+// is undone as one step.
+type Editor struct {
+	// UndoGroupInterval is the longest pause within one undo step of
+	// typing or deleting
+	UndoGroupInterval time.Duration
+
+	buf        *Buffer
+	selections []Selection // Sorted and not overlapping
+	primary    int         // Index of the selection the view follows
+	undo       []*undoGroup
+	redo       []*undoGroup
+	now        func() time.Time
+}
+
+// New creates an editor for text with a cursor at the start
+func New(text string) *Editor {
+	return &Editor{
+		UndoGroupInterval: DefaultUndoGroupInterval,
+		buf:               NewBuffer(text),
+		selections:        []Selection{Cursor(0)},
+		now:               time.Now,
+	}
+}
+
+// Text returns the text
+func (e *Editor) Text() string {
+	return e.buf.String()
+}
+
+// Len returns the length of the text in bytes
+func (e *Editor) Len() int {
+	return e.buf.Len()
+}
+
+// SetText replaces the text, keeping one cursor at the end. Undo history is
+// cleared.
+func (e *Editor) SetText(text string) {
+	e.buf = NewBuffer(text)
+	e.selections, e.primary = []Selection{Cursor(len(text))}, 0
+	e.undo, e.redo = nil, nil
+}
+
+// Selections returns the selections in text order
+func (e *Editor) Selections() []Selection {
+	return append([]Selection(nil), e.selections...)
+}
+
+// Primary returns the selection the view follows, usually the one added
+// last
+func (e *Editor) Primary() Selection {
+	return e.selections[e.primary]
+}
+
+// SetSelections replaces the selections. The last one becomes the primary
+// selection. Overlapping selections are merged.
+func (e *Editor) SetSelections(selections ...Selection) {
+	if len(selections) == 0 {
+		selections = []Selection{Cursor(0)}
+	}
+	e.setSelections(selections, len(selections)-1)
+}
+
+// AddSelection adds a selection and makes it the primary one
+func (e *Editor) AddSelection(s Selection) {
+	e.setSelections(append(e.Selections(), s), len(e.selections))
+}
+
+// CollapseSelections drops every selection but the primary one, and
+// reports whether there were others
+func (e *Editor) CollapseSelections() bool {
+	if len(e.selections) == 1 {
+		return false
+	}
+	e.selections, e.primary = []Selection{e.Primary()}, 0
+	return true
+}
+
+// setSelections clamps, sorts and merges selections, keeping track of the
+// primary one
+func (e *Editor) setSelections(selections []Selection, primary int) {
+	type indexed struct {
+		Selection
+		primary bool
+	}
+	sorted := make([]indexed, len(selections))
+	for i, s := range selections {
+		s.Anchor = max(0, min(s.Anchor, e.Len()))
+		s.Head = max(0, min(s.Head, e.Len()))
+		sorted[i] = indexed{s, i == primary}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Start() < sorted[j].Start()
+	})
+
+	merged := []indexed{sorted[0]}
+	for _, s := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if s.Start() > last.End() || s.Start() == last.End() && !s.Empty() && !last.Empty() {
+			merged = append(merged, s)
+			continue
+		}
+		// Overlapping selections join, keeping the direction of the first
+		start, end := last.Start(), max(last.End(), s.End())
+		if last.Head < last.Anchor {
+			last.Selection = Selection{Anchor: end, Head: start}
+		} else {
+			last.Selection = Selection{Anchor: start, Head: end}
+		}
+		last.primary = last.primary || s.primary
+	}
+
+	e.selections = make([]Selection, len(merged))
+	e.primary = 0
+	for i, s := range merged {
+		e.selections[i] = s.Selection
+		if s.primary {
+			e.primary = i
+		}
+	}
+}
+
+// MoveCursors moves the cursor of every selection to the offset move
+// returns for it. With extend the anchors stay put, selecting text;
+// otherwise each selection becomes a cursor.
+func (e *Editor) MoveCursors(move func(s Selection) int, extend bool) {
+	moved := make([]Selection, len(e.selections))
+	for i, s := range e.selections {
+		head := move(s)
+		if extend {
+			moved[i] = Selection{Anchor: s.Anchor, Head: head}
+		} else {
+			moved[i] = Cursor(head)
+		}
+	}
+	e.setSelections(moved, e.primary)
+}
+
+// Insert types text at every selection, replacing the selected text
+func (e *Editor) Insert(text string) {
+	kind := editInsert
+	if strings.Contains(text, "\n") || utf8.RuneCountInString(text) > 1 {
+		kind = editOther
+	}
+	edits := make([]edit, len(e.selections))
+	for i, s := range e.selections {
+		edits[i] = edit{s.Start(), s.End(), text}
+	}
+	e.apply(edits, kind)
+}
+
+// InsertEach types texts[i] at the i-th selection, such as the lines of a
+// paste with as many lines as there are cursors. It returns false, doing
+// nothing, when the counts differ.
+func (e *Editor) InsertEach(texts []string) bool {
+	if len(texts) != len(e.selections) {
+		return false
+	}
+	edits := make([]edit, len(e.selections))
+	for i, s := range e.selections {
+		edits[i] = edit{s.Start(), s.End(), texts[i]}
+	}
+	e.apply(edits, editOther)
+	return true
+}
+
+// Delete removes the selected text at every selection. Empty selections
+// remove the range from their cursor to the offset to returns for them,
+// such as PrevBoundary for Backspace.
+func (e *Editor) Delete(to func(cursor int) int) {
+	edits := make([]edit, len(e.selections))
+	kind := editDelete
+	for i, s := range e.selections {
+		if !s.Empty() {
+			edits[i] = edit{s.Start(), s.End(), ""}
+			kind = editOther
+			continue
+		}
+		other := max(0, min(to(s.Head), e.Len()))
+		edits[i] = edit{min(s.Head, other), max(s.Head, other), ""}
+	}
+	e.apply(edits, kind)
+}
+
+// apply makes one edit per selection and leaves a cursor after each.
+// Edits are in text order; overlapping ones are merged.
+func (e *Editor) apply(edits []edit, kind editKind) {
+	merged := edits[:0:0]
+	for _, ed := range edits {
+		if n := len(merged); n > 0 && ed.start < merged[n-1].end {
+			merged[n-1].end = max(merged[n-1].end, ed.end)
+			merged[n-1].text += ed.text
+			continue
+		}
+		merged = append(merged, ed)
+	}
+
+	changed := false
+	for _, ed := range merged {
+		if ed.start != ed.end || ed.text != "" {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	before := e.Selections()
+	changes := make([]change, 0, len(merged))
+
+	// Apply from the end so the offsets of earlier edits still hold
+	for i := len(merged) - 1; i >= 0; i-- {
+		ed := merged[i]
+		c := change{offset: ed.start, deleted: e.buf.Slice(ed.start, ed.end), inserted: ed.text}
+		e.buf.Delete(c.offset, len(c.deleted))
+		e.buf.Insert(c.offset, c.inserted)
+		changes = append(changes, c)
+	}
+
+	cursors := make([]Selection, len(merged))
+	delta := 0
+	for i, ed := range merged {
+		cursors[i] = Cursor(ed.start + delta + len(ed.text))
+		delta += len(ed.text) - (ed.end - ed.start)
+	}
+	primary := min(e.primary, len(cursors)-1)
+	e.setSelections(cursors, primary)
+	e.record(changes, before, kind)
+}
+
+// record adds changes to the undo history, joining the last group when
+// they continue the same typing or deleting
+func (e *Editor) record(changes []change, before []Selection, kind editKind) {
+	now := e.now()
+	e.redo = nil
+	if n := len(e.undo); n > 0 && kind != editOther {
+		last := e.undo[n-1]
+		if last.kind == kind && now.Sub(last.at) <= e.UndoGroupInterval && equalSelections(last.after, before) {
+			last.changes = append(last.changes, changes...)
+			last.after, last.at = e.Selections(), now
+			return
+		}
+	}
+	e.undo = append(e.undo, &undoGroup{
+		changes: changes,
+		before:  before,
+		after:   e.Selections(),
+		kind:    kind,
+		at:      now,
+	})
+}
+
+// BreakUndoGroup makes the next edit start a new undo step
+func (e *Editor) BreakUndoGroup() {
+	if n := len(e.undo); n > 0 {
+		e.undo[n-1].kind = editOther
+	}
+}
+
+func equalSelections(a, b []Selection) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Undo reverts the last undo step and restores its selections. It returns
+// false when there is nothing to undo.
+func (e *Editor) Undo() bool {
+	n := len(e.undo)
+	if n == 0 {
+		return false
+	}
+	group := e.undo[n-1]
+	e.undo = e.undo[:n-1]
+	for i := len(group.changes) - 1; i >= 0; i-- {
+		c := group.changes[i]
+		e.buf.Delete(c.offset, len(c.inserted))
+		e.buf.Insert(c.offset, c.deleted)
+	}
+	e.restore(group.before)
+	e.redo = append(e.redo, group)
+	return true
+}
+
+// Redo applies the last undone step again. It returns false when there is
+// nothing to redo.
+func (e *Editor) Redo() bool {
+	n := len(e.redo)
+	if n == 0 {
+		return false
+	}
+	group := e.redo[n-1]
+	e.redo = e.redo[:n-1]
+	for _, c := range group.changes {
+		e.buf.Delete(c.offset, len(c.deleted))
+		e.buf.Insert(c.offset, c.inserted)
+	}
+	e.restore(group.after)
+	group.kind = editOther // Later typing starts a new step
+	e.undo = append(e.undo, group)
+	return true
+}
+
+// restore sets selections saved in the history, the last one primary
+func (e *Editor) restore(selections []Selection) {
+	e.setSelections(append([]Selection(nil), selections...), len(selections)-1)
+}
+
+// LineStart returns the offset of the start of the line holding offset
+func (e *Editor) LineStart(offset int) int {
+	return strings.LastIndexByte(e.Text()[:offset], '\n') + 1
+}
+
+// LineEnd returns the offset of the line break ending the line holding
+// offset, or the end of the text
+func (e *Editor) LineEnd(offset int) int {
+	text := e.Text()
+	if i := strings.IndexByte(text[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(text)
+}
+
+// PrevBoundary returns the start of the grapheme cluster before offset
+func (e *Editor) PrevBoundary(offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	start := e.LineStart(offset)
+	if start == offset {
+		return offset - 1 // The line break
+	}
+	graphemes := renderer.Graphemes(e.Text()[start:offset])
+	return offset - len(graphemes[len(graphemes)-1])
+}
+
+// NextBoundary returns the end of the grapheme cluster after offset
+func (e *Editor) NextBoundary(offset int) int {
+	if offset >= e.Len() {
+		return e.Len()
+	}
+	end := e.LineEnd(offset)
+	if end == offset {
+		return offset + 1
+	}
+	return offset + len(renderer.Graphemes(e.Text()[offset:end])[0])
+}
+
+// WordLeft returns the start of the word before offset
+func (e *Editor) WordLeft(offset int) int {
+	text := e.Text()
+	for offset > 0 {
+		prev := e.PrevBoundary(offset)
+		if isWordChar(text[prev:offset]) {
+			break
+		}
+		offset = prev
+	}
+	for offset > 0 {
+		prev := e.PrevBoundary(offset)
+		if !isWordChar(text[prev:offset]) {
+			break
+		}
+		offset = prev
+	}
+	return offset
+}
+
+// WordRight returns the end of the word after offset
+func (e *Editor) WordRight(offset int) int {
+	text := e.Text()
+	for offset < len(text) {
+		next := e.NextBoundary(offset)
+		if isWordChar(text[offset:next]) {
+			break
+		}
+		offset = next
+	}
+	for offset < len(text) {
+		next := e.NextBoundary(offset)
+		if !isWordChar(text[offset:next]) {
+			break
+		}
+		offset = next
+	}
+	return offset
+}
+
+// isWordChar reports whether a grapheme cluster belongs to a word
+func isWordChar(grapheme string) bool {
+	r, _ := utf8.DecodeRuneInString(grapheme)
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// Find returns the first match of query at or after from, wrapping around
+// to the start of the text
+func (e *Editor) Find(query string, from int) (start, end int, ok bool) {
+	if query == "" {
+		return 0, 0, false
+	}
+	text := e.Text()
+	from = max(0, min(from, len(text)))
+	if i := strings.Index(text[from:], query); i >= 0 {
+		return from + i, from + i + len(query), true
+	}
+	if i := strings.Index(text, query); i >= 0 {
+		return i, i + len(query), true
+	}
+	return 0, 0, false
+}
+
+// Matches returns the start of every match of query, not overlapping
+func (e *Editor) Matches(query string) []int {
+	if query == "" {
+		return nil
+	}
+	var starts []int
+	text := e.Text()
+	for from := 0; ; {
+		i := strings.Index(text[from:], query)
+		if i < 0 {
+			return starts
+		}
+		starts = append(starts, from+i)
+		from += i + len(query)
+	}
+}
+
+// FindNext selects the next match of query after the primary selection as
+// the only selection. It returns false when there is no match.
+func (e *Editor) FindNext(query string) bool {
+	start, end, ok := e.Find(query, e.Primary().End())
+	if ok {
+		e.SetSelections(Selection{Anchor: start, Head: end})
+	}
+	return ok
+}
+
+// SelectNextMatch adds a selection at the next match of the primary
+// selection's text. An empty primary selection first selects the word
+// around its cursor. It returns false when nothing was selected or added.
+func (e *Editor) SelectNextMatch() bool {
+	p := e.Primary()
+	if p.Empty() {
+		start, end := e.wordAt(p.Head)
+		if start == end {
+			return false
+		}
+		selections := e.Selections()
+		selections[e.primary] = Selection{Anchor: start, Head: end}
+		e.setSelections(selections, e.primary)
+		return true
+	}
+
+	query := e.Text()[p.Start():p.End()]
+	for from := p.End(); ; {
+		start, end, ok := e.Find(query, from)
+		if !ok || start == p.Start() {
+			return false
+		}
+		if !e.selected(start) {
+			e.AddSelection(Selection{Anchor: start, Head: end})
+			return true
+		}
+		from = end
+	}
+}
+
+// selected reports whether a selection starts at offset
+func (e *Editor) selected(offset int) bool {
+	for _, s := range e.selections {
+		if !s.Empty() && s.Start() == offset {
+			return true
+		}
+	}
+	return false
+}
+
+// wordAt returns the word around offset
+func (e *Editor) wordAt(offset int) (start, end int) {
+	text := e.Text()
+	start, end = offset, offset
+	for start > 0 {
+		prev := e.PrevBoundary(start)
+		if !isWordChar(text[prev:start]) {
+			break
+		}
+		start = prev
+	}
+	for end < len(text) {
+		next := e.NextBoundary(end)
+		if !isWordChar(text[end:next]) {
+			break
+		}
+		end = next
+	}
+	return start, end
+}
+
+// SelectMatches selects every match of query, and returns how many there
+// are. The selections are unchanged when there are none.
+func (e *Editor) SelectMatches(query string) int {
+	starts := e.Matches(query)
+	if len(starts) == 0 {
+		return 0
+	}
+	selections := make([]Selection, len(starts))
+	for i, start := range starts {
+		selections[i] = Selection{Anchor: start, Head: start + len(query)}
+	}
+	e.SetSelections(selections...)
+	return len(starts)
+}
+
+// Replace replaces the primary selection with replacement if it is a match
+// of query, then selects the next match. It returns false when there was
+// no match to replace or select.
+func (e *Editor) Replace(query, replacement string) bool {
+	p := e.Primary()
+	if query == "" {
+		return false
+	}
+	if e.Text()[p.Start():p.End()] != query {
+		return e.FindNext(query)
+	}
+	e.SetSelections(p)
+	e.apply([]edit{{p.Start(), p.End(), replacement}}, editOther)
+	e.FindNext(query)
+	return true
+}
+
+// ReplaceAll replaces every match of query in one undo step and returns
+// how many were replaced
+func (e *Editor) ReplaceAll(query, replacement string) int {
+	starts := e.Matches(query)
+	if len(starts) == 0 {
+		return 0
+	}
+	edits := make([]edit, len(starts))
+	for i, start := range starts {
+		edits[i] = edit{start, start + len(query), replacement}
+	}
+	e.apply(edits, editOther)
+
+	// Leave one cursor after the last replacement rather than one at each
+	last := e.selections[len(e.selections)-1]
+	e.SetSelections(last)
+	e.undo[len(e.undo)-1].after = e.Selections()
+	return len(starts)
+}
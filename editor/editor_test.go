@@ -0,0 +1,193 @@
+package editor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// clock is a settable time source for undo grouping
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time { return c.t }
+
+func newEditor(text string) (*Editor, *clock) {
+	e := New(text)
+	c := &clock{t: time.Unix(0, 0)}
+	e.now = c.now
+	return e, c
+}
+
+func TestEditorMultipleCursors(t *testing.T) {
+	e, _ := newEditor("one\ntwo\nthree")
+	e.SetSelections(Cursor(0), Cursor(4), Cursor(8))
+	e.Insert("- ")
+	if got := e.Text(); got != "- one\n- two\n- three" {
+		t.Fatalf("Expected a prefix on every line, got %q", got)
+	}
+	expected := []Selection{Cursor(2), Cursor(8), Cursor(14)}
+	if got := e.Selections(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected cursors %v, got %v", expected, got)
+	}
+
+	// Backspace from adjacent cursors merges them
+	e.SetSelections(Cursor(1), Cursor(2))
+	e.Delete(e.PrevBoundary)
+	if got := e.Text(); got != "one\n- two\n- three" || len(e.Selections()) != 1 {
+		t.Errorf("Expected merged cursors after deleting \"- \", got %q with %v", got, e.Selections())
+	}
+}
+
+func TestEditorSelectionsMerge(t *testing.T) {
+	e, _ := newEditor("abcdefgh")
+	e.SetSelections(Selection{Anchor: 1, Head: 4}, Selection{Anchor: 6, Head: 3}, Cursor(7))
+	expected := []Selection{{Anchor: 1, Head: 6}, Cursor(7)}
+	if got := e.Selections(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected overlapping selections to merge into %v, got %v", expected, got)
+	}
+	if e.Primary() != Cursor(7) {
+		t.Errorf("Expected the last selection to be primary, got %v", e.Primary())
+	}
+}
+
+func TestEditorGraphemeBoundaries(t *testing.T) {
+	e, _ := newEditor("é👍🏽\nx")
+	if got := e.NextBoundary(0); got != len("é") {
+		t.Errorf("Expected a combining mark to move with its letter, got %d", got)
+	}
+	end := len("é👍🏽")
+	if got := e.PrevBoundary(end); got != len("é") {
+		t.Errorf("Expected an emoji with a skin tone to be one step, got %d", got)
+	}
+	if got := e.NextBoundary(end); got != end+1 {
+		t.Errorf("Expected the line break to be one step, got %d", got)
+	}
+}
+
+func TestEditorWords(t *testing.T) {
+	e, _ := newEditor("foo, bar_baz qux")
+	if got := e.WordRight(3); got != len("foo, bar_baz") {
+		t.Errorf("Expected WordRight to skip punctuation and the word, got %d", got)
+	}
+	if got := e.WordLeft(len("foo, bar_baz")); got != len("foo, ") {
+		t.Errorf("Expected WordLeft to stop at the word start, got %d", got)
+	}
+}
+
+func TestEditorUndoGrouping(t *testing.T) {
+	e, c := newEditor("")
+	for _, r := range "abc" {
+		e.Insert(string(r))
+		c.t = c.t.Add(100 * time.Millisecond)
+	}
+	c.t = c.t.Add(2 * time.Second)
+	e.Insert("d")
+	e.Delete(e.PrevBoundary)
+	e.Delete(e.PrevBoundary)
+
+	steps := []string{"abcd", "abc", ""}
+	for _, expected := range steps {
+		if !e.Undo() {
+			t.Fatal("Expected an undo step")
+		}
+		if got := e.Text(); got != expected {
+			t.Errorf("Expected %q after undo, got %q", expected, got)
+		}
+	}
+	if e.Undo() {
+		t.Error("Expected no more undo steps")
+	}
+
+	for _, expected := range []string{"abc", "abcd", "ab"} {
+		if !e.Redo() {
+			t.Fatal("Expected a redo step")
+		}
+		if got := e.Text(); got != expected {
+			t.Errorf("Expected %q after redo, got %q", expected, got)
+		}
+	}
+	if e.Primary() != Cursor(2) {
+		t.Errorf("Expected redo to restore the cursor, got %v", e.Primary())
+	}
+
+	e.Insert("x")
+	if e.Redo() {
+		t.Error("Expected a new edit to clear redo")
+	}
+}
+
+func TestEditorUndoMultipleCursors(t *testing.T) {
+	e, _ := newEditor("a\nb")
+	e.SetSelections(Cursor(1), Cursor(3))
+	e.Insert("!")
+	e.Undo()
+	if got := e.Text(); got != "a\nb" {
+		t.Errorf("Expected undo to revert both inserts, got %q", got)
+	}
+	if expected := []Selection{Cursor(1), Cursor(3)}; !reflect.DeepEqual(e.Selections(), expected) {
+		t.Errorf("Expected the cursors back at %v, got %v", expected, e.Selections())
+	}
+}
+
+func TestEditorSearchAndReplace(t *testing.T) {
+	e, _ := newEditor("cat dog cat bird cat")
+	e.SetSelections(Cursor(1))
+	if !e.FindNext("cat") || e.Primary() != (Selection{Anchor: 8, Head: 11}) {
+		t.Errorf("Expected the match after the cursor, got %v", e.Primary())
+	}
+	if !e.Replace("cat", "cow") || e.Text() != "cat dog cow bird cat" {
+		t.Errorf("Expected the selected match to be replaced, got %q", e.Text())
+	}
+	if e.Primary() != (Selection{Anchor: 17, Head: 20}) {
+		t.Errorf("Expected the next match to be selected, got %v", e.Primary())
+	}
+
+	if n := e.ReplaceAll("cat", "ox"); n != 2 || e.Text() != "ox dog cow bird ox" {
+		t.Errorf("Expected 2 replacements, got %d and %q", n, e.Text())
+	}
+	e.Undo()
+	if e.Text() != "cat dog cow bird cat" {
+		t.Errorf("Expected replace all to undo in one step, got %q", e.Text())
+	}
+	if n := e.SelectMatches("cat"); n != 2 || len(e.Selections()) != 2 {
+		t.Errorf("Expected a selection per match, got %d", n)
+	}
+}
+
+func TestEditorSelectNextMatch(t *testing.T) {
+	e, _ := newEditor("go to go and go")
+	e.SetSelections(Cursor(1))
+	e.SelectNextMatch()
+	e.SelectNextMatch()
+	e.SelectNextMatch()
+	starts := []int{}
+	for _, s := range e.Selections() {
+		starts = append(starts, s.Start())
+	}
+	if expected := []int{0, 6, 13}; !reflect.DeepEqual(starts, expected) {
+		t.Errorf("Expected selections at %v, got %v", expected, starts)
+	}
+	if e.SelectNextMatch() {
+		t.Error("Expected no further match once every one is selected")
+	}
+
+	e.Insert("run")
+	if got := e.Text(); got != "run to run and run" {
+		t.Errorf("Expected typing to replace every selection, got %q", got)
+	}
+}
+
+func TestEditorSelectNextMatchMultipleCursors(t *testing.T) {
+	e, _ := newEditor("hello world")
+	e.SetSelections(Cursor(2), Cursor(4))
+	e.SelectNextMatch()
+	expected := []Selection{{Anchor: 0, Head: 5}}
+	if got := e.Selections(); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("Expected the word to absorb the other cursor, got %v", got)
+	}
+
+	e.Insert("X")
+	if got := e.Text(); got != "X world" {
+		t.Errorf("Expected typing to replace the word, got %q", got)
+	}
+}
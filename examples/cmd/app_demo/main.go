@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SCKelemen/cli/app"
+	"github.com/SCKelemen/cli/components"
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/keymap"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// A native app: one StyledNode tree is kept for the whole run and changed
+// in place by event handlers and a ticker, with no bubbletea model
+func main() {
+	white, _ := color.ParseColor("#FAFAFA")
+	purple, _ := color.ParseColor("#7D56F4")
+	gray, _ := color.ParseColor("#888888")
+
+	root := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionColumn,
+			Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(2), Bottom: layout.Px(1), Left: layout.Px(2)},
+		},
+	}, nil)
+
+	headerStyle := &renderer.Style{Foreground: &white, BorderColor: &purple, Bold: true}
+	headerStyle.WithBorder(renderer.RoundedBorder)
+	header := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{Display: layout.DisplayBlock, Height: layout.Px(3)},
+	}, headerStyle)
+	root.AddChild(header)
+
+	spinner := components.NewSpinnerDots()
+	status := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{Display: layout.DisplayBlock, Height: layout.Px(1)},
+	}, &renderer.Style{Foreground: &purple})
+	root.AddChild(status)
+
+	section := components.NewCollapsible("Details", "Press space or click the header to collapse this section.\nThe tree is laid out once per frame.")
+	section.OnToggle = func(c *components.Collapsible) {
+		replaceChild(root, 2, c.ToStyledNode())
+	}
+	root.AddChild(section.ToStyledNode())
+
+	// Typed keys go to the focused input before the keymap sees them
+	name := components.NewTextInput("Type your name and press enter", 40)
+	name.ID = "name"
+	name.OnSubmit = func(t *components.TextInput) {
+		header.Content = fmt.Sprintf(" Hello, %s", t.Value())
+	}
+	root.AddChild(name.ToStyledNode())
+
+	// Named actions, which a user config file could rebind
+	km := keymap.New()
+	err := km.Add(
+		keymap.Binding{Action: "focus", Keys: []string{"tab"}, Help: "focus"},
+		keymap.Binding{Action: "toggle", Keys: []string{"space"}, Help: "toggle details"},
+		keymap.Binding{Action: "top", Keys: []string{"g g"}, Help: "expand details"},
+		keymap.Binding{Action: "suspend", Keys: []string{"ctrl+z"}, Help: "suspend"},
+		keymap.Binding{Action: "quit", Keys: []string{"q", "esc", "ctrl+c"}, Help: "quit"},
+		keymap.Binding{Action: "help", Keys: []string{"?"}, Help: "more"},
+	)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	help := components.NewHelpBar(km, 0)
+	help.DescColor = &gray
+	root.AddChild(help.ToStyledNode())
+	refreshHelp := func(a *app.App) {
+		help.Scopes = keymap.FocusScopes(a.Focus())
+		replaceChild(root, 4, help.ToStyledNode())
+	}
+
+	keys := 0
+	a := app.New(root, app.Options{
+		Mouse:    true,
+		Keyboard: input.KeyboardDisambiguate,
+		OnResize: func(a *app.App, width, height int) {
+			header.Content = fmt.Sprintf(" Native runtime • %dx%d", width, height)
+			help.Width = width - 4 // Root padding
+			refreshHelp(a)
+		},
+		OnEvent: func(a *app.App, event input.Event) {
+			key, ok := event.(input.KeyEvent)
+			if !ok || key.Action == input.KeyRelease {
+				return
+			}
+			keys++
+			defer refreshHelp(a)
+
+			action, _ := km.Handle(key, keymap.FocusScopes(a.Focus())...)
+			switch action {
+			case "quit":
+				a.Quit()
+			case "toggle":
+				section.Toggle()
+				replaceChild(root, 2, section.ToStyledNode())
+			case "top":
+				section.Expanded = true
+				replaceChild(root, 2, section.ToStyledNode())
+			case "help":
+				help.ToggleFull()
+			}
+		},
+	})
+
+	// Animate the spinner from outside the handlers
+	ticker := time.NewTicker(spinner.Interval)
+	defer ticker.Stop()
+	go func() {
+		for now := range ticker.C {
+			a.Update(func() {
+				spinner.Update(now)
+				status.Content = fmt.Sprintf("%s Working • %d keys pressed", spinner.ToStyledNode().Content, keys)
+			})
+		}
+	}()
+
+	if err := a.Run(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// replaceChild swaps a child of parent in both the styled and layout trees
+func replaceChild(parent *renderer.StyledNode, i int, child *renderer.StyledNode) {
+	parent.Children[i] = child
+	parent.Node.Children[i] = child.Node
+}
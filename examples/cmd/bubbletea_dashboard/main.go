@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/cli/teaadapter"
 	"github.com/SCKelemen/color"
 	"github.com/SCKelemen/layout"
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,18 +14,13 @@ import (
 
 type tickMsg time.Time
 
-type dashboardModel struct {
-	width   int
-	height  int
-	ready   bool
+// dashboard counts seconds; the adapter owns the screen, layout and quit
+// keys
+type dashboard struct {
 	counter int
 }
 
-func initialDashboardModel() dashboardModel {
-	return dashboardModel{}
-}
-
-func (m dashboardModel) Init() tea.Cmd {
+func (d *dashboard) Init() tea.Cmd {
 	return tickCmd()
 }
 
@@ -34,38 +30,15 @@ func tickCmd() tea.Cmd {
 	})
 }
 
-func (m dashboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
-			return m, tea.Quit
-		}
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.ready = true
-		return m, nil
-
-	case tickMsg:
-		m.counter++
-		return m, tickCmd()
+func (d *dashboard) Update(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(tickMsg); ok {
+		d.counter++
+		return tickCmd()
 	}
-
-	return m, nil
+	return nil
 }
 
-func (m dashboardModel) View() string {
-	if !m.ready {
-		return "Initializing..."
-	}
-
-	screen := renderer.NewScreen(m.width, m.height)
-
-	// Create layout context
-	ctx := layout.NewLayoutContext(float64(m.width), float64(m.height), 16)
-
+func (d *dashboard) Render(width, height int) *renderer.StyledNode {
 	// Create responsive grid layout with viewport units
 	root := &layout.Node{
 		Style: layout.Style{
@@ -96,7 +69,7 @@ func (m dashboardModel) View() string {
 	}
 	headerStyle.WithBorder(renderer.RoundedBorder)
 	headerStyled := renderer.NewStyledNode(headerNode, headerStyle)
-	headerStyled.Content = fmt.Sprintf(" Dashboard (CSS Units!) • %dx%d • %ds", m.width, m.height, m.counter)
+	headerStyled.Content = fmt.Sprintf(" Dashboard (CSS Units!) • %dx%d • %ds", width, height, d.counter)
 	rootStyled.AddChild(headerStyled)
 
 	// Left panel - Color gradient
@@ -112,7 +85,8 @@ func (m dashboardModel) View() string {
 	}
 	leftStyle.WithBorder(renderer.RoundedBorder)
 	leftStyled := renderer.NewStyledNode(leftNode, leftStyle)
-	leftStyled.Content = fmt.Sprintf("\n Gradient Panel\n\n Hue: %d°", (m.counter*10)%360)
+	leftStyled.Content = fmt.Sprintf("\n Gradient Panel\n\n Hue: %d°", (d.counter*10)%360)
+	focusable(leftStyled, "gradient")
 	rootStyled.AddChild(leftStyled)
 
 	// Right panel - Stats
@@ -128,7 +102,8 @@ func (m dashboardModel) View() string {
 	}
 	rightStyle.WithBorder(renderer.RoundedBorder)
 	rightStyled := renderer.NewStyledNode(rightNode, rightStyle)
-	rightStyled.Content = fmt.Sprintf("\n Statistics\n\n Width:  %d\n Height: %d\n Cells:  %d", m.width, m.height, m.width*m.height)
+	rightStyled.Content = fmt.Sprintf("\n Statistics\n\n Width:  %d\n Height: %d\n Cells:  %d", width, height, width*height)
+	focusable(rightStyled, "stats")
 	rootStyled.AddChild(rightStyled)
 
 	// Bottom left - Progress
@@ -144,8 +119,9 @@ func (m dashboardModel) View() string {
 	}
 	bottomLeftStyle.WithBorder(renderer.RoundedBorder)
 	bottomLeftStyled := renderer.NewStyledNode(bottomLeftNode, bottomLeftStyle)
-	progress := (m.counter % 10) * 10
+	progress := (d.counter % 10) * 10
 	bottomLeftStyled.Content = fmt.Sprintf("\n Progress: %d%%", progress)
+	focusable(bottomLeftStyled, "progress")
 	rootStyled.AddChild(bottomLeftStyled)
 
 	// Bottom right - Controls
@@ -161,19 +137,22 @@ func (m dashboardModel) View() string {
 	}
 	bottomRightStyle.WithBorder(renderer.RoundedBorder)
 	bottomRightStyled := renderer.NewStyledNode(bottomRightNode, bottomRightStyle)
-	bottomRightStyled.Content = "\n Controls\n\n q/ESC - Quit\n Resize terminal"
+	bottomRightStyled.Content = "\n Controls\n\n Tab/arrows - Move focus\n q/ESC - Quit\n Resize terminal"
+	focusable(bottomRightStyled, "controls")
 	rootStyled.AddChild(bottomRightStyled)
 
-	// Layout and render with context
-	constraints := layout.Tight(float64(m.width), float64(m.height))
-	layout.Layout(root, constraints, ctx)
-	screen.Render(rootStyled)
+	return rootStyled
+}
 
-	return screen.String()
+// focusable lets a panel take focus from Tab, the arrow keys or a click.
+// The tree is rebuilt on every frame, so the ID carries focus over.
+func focusable(panel *renderer.StyledNode, id string) {
+	panel.Focusable = true
+	panel.ID = id
 }
 
 func main() {
-	p := tea.NewProgram(initialDashboardModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(teaadapter.New(&dashboard{}), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -5,54 +5,15 @@ import (
 	"os"
 
 	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/cli/teaadapter"
 	"github.com/SCKelemen/color"
 	"github.com/SCKelemen/layout"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-type model struct {
-	width  int
-	height int
-	ready  bool
-}
-
-func initialModel() model {
-	return model{}
-}
-
-func (m model) Init() tea.Cmd {
-	return nil
-}
-
-func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
-			return m, tea.Quit
-		}
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.ready = true
-		return m, nil
-	}
-
-	return m, nil
-}
-
-func (m model) View() string {
-	if !m.ready {
-		return "Initializing..."
-	}
-
-	// Create screen buffer matching terminal size
-	screen := renderer.NewScreen(m.width, m.height)
-
-	// Create layout context with viewport dimensions and root font size
-	ctx := layout.NewLayoutContext(float64(m.width), float64(m.height), 16)
-
+// render builds the tree for the window size; the adapter owns the screen,
+// layout and quit keys
+func render(width, height int) *renderer.StyledNode {
 	// Create root layout node using viewport units
 	root := &layout.Node{
 		Style: layout.Style{
@@ -81,7 +42,7 @@ func (m model) View() string {
 	}
 	headerStyle.WithBorder(renderer.RoundedBorder)
 	headerStyled := renderer.NewStyledNode(headerNode, headerStyle)
-	headerStyled.Content = fmt.Sprintf("\n Responsive TUI • %dx%d", m.width, m.height)
+	headerStyled.Content = fmt.Sprintf("\n Responsive TUI • %dx%d", width, height)
 	rootStyled.AddChild(headerStyled)
 
 	// Content area - 75% of viewport with gradient background
@@ -95,7 +56,7 @@ func (m model) View() string {
 	}
 
 	// Rainbow gradient - calculate hue based on time/position
-	hue := float64((m.width + m.height) % 360)
+	hue := float64((width + height) % 360)
 	gradientColor, _ := color.ParseColor(fmt.Sprintf("oklch(0.65 0.2 %.0f)", hue))
 
 	contentStyle := &renderer.Style{
@@ -108,7 +69,7 @@ func (m model) View() string {
 
 	// Simpler content that fits in small viewports
 	contentStyled.Content = fmt.Sprintf("\n\n %dx%d • Vh(%d/%d/%d)\n Try resizing!",
-		m.width, m.height, 15, 75, 10)
+		width, height, 15, 75, 10)
 	rootStyled.AddChild(contentStyled)
 
 	// Footer - 10% of viewport
@@ -127,16 +88,11 @@ func (m model) View() string {
 	footerStyled.Content = " Press 'q' to quit • Resize window"
 	rootStyled.AddChild(footerStyled)
 
-	// Layout and render with context
-	constraints := layout.Tight(float64(m.width), float64(m.height))
-	layout.Layout(root, constraints, ctx)
-	screen.Render(rootStyled)
-
-	return screen.String()
+	return rootStyled
 }
 
 func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(teaadapter.New(teaadapter.ComponentFunc(render)), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
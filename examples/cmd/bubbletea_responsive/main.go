@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/cli/teaadapter"
 	"github.com/SCKelemen/color"
 	"github.com/SCKelemen/layout"
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,52 +17,16 @@ const (
 	minPanelWidth       = 30  // Minimum width for each panel
 )
 
-type responsiveModel struct {
-	width  int
-	height int
-	ready  bool
-}
-
-func initialResponsiveModel() responsiveModel {
-	return responsiveModel{}
-}
-
-func (m responsiveModel) Init() tea.Cmd {
-	return nil
-}
-
-func (m responsiveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch msg := msg.(type) {
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
-			return m, tea.Quit
-		}
-
-	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		m.ready = true
-		return m, nil
-	}
-
-	return m, nil
-}
-
-func (m responsiveModel) View() string {
-	if !m.ready {
-		return "Initializing..."
-	}
-
-	screen := renderer.NewScreen(m.width, m.height)
-
+// render builds the tree for the window size; the adapter owns the screen,
+// layout and quit keys
+func render(width, height int) *renderer.StyledNode {
 	// Determine layout mode based on width
 	var layoutMode string
 	var columns int
-	if m.width >= minWidthThreeColumn {
+	if width >= minWidthThreeColumn {
 		layoutMode = "Three Column"
 		columns = 3
-	} else if m.width >= minWidthTwoColumn {
+	} else if width >= minWidthTwoColumn {
 		layoutMode = "Two Column"
 		columns = 2
 	} else {
@@ -74,8 +39,8 @@ func (m responsiveModel) View() string {
 		Style: layout.Style{
 			Display:       layout.DisplayFlex,
 			FlexDirection: layout.FlexDirectionColumn,
-			Width:         layout.Px(float64(m.width)),
-			Height:        layout.Px(float64(m.height)),
+			Width:         layout.Px(float64(width)),
+			Height:        layout.Px(float64(height)),
 		},
 	}
 	rootStyled := renderer.NewStyledNode(root, nil)
@@ -84,7 +49,7 @@ func (m responsiveModel) View() string {
 	headerNode := &layout.Node{
 		Style: layout.Style{
 			Display: layout.DisplayBlock,
-			Width:   layout.Px(float64(m.width)),
+			Width:   layout.Px(float64(width)),
 			Height:  layout.Px(3),
 		},
 	}
@@ -96,18 +61,18 @@ func (m responsiveModel) View() string {
 	}
 	headerStyle.WithBorder(renderer.RoundedBorder)
 	headerStyled := renderer.NewStyledNode(headerNode, headerStyle)
-	headerStyled.Content = fmt.Sprintf(" Responsive Layout: %s • %dx%d", layoutMode, m.width, m.height)
+	headerStyled.Content = fmt.Sprintf(" Responsive Layout: %s • %dx%d", layoutMode, width, height)
 	rootStyled.AddChild(headerStyled)
 
 	// Content container with flexible layout
-	contentHeight := m.height - 6 // Header + footer + margins
+	contentHeight := height - 6 // Header + footer + margins
 	if contentHeight > 0 {
 		contentNode := &layout.Node{
 			Style: layout.Style{
 				Display:       layout.DisplayFlex,
 				FlexDirection: layout.FlexDirectionRow,
 				FlexWrap:      layout.FlexWrapWrap,
-				Width:         layout.Px(float64(m.width)),
+				Width:         layout.Px(float64(width)),
 				Height:        layout.Px(float64(contentHeight)),
 				Margin:        layout.Spacing{Top: layout.Px(1), Right: layout.Px(0), Bottom: layout.Px(1), Left: layout.Px(0)},
 			},
@@ -134,7 +99,7 @@ func (m responsiveModel) View() string {
 		}
 
 		// Calculate panel width based on columns
-		panelWidth := m.width / columns
+		panelWidth := width / columns
 		if columns > 1 {
 			panelWidth-- // Account for spacing
 		}
@@ -168,7 +133,7 @@ func (m responsiveModel) View() string {
 	footerNode := &layout.Node{
 		Style: layout.Style{
 			Display: layout.DisplayBlock,
-			Width:   layout.Px(float64(m.width)),
+			Width:   layout.Px(float64(width)),
 			Height:  layout.Px(2),
 		},
 	}
@@ -179,9 +144,9 @@ func (m responsiveModel) View() string {
 	footerStyled := renderer.NewStyledNode(footerNode, footerStyle)
 
 	var breakpointInfo string
-	if m.width >= minWidthThreeColumn {
+	if width >= minWidthThreeColumn {
 		breakpointInfo = fmt.Sprintf("Desktop Mode (>=%d cols)", minWidthThreeColumn)
-	} else if m.width >= minWidthTwoColumn {
+	} else if width >= minWidthTwoColumn {
 		breakpointInfo = fmt.Sprintf("Tablet Mode (%d-%d cols)", minWidthTwoColumn, minWidthThreeColumn-1)
 	} else {
 		breakpointInfo = fmt.Sprintf("Mobile Mode (<%d cols)", minWidthTwoColumn)
@@ -190,21 +155,11 @@ func (m responsiveModel) View() string {
 	footerStyled.Content = fmt.Sprintf("Breakpoint: %s • Resize terminal to see responsive behavior • q/ESC to quit", breakpointInfo)
 	rootStyled.AddChild(footerStyled)
 
-	// Layout and render
-	constraints := layout.Tight(float64(m.width), float64(m.height))
-	ctx := &layout.LayoutContext{
-		ViewportWidth:  float64(m.width),
-		ViewportHeight: float64(m.height),
-		RootFontSize:   16,
-	}
-	layout.Layout(root, constraints, ctx)
-	screen.Render(rootStyled)
-
-	return screen.String()
+	return rootStyled
 }
 
 func main() {
-	p := tea.NewProgram(initialResponsiveModel(), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(teaadapter.New(teaadapter.ComponentFunc(render)), tea.WithAltScreen(), tea.WithMouseCellMotion())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SCKelemen/cli/app"
+	"github.com/SCKelemen/cli/components"
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// An in-terminal commit message editor. The message is printed when the
+// app exits with Ctrl+S.
+func main() {
+	gray, _ := color.ParseColor("#888888")
+
+	root := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionColumn,
+			Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(2), Bottom: layout.Px(1), Left: layout.Px(2)},
+		},
+	}, nil)
+
+	area := components.NewTextArea("Summarize the change\n\nExplain what it does and why.", 0, 0).WithLineNumbers(true)
+	area.ID = "message"
+	area.Editor.SetSelections(area.Editor.Selections()[0]) // Cursor at the start
+	root.AddChild(area.ToStyledNode())
+
+	status := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{Display: layout.DisplayBlock, Height: layout.Px(1)},
+	}, &renderer.Style{Foreground: &gray})
+	root.AddChild(status)
+
+	showStatus := func() {
+		e := area.Editor
+		head := e.Primary().Head
+		line := strings.Count(e.Text()[:head], "\n") + 1
+		col := renderer.TextWidth(e.Text()[e.LineStart(head):head]) + 1
+		status.Content = fmt.Sprintf("Ln %d, Col %d • %d cursors • ctrl+s save • esc quit • ctrl+alt+↑↓ add cursor • alt+n next match", line, col, len(e.Selections()))
+	}
+
+	// Keys the editor used still bubble up to the root after it
+	for _, t := range []renderer.EventType{renderer.EventKeyDown, renderer.EventPaste, renderer.EventMouseDown, renderer.EventDrag} {
+		root.On(t, func(*renderer.Event) { showStatus() })
+	}
+
+	saved := false
+	a := app.New(root, app.Options{
+		Mouse:          true,
+		Keyboard:       input.KeyboardDisambiguate,
+		DisableSuspend: true, // Ctrl+Z undoes
+		OnResize: func(a *app.App, width, height int) {
+			area.Width, area.Height = width-4, height-3 // Root padding and status line
+			root.Children[0] = area.ToStyledNode()
+			root.Node.Children[0] = root.Children[0].Node
+			a.Focus().Focus(root.Children[0])
+			showStatus()
+		},
+		OnEvent: func(a *app.App, event input.Event) {
+			key, ok := event.(input.KeyEvent)
+			if !ok || key.Action == input.KeyRelease {
+				return
+			}
+			switch key.String() {
+			case "ctrl+s":
+				saved = true
+				a.Quit()
+			case "esc", "ctrl+c":
+				a.Quit()
+			}
+		},
+	})
+
+	if err := a.Run(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if saved {
+		fmt.Println(area.Value())
+	}
+}
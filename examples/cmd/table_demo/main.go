@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/SCKelemen/cli/app"
+	"github.com/SCKelemen/cli/components"
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// A service picker: type in the filter, sort with the digit keys and press
+// Enter to print the selected service.
+func main() {
+	gray, _ := color.ParseColor("#888888")
+	green, _ := color.ParseColor("#04B575")
+	red, _ := color.ParseColor("#FF5F87")
+	yellow, _ := color.ParseColor("#FFD75F")
+
+	root := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{
+			Display:       layout.DisplayFlex,
+			FlexDirection: layout.FlexDirectionColumn,
+			Padding:       layout.Spacing{Top: layout.Px(1), Right: layout.Px(2), Bottom: layout.Px(1), Left: layout.Px(2)},
+		},
+	}, nil)
+
+	filter := components.NewTextInput("filter services", 40)
+	filter.Prompt = "/ "
+	filter.ID = "filter"
+	root.AddChild(filter.ToStyledNode())
+
+	table := components.NewTable([]components.Column{
+		components.AutoColumn("Service"),
+		components.AutoColumn("Status"),
+		components.FixedColumn("Replicas", 8).WithAlign(renderer.TextAlignRight),
+		components.FixedColumn("CPU %", 6).WithAlign(renderer.TextAlignRight),
+		components.FrColumn("Image", 1),
+	}, 0, 0)
+	table.ID = "services"
+
+	statusColors := map[string]*color.Color{"running": &green, "failing": &red, "pending": &yellow}
+	services := [][]string{
+		{"api-gateway", "running", "3", "41.2", "registry.local/gateway:2.14.1"},
+		{"auth", "running", "2", "12.8", "registry.local/auth:1.9.0"},
+		{"billing", "failing", "1", "97.5", "registry.local/billing:3.0.0-rc2"},
+		{"cache", "running", "4", "7.1", "redis:7.2-alpine"},
+		{"search", "pending", "0", "0", "registry.local/search:0.8.3"},
+		{"notifications", "running", "2", "18.4", "registry.local/notify:1.2.7"},
+		{"reports", "running", "1", "55.0", "registry.local/reports:4.1.0"},
+		{"scheduler", "failing", "1", "3.3", "registry.local/scheduler:2.0.5"},
+		{"storage", "running", "6", "22.9", "minio/minio:RELEASE.2024-06-13"},
+		{"web", "running", "5", "34.6", "registry.local/web:5.3.2"},
+	}
+	for _, s := range services {
+		cells := make([]components.TableCell, len(s))
+		for i, text := range s {
+			cells[i] = components.TableCell{Text: text}
+		}
+		cells[1].Style = &renderer.Style{Foreground: statusColors[s[1]]}
+		table.AddCells(cells...)
+	}
+	root.AddChild(table.ToStyledNode())
+
+	status := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{Display: layout.DisplayBlock, Height: layout.Px(1)},
+	}, &renderer.Style{Foreground: &gray})
+	root.AddChild(status)
+
+	showStatus := func() {
+		status.Content = fmt.Sprintf("%d of %d services • tab filter • 1-5 sort • enter select • esc quit", len(table.Visible()), len(table.Rows()))
+	}
+	showStatus()
+
+	var chosen []components.TableCell
+	filter.OnChange = func(t *components.TextInput) {
+		table.SetFilter(t.Value())
+		showStatus()
+	}
+	a := app.New(root, app.Options{
+		Mouse:    true,
+		Keyboard: input.KeyboardDisambiguate,
+		OnResize: func(a *app.App, width, height int) {
+			// The table fills the width, so its fr column follows the terminal
+			table.Height = height - 6 // Root padding, filter and status line
+			root.Children[1] = table.ToStyledNode()
+			root.Node.Children[1] = root.Children[1].Node
+			a.Focus().Focus(root.Children[1])
+		},
+		OnEvent: func(a *app.App, event input.Event) {
+			key, ok := event.(input.KeyEvent)
+			if !ok || key.Action == input.KeyRelease {
+				return
+			}
+			switch key.String() {
+			case "esc", "ctrl+c":
+				a.Quit()
+			}
+		},
+	})
+	table.OnSubmit = func(t *components.Table) {
+		chosen = t.SelectedRow()
+		a.Quit()
+	}
+	filter.OnSubmit = func(*components.TextInput) {
+		chosen = table.SelectedRow()
+		a.Quit()
+	}
+
+	if err := a.Run(); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if chosen != nil {
+		texts := make([]string, len(chosen))
+		for i, cell := range chosen {
+			texts[i] = cell.Text
+		}
+		fmt.Println(strings.Join(texts, "\t"))
+	}
+}
@@ -8,14 +8,15 @@ require (
 	github.com/SCKelemen/design-system v1.0.0
 	github.com/SCKelemen/layout v1.1.1
 	github.com/SCKelemen/text v1.1.0
+	github.com/SCKelemen/unicode v1.1.0
 	github.com/charmbracelet/bubbletea v1.3.10
 	github.com/mattn/go-runewidth v0.0.19
+	github.com/muesli/cancelreader v0.2.2
 	golang.org/x/term v0.38.0
 )
 
 require (
 	github.com/SCKelemen/svg v0.1.0 // indirect
-	github.com/SCKelemen/unicode v1.1.0 // indirect
 	github.com/SCKelemen/units v1.0.3 // indirect
 	github.com/aymanbagabas/go-osc52/v2 v2.0.1 // indirect
 	github.com/charmbracelet/colorprofile v0.2.3-0.20250311203215-f60798e515dc // indirect
@@ -29,7 +30,6 @@ require (
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/mattn/go-localereader v0.0.1 // indirect
 	github.com/muesli/ansi v0.0.0-20230316100256-276c6243b2f6 // indirect
-	github.com/muesli/cancelreader v0.2.2 // indirect
 	github.com/muesli/termenv v0.16.0 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/xo/terminfo v0.0.0-20220910002029-abceb7e1c41e // indirect
@@ -0,0 +1,475 @@
+package input
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const esc = 0x1b
+
+var pasteEnd = []byte("\x1b[201~")
+
+// pasteStart marks the start of a bracketed paste; the decoder collects the
+// pasted text and reports it as one PasteEvent
+type pasteStart struct{}
+
+func (pasteStart) isEvent() {}
+
+// Decoder turns raw terminal input into events. Input may arrive split at
+// any byte, so sequences that are not yet complete are held back until more
+// input arrives. A lone ESC is ambiguous: it is either the Escape key or the
+// start of a sequence, so it is held back too; call Flush once no more
+// input has arrived within an escape timeout to report it as a key. Reader
+// does this automatically.
+//
+// Once the terminal acknowledges kitty keyboard enhancements (see
+// EnableKeyboardEnhancements) the Escape key arrives as a sequence of its
+// own, so a lone ESC is no longer ambiguous and is not flushed as a key.
+type Decoder struct {
+	buf      []byte
+	pasting  bool
+	paste    []byte
+	keyboard KeyboardFlags
+}
+
+// NewDecoder creates a decoder
+func NewDecoder() *Decoder {
+	return &Decoder{}
+}
+
+// Feed decodes input bytes, returning the events completed by them
+func (d *Decoder) Feed(p []byte) []Event {
+	d.buf = append(d.buf, p...)
+	return d.decode(false)
+}
+
+// Flush decodes any input held back waiting for the rest of a sequence. A
+// lone ESC becomes the Escape key, and an unfinished sequence is read as
+// Alt plus its second byte followed by the remaining bytes as keys. A
+// bracketed paste in progress is left to complete.
+func (d *Decoder) Flush() []Event {
+	return d.decode(true)
+}
+
+// Pending reports whether input is held back waiting for the rest of a
+// sequence, and so whether Flush should be called after a timeout
+func (d *Decoder) Pending() bool {
+	return len(d.buf) > 0 && !d.pasting && d.keyboard&KeyboardDisambiguate == 0
+}
+
+// KeyboardFlags returns the kitty keyboard enhancement flags last reported
+// by the terminal, or 0 when input is in legacy form
+func (d *Decoder) KeyboardFlags() KeyboardFlags {
+	return d.keyboard
+}
+
+func (d *Decoder) decode(flush bool) []Event {
+	var events []Event
+	for len(d.buf) > 0 {
+		if d.pasting {
+			if !d.collectPaste() {
+				break
+			}
+			events = append(events, PasteEvent{Text: normalizeNewlines(string(d.paste))})
+			d.paste = nil
+			continue
+		}
+
+		event, n := decodeEvent(d.buf, flush)
+		if n == 0 {
+			break
+		}
+		d.buf = d.buf[n:]
+
+		switch e := event.(type) {
+		case pasteStart:
+			d.pasting = true
+		case KeyboardFlagsEvent:
+			d.keyboard = e.Flags
+			events = append(events, event)
+		default:
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// collectPaste moves pasted text from the input buffer to the paste buffer,
+// reporting whether the end of the paste was reached
+func (d *Decoder) collectPaste() bool {
+	if end := bytes.Index(d.buf, pasteEnd); end >= 0 {
+		d.paste = append(d.paste, d.buf[:end]...)
+		d.buf = d.buf[end+len(pasteEnd):]
+		d.pasting = false
+		return true
+	}
+
+	// Keep what may be the start of the terminator for the next read
+	keep := 0
+	for n := min(len(d.buf), len(pasteEnd)-1); n > 0; n-- {
+		if bytes.HasSuffix(d.buf, pasteEnd[:n]) {
+			keep = n
+			break
+		}
+	}
+	d.paste = append(d.paste, d.buf[:len(d.buf)-keep]...)
+	d.buf = d.buf[len(d.buf)-keep:]
+	return false
+}
+
+// normalizeNewlines converts the carriage returns terminals send for line
+// breaks in pasted text to newlines
+func normalizeNewlines(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.ReplaceAll(s, "\r", "\n")
+}
+
+// decodeEvent decodes the event at the start of buf, returning it and the
+// number of bytes it used. It returns 0 bytes when the event is incomplete,
+// unless flush is set.
+func decodeEvent(buf []byte, flush bool) (Event, int) {
+	b := buf[0]
+	switch {
+	case b == esc:
+		return decodeEscape(buf, flush)
+	case b < 0x20 || b == 0x7f:
+		return controlKey(b), 1
+	case b < utf8.RuneSelf:
+		return KeyEvent{Key: KeyRune, Rune: rune(b)}, 1
+	}
+
+	if !utf8.FullRune(buf) && !flush {
+		return nil, 0
+	}
+	r, n := utf8.DecodeRune(buf)
+	if r == utf8.RuneError {
+		return UnknownEvent{Sequence: string(buf[:n])}, n
+	}
+	return KeyEvent{Key: KeyRune, Rune: r}, n
+}
+
+// controlKey decodes a C0 control byte or DEL. Ctrl+H and Backspace cannot
+// be told apart when a terminal sends BS for Backspace; BS is read as
+// Ctrl+H and DEL as Backspace, as most terminals send.
+func controlKey(b byte) KeyEvent {
+	switch b {
+	case '\r':
+		return KeyEvent{Key: KeyEnter}
+	case '\t':
+		return KeyEvent{Key: KeyTab}
+	case 0x7f:
+		return KeyEvent{Key: KeyBackspace}
+	case 0x00:
+		return KeyEvent{Key: KeyRune, Rune: ' ', Mod: ModCtrl}
+	}
+	if b <= 0x1a {
+		return KeyEvent{Key: KeyRune, Rune: rune('a' + b - 1), Mod: ModCtrl}
+	}
+	// 0x1c–0x1f: Ctrl+\ Ctrl+] Ctrl+^ Ctrl+_
+	return KeyEvent{Key: KeyRune, Rune: rune(b + 0x40), Mod: ModCtrl}
+}
+
+// decodeEscape decodes a sequence starting with ESC: a control sequence, a
+// control string, or a key pressed with Alt
+func decodeEscape(buf []byte, flush bool) (Event, int) {
+	if len(buf) == 1 {
+		if !flush {
+			return nil, 0
+		}
+		return KeyEvent{Key: KeyEscape}, 1
+	}
+
+	var event Event
+	n := 0
+	switch buf[1] {
+	case '[':
+		event, n = decodeCSI(buf)
+	case 'O':
+		event, n = decodeSS3(buf)
+	case ']', 'P':
+		event, n = decodeControlString(buf)
+	default:
+		// Alt sends ESC before the key
+		event, n = decodeEvent(buf[1:], flush)
+		if n == 0 {
+			return nil, 0
+		}
+		if key, ok := event.(KeyEvent); ok {
+			key.Mod |= ModAlt
+			return key, n + 1
+		}
+		return KeyEvent{Key: KeyEscape}, 1
+	}
+
+	if n > 0 {
+		return event, n
+	}
+	if !flush {
+		return nil, 0
+	}
+	// The sequence never finished; it was Alt with [, O, ] or P
+	return KeyEvent{Key: KeyRune, Rune: rune(buf[1]), Mod: ModAlt}, 2
+}
+
+// decodeCSI decodes a control sequence: ESC [, parameter bytes,
+// intermediate bytes and a final byte
+func decodeCSI(buf []byte) (Event, int) {
+	i := 2
+	for i < len(buf) && buf[i] >= 0x30 && buf[i] <= 0x3f {
+		i++
+	}
+	paramsEnd := i
+	for i < len(buf) && buf[i] >= 0x20 && buf[i] <= 0x2f {
+		i++
+	}
+	if i == len(buf) {
+		return nil, 0
+	}
+
+	final := buf[i]
+	if final < 0x40 || final > 0x7e {
+		// Malformed; report what was read and continue after it
+		return UnknownEvent{Sequence: string(buf[:i])}, i
+	}
+
+	seq := csiSequence{
+		raw:          string(buf[:i+1]),
+		intermediate: string(buf[paramsEnd:i]),
+		final:        final,
+	}
+	params := string(buf[2:paramsEnd])
+	if params != "" && strings.IndexByte("<=>?", params[0]) >= 0 {
+		seq.marker = params[0]
+		params = params[1:]
+	}
+	seq.params = parseParams(params)
+	return seq.event(), i + 1
+}
+
+// csiSequence is a parsed control sequence
+type csiSequence struct {
+	raw          string
+	marker       byte    // Private marker: <, =, > or ?
+	params       [][]int // Parameters, each with its colon-separated subparameters
+	intermediate string
+	final        byte
+}
+
+// param returns a parameter's first value, or def when it is missing
+func (s csiSequence) param(i, def int) int {
+	if i >= len(s.params) || len(s.params[i]) == 0 || s.params[i][0] < 0 {
+		return def
+	}
+	return s.params[i][0]
+}
+
+// parseParams splits parameters on semicolons and subparameters on colons.
+// Empty values are -1.
+func parseParams(s string) [][]int {
+	if s == "" {
+		return nil
+	}
+	var params [][]int
+	for _, field := range strings.Split(s, ";") {
+		var values []int
+		for _, sub := range strings.Split(field, ":") {
+			value, err := strconv.Atoi(sub)
+			if err != nil {
+				value = -1
+			}
+			values = append(values, value)
+		}
+		params = append(params, values)
+	}
+	return params
+}
+
+func (s csiSequence) event() Event {
+	switch s.marker {
+	case '<':
+		if s.final == 'M' || s.final == 'm' {
+			return s.mouseEvent()
+		}
+	case '?':
+		switch {
+		case s.final == 'c':
+			params := make([]int, len(s.params))
+			for i := range s.params {
+				params[i] = s.param(i, 0)
+			}
+			return DeviceAttributesEvent{Params: params}
+		case s.final == 'y' && s.intermediate == "$":
+			return ModeReportEvent{Mode: s.param(0, 0), Value: s.param(1, 0)}
+		case s.final == 'u' && s.intermediate == "":
+			return KeyboardFlagsEvent{Flags: KeyboardFlags(s.param(0, 0))}
+		}
+	case 0:
+		if s.intermediate == "" {
+			if event := s.keyEvent(); event != nil {
+				return event
+			}
+		}
+	}
+	return UnknownEvent{Sequence: s.raw}
+}
+
+// csiKeys maps the final bytes of cursor and function key sequences
+var csiKeys = map[byte]Key{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'S': KeyF4,
+}
+
+// tildeKeys maps the first parameter of CSI ~ sequences
+var tildeKeys = map[int]Key{
+	1:  KeyHome,
+	2:  KeyInsert,
+	3:  KeyDelete,
+	4:  KeyEnd,
+	5:  KeyPageUp,
+	6:  KeyPageDown,
+	7:  KeyHome,
+	8:  KeyEnd,
+	11: KeyF1,
+	12: KeyF2,
+	13: KeyF3,
+	14: KeyF4,
+	15: KeyF5,
+	17: KeyF6,
+	18: KeyF7,
+	19: KeyF8,
+	20: KeyF9,
+	21: KeyF10,
+	23: KeyF11,
+	24: KeyF12,
+}
+
+// keyEvent decodes key sequences, in legacy and kitty form, and the
+// replies and reports that share their unmarked form. CSI row;col R is read
+// as a cursor position report rather than F3 with modifiers.
+func (s csiSequence) keyEvent() Event {
+	switch s.final {
+	case 'u':
+		return s.kittyKeyEvent()
+	case 'I', 'O':
+		if len(s.params) == 0 {
+			return FocusEvent{Focused: s.final == 'I'}
+		}
+	case 'R':
+		if len(s.params) == 2 {
+			return CursorPositionEvent{Row: s.param(0, 1), Col: s.param(1, 1)}
+		}
+		return KeyEvent{Key: KeyF3, Mod: parseModifier(s.param(1, 1)), Action: s.keyAction()}
+	case 'Z':
+		return KeyEvent{Key: KeyTab, Mod: ModShift}
+	case '~':
+		code := s.param(0, 0)
+		if code == 200 {
+			return pasteStart{}
+		}
+		if key, ok := tildeKeys[code]; ok {
+			return KeyEvent{Key: key, Mod: parseModifier(s.param(1, 1)), Action: s.keyAction()}
+		}
+	}
+
+	if key, ok := csiKeys[s.final]; ok {
+		return KeyEvent{Key: key, Mod: parseModifier(s.param(1, 1)), Action: s.keyAction()}
+	}
+	return nil
+}
+
+// mouseEvent decodes an SGR mouse report: CSI < button ; x ; y M (press or
+// motion) or m (release)
+func (s csiSequence) mouseEvent() Event {
+	b := s.param(0, 0)
+	event := MouseEvent{
+		X: s.param(1, 1) - 1,
+		Y: s.param(2, 1) - 1,
+	}
+
+	if b&4 != 0 {
+		event.Mod |= ModShift
+	}
+	if b&8 != 0 {
+		event.Mod |= ModAlt
+	}
+	if b&16 != 0 {
+		event.Mod |= ModCtrl
+	}
+
+	switch {
+	case b&128 != 0:
+		event.Button = []MouseButton{MouseBackward, MouseForward, MouseNone, MouseNone}[b&3]
+	case b&64 != 0:
+		event.Button = []MouseButton{MouseWheelUp, MouseWheelDown, MouseWheelLeft, MouseWheelRight}[b&3]
+	default:
+		event.Button = []MouseButton{MouseLeft, MouseMiddle, MouseRight, MouseNone}[b&3]
+	}
+
+	switch {
+	case s.final == 'm':
+		event.Action = MouseRelease
+	case b&32 != 0:
+		event.Action = MouseMotion
+	default:
+		event.Action = MousePress
+	}
+	return event
+}
+
+// ss3Keys maps the final byte of SS3 sequences, sent for cursor keys in
+// application mode and for F1–F4
+var ss3Keys = map[byte]Key{
+	'A': KeyUp,
+	'B': KeyDown,
+	'C': KeyRight,
+	'D': KeyLeft,
+	'H': KeyHome,
+	'F': KeyEnd,
+	'M': KeyEnter,
+	'P': KeyF1,
+	'Q': KeyF2,
+	'R': KeyF3,
+	'S': KeyF4,
+}
+
+// decodeSS3 decodes ESC O followed by one byte
+func decodeSS3(buf []byte) (Event, int) {
+	if len(buf) < 3 {
+		return nil, 0
+	}
+	if key, ok := ss3Keys[buf[2]]; ok {
+		return KeyEvent{Key: key}, 3
+	}
+	return UnknownEvent{Sequence: string(buf[:3])}, 3
+}
+
+// decodeControlString decodes an OSC (ESC ]) or DCS (ESC P) string, ended
+// by BEL or ST (ESC \)
+func decodeControlString(buf []byte) (Event, int) {
+	for i := 2; i < len(buf); i++ {
+		end := 0
+		switch {
+		case buf[i] == '\a':
+			end = i + 1
+		case buf[i] == esc && i+1 < len(buf) && buf[i+1] == '\\':
+			end = i + 2
+		default:
+			continue
+		}
+
+		if buf[1] == ']' {
+			return OSCEvent{Data: string(buf[2:i])}, end
+		}
+		return UnknownEvent{Sequence: string(buf[:end])}, end
+	}
+	return nil, 0
+}
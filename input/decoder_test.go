@@ -0,0 +1,188 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Event
+	}{
+		{"Rune", "a", []Event{KeyEvent{Key: KeyRune, Rune: 'a'}}},
+		{"UTF8", "é漢", []Event{KeyEvent{Key: KeyRune, Rune: 'é'}, KeyEvent{Key: KeyRune, Rune: '漢'}}},
+		{"Enter", "\r", []Event{KeyEvent{Key: KeyEnter}}},
+		{"Tab", "\t", []Event{KeyEvent{Key: KeyTab}}},
+		{"Backspace", "\x7f", []Event{KeyEvent{Key: KeyBackspace}}},
+		{"CtrlC", "\x03", []Event{KeyEvent{Key: KeyRune, Rune: 'c', Mod: ModCtrl}}},
+		{"CtrlSpace", "\x00", []Event{KeyEvent{Key: KeyRune, Rune: ' ', Mod: ModCtrl}}},
+		{"CtrlBackslash", "\x1c", []Event{KeyEvent{Key: KeyRune, Rune: '\\', Mod: ModCtrl}}},
+		{"AltRune", "\x1bx", []Event{KeyEvent{Key: KeyRune, Rune: 'x', Mod: ModAlt}}},
+		{"AltCtrl", "\x1b\x01", []Event{KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModAlt | ModCtrl}}},
+		{"Up", "\x1b[A", []Event{KeyEvent{Key: KeyUp}}},
+		{"ApplicationUp", "\x1bOA", []Event{KeyEvent{Key: KeyUp}}},
+		{"CtrlRight", "\x1b[1;5C", []Event{KeyEvent{Key: KeyRight, Mod: ModCtrl}}},
+		{"AltUp", "\x1b\x1b[A", []Event{KeyEvent{Key: KeyUp, Mod: ModAlt}}},
+		{"ShiftTab", "\x1b[Z", []Event{KeyEvent{Key: KeyTab, Mod: ModShift}}},
+		{"Delete", "\x1b[3~", []Event{KeyEvent{Key: KeyDelete}}},
+		{"CtrlShiftPageUp", "\x1b[5;6~", []Event{KeyEvent{Key: KeyPageUp, Mod: ModCtrl | ModShift}}},
+		{"F1", "\x1bOP", []Event{KeyEvent{Key: KeyF1}}},
+		{"F5", "\x1b[15~", []Event{KeyEvent{Key: KeyF5}}},
+		{"ShiftF12", "\x1b[24;2~", []Event{KeyEvent{Key: KeyF12, Mod: ModShift}}},
+		{"AltF1", "\x1b[1;3P", []Event{KeyEvent{Key: KeyF1, Mod: ModAlt}}},
+		{"Unknown", "\x1b[9x", []Event{UnknownEvent{Sequence: "\x1b[9x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDecoder().Feed([]byte(tt.input))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeMouse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected MouseEvent
+	}{
+		{"LeftPress", "\x1b[<0;10;5M", MouseEvent{X: 9, Y: 4, Button: MouseLeft, Action: MousePress}},
+		{"RightRelease", "\x1b[<2;1;1m", MouseEvent{Button: MouseRight, Action: MouseRelease}},
+		{"Drag", "\x1b[<32;3;4M", MouseEvent{X: 2, Y: 3, Button: MouseLeft, Action: MouseMotion}},
+		{"Hover", "\x1b[<35;3;4M", MouseEvent{X: 2, Y: 3, Button: MouseNone, Action: MouseMotion}},
+		{"WheelDown", "\x1b[<65;1;1M", MouseEvent{Button: MouseWheelDown, Action: MousePress}},
+		{"CtrlShiftClick", "\x1b[<20;2;2M", MouseEvent{X: 1, Y: 1, Button: MouseLeft, Action: MousePress, Mod: ModCtrl | ModShift}},
+		{"Backward", "\x1b[<128;1;1M", MouseEvent{Button: MouseBackward, Action: MousePress}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDecoder().Feed([]byte(tt.input))
+			if len(got) != 1 || got[0] != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+
+	if !(MouseEvent{Button: MouseWheelUp}).IsWheel() || (MouseEvent{Button: MouseLeft}).IsWheel() {
+		t.Error("IsWheel should report only wheel buttons")
+	}
+}
+
+func TestDecodeReplies(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Event
+	}{
+		{"FocusIn", "\x1b[I", FocusEvent{Focused: true}},
+		{"FocusOut", "\x1b[O", FocusEvent{Focused: false}},
+		{"CursorPosition", "\x1b[12;40R", CursorPositionEvent{Row: 12, Col: 40}},
+		{"DeviceAttributes", "\x1b[?62;4;22c", DeviceAttributesEvent{Params: []int{62, 4, 22}}},
+		{"ModeReport", "\x1b[?2026;2$y", ModeReportEvent{Mode: 2026, Value: 2}},
+		{"OSCBell", "\x1b]11;rgb:0000/0000/0000\a", OSCEvent{Data: "11;rgb:0000/0000/0000"}},
+		{"OSCST", "\x1b]10;rgb:ffff/ffff/ffff\x1b\\", OSCEvent{Data: "10;rgb:ffff/ffff/ffff"}},
+		{"DCS", "\x1bP1$r0m\x1b\\", UnknownEvent{Sequence: "\x1bP1$r0m\x1b\\"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDecoder().Feed([]byte(tt.input))
+			if len(got) != 1 || !reflect.DeepEqual(got[0], tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeBracketedPaste(t *testing.T) {
+	d := NewDecoder()
+	input := []byte("a\x1b[200~line 1\r\nline \x1b[A2\x1b[201~b")
+
+	// Feed one byte at a time so every sequence is split
+	var got []Event
+	for i := range input {
+		got = append(got, d.Feed(input[i:i+1])...)
+	}
+
+	expected := []Event{
+		KeyEvent{Key: KeyRune, Rune: 'a'},
+		PasteEvent{Text: "line 1\nline \x1b[A2"},
+		KeyEvent{Key: KeyRune, Rune: 'b'},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestDecodePasteNotFlushed(t *testing.T) {
+	d := NewDecoder()
+	d.Feed([]byte("\x1b[200~partial\x1b[20"))
+	if d.Pending() {
+		t.Error("A paste in progress should not wait on the escape timeout")
+	}
+	if events := d.Flush(); len(events) != 0 {
+		t.Errorf("Flush should not end a paste, got %v", events)
+	}
+	events := d.Feed([]byte("1~"))
+	if len(events) != 1 || events[0] != (PasteEvent{Text: "partial"}) {
+		t.Errorf("Expected the paste once it ends, got %v", events)
+	}
+}
+
+func TestDecodeSplitSequences(t *testing.T) {
+	d := NewDecoder()
+	if events := d.Feed([]byte("\x1b[1;")); len(events) != 0 {
+		t.Fatalf("Expected no events for a partial sequence, got %v", events)
+	}
+	if events := d.Feed([]byte("5A")); len(events) != 1 || events[0] != (KeyEvent{Key: KeyUp, Mod: ModCtrl}) {
+		t.Errorf("Expected ctrl+up, got %v", events)
+	}
+
+	if events := d.Feed([]byte{0xe6, 0xbc}); len(events) != 0 {
+		t.Fatalf("Expected no events for a partial rune, got %v", events)
+	}
+	if events := d.Feed([]byte{0xa2}); len(events) != 1 || events[0] != (KeyEvent{Key: KeyRune, Rune: '漢'}) {
+		t.Errorf("Expected the completed rune, got %v", events)
+	}
+}
+
+func TestDecodeEscapeTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Event
+	}{
+		{"LoneEscape", "\x1b", []Event{KeyEvent{Key: KeyEscape}}},
+		{"AltEscape", "\x1b\x1b", []Event{KeyEvent{Key: KeyEscape, Mod: ModAlt}}},
+		{"AltBracket", "\x1b[", []Event{KeyEvent{Key: KeyRune, Rune: '[', Mod: ModAlt}}},
+		{"AltO", "\x1bO", []Event{KeyEvent{Key: KeyRune, Rune: 'O', Mod: ModAlt}}},
+		{"UnfinishedOSC", "\x1b]x", []Event{
+			KeyEvent{Key: KeyRune, Rune: ']', Mod: ModAlt},
+			KeyEvent{Key: KeyRune, Rune: 'x'},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder()
+			if events := d.Feed([]byte(tt.input)); len(events) != 0 {
+				t.Fatalf("Expected input to be held back, got %v", events)
+			}
+			if !d.Pending() {
+				t.Fatal("Expected pending input")
+			}
+			if got := d.Flush(); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+			if d.Pending() {
+				t.Error("Expected no pending input after Flush")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package input
+
+import "fmt"
+
+// Event is a decoded input event: a KeyEvent, MouseEvent, PasteEvent,
+// FocusEvent, a reply to a terminal query, or an UnknownEvent
+type Event interface {
+	isEvent()
+}
+
+// KeyEvent is a key press, repeat or release. Under the kitty keyboard
+// protocol Rune is the unshifted key, with the shifted and base layout keys
+// and the text produced reported when the terminal sends them.
+type KeyEvent struct {
+	Key    Key
+	Rune   rune // The key's character, when Key is KeyRune
+	Mod    Modifier
+	Action KeyAction
+
+	ShiftedRune rune   // The key with Shift applied, if reported
+	BaseRune    rune   // The key in the standard PC-101 layout, if reported
+	Text        string // Text the key produces, if reported
+}
+
+// String returns the key with its modifiers, such as "ctrl+c", "alt+enter"
+// or "shift+f5". A space is written as "space".
+func (e KeyEvent) String() string {
+	name := e.Key.String()
+	if e.Key == KeyRune {
+		name = string(e.Rune)
+		if e.Rune == ' ' {
+			name = "space"
+		}
+	}
+	if mods := e.Mod.String(); mods != "" {
+		return mods + "+" + name
+	}
+	return name
+}
+
+// MouseButton identifies the button of a mouse event
+type MouseButton int
+
+const (
+	MouseNone MouseButton = iota // Motion with no button held
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+	MouseWheelLeft
+	MouseWheelRight
+	MouseBackward
+	MouseForward
+)
+
+// MouseAction is what happened to the mouse
+type MouseAction int
+
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	MouseMotion
+)
+
+// MouseEvent is a mouse press, release, motion or wheel event reported in
+// SGR (mode 1006) encoding. X and Y are zero-based cells.
+type MouseEvent struct {
+	X, Y   int
+	Button MouseButton
+	Action MouseAction
+	Mod    Modifier
+}
+
+// IsWheel reports whether the event is a wheel scroll
+func (e MouseEvent) IsWheel() bool {
+	return e.Button >= MouseWheelUp && e.Button <= MouseWheelRight
+}
+
+// PasteEvent is text pasted while bracketed paste (mode 2004) is enabled
+type PasteEvent struct {
+	Text string
+}
+
+// FocusEvent reports the terminal gaining or losing focus while focus
+// reporting (mode 1004) is enabled
+type FocusEvent struct {
+	Focused bool
+}
+
+// CursorPositionEvent is a cursor position report (CSI 6n). Row and Col are
+// one-based, as reported.
+type CursorPositionEvent struct {
+	Row, Col int
+}
+
+// DeviceAttributesEvent is a primary device attributes reply (CSI c)
+type DeviceAttributesEvent struct {
+	Params []int
+}
+
+// ModeReportEvent is a DECRQM reply (CSI ? mode $ p). Value is 0 when the
+// mode is not recognized, 1 or 2 when it is set or reset, and 3 or 4 when
+// it is permanently set or reset.
+type ModeReportEvent struct {
+	Mode  int
+	Value int
+}
+
+// OSCEvent is an operating system command reply, such as a color query
+// answer. Data is the text between ESC ] and the terminator.
+type OSCEvent struct {
+	Data string
+}
+
+// KeyboardFlagsEvent is the reply to a kitty keyboard protocol query
+// (CSI ? u), reporting the enhancement flags in effect. Terminals without
+// the protocol do not reply.
+type KeyboardFlagsEvent struct {
+	Flags KeyboardFlags
+}
+
+// UnknownEvent is a sequence the decoder does not recognize
+type UnknownEvent struct {
+	Sequence string
+}
+
+// String returns the sequence quoted, with escapes made visible
+func (e UnknownEvent) String() string {
+	return fmt.Sprintf("%q", e.Sequence)
+}
+
+func (KeyEvent) isEvent()              {}
+func (MouseEvent) isEvent()            {}
+func (PasteEvent) isEvent()            {}
+func (FocusEvent) isEvent()            {}
+func (CursorPositionEvent) isEvent()   {}
+func (DeviceAttributesEvent) isEvent() {}
+func (ModeReportEvent) isEvent()       {}
+func (OSCEvent) isEvent()              {}
+func (KeyboardFlagsEvent) isEvent()    {}
+func (UnknownEvent) isEvent()          {}
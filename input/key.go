@@ -0,0 +1,171 @@
+package input
+
+import "strings"
+
+// Key identifies a key that does not produce text. Keys that produce text
+// are reported as KeyRune with the rune in KeyEvent.Rune.
+type Key int
+
+const (
+	KeyRune Key = iota // A text key; see KeyEvent.Rune
+	KeyEnter
+	KeyTab
+	KeyBackspace
+	KeyEscape
+	KeyUp
+	KeyDown
+	KeyRight
+	KeyLeft
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+	KeyCapsLock
+	KeyScrollLock
+	KeyNumLock
+	KeyPrintScreen
+	KeyPause
+	KeyMenu
+	KeyLeftShift
+	KeyLeftCtrl
+	KeyLeftAlt
+	KeyLeftSuper
+	KeyRightShift
+	KeyRightCtrl
+	KeyRightAlt
+	KeyRightSuper
+)
+
+var keyNames = map[Key]string{
+	KeyEnter:     "enter",
+	KeyTab:       "tab",
+	KeyBackspace: "backspace",
+	KeyEscape:    "esc",
+	KeyUp:        "up",
+	KeyDown:      "down",
+	KeyRight:     "right",
+	KeyLeft:      "left",
+	KeyHome:      "home",
+	KeyEnd:       "end",
+	KeyPageUp:    "pgup",
+	KeyPageDown:  "pgdown",
+	KeyInsert:    "insert",
+	KeyDelete:    "delete",
+	KeyF1:        "f1",
+	KeyF2:        "f2",
+	KeyF3:        "f3",
+	KeyF4:        "f4",
+	KeyF5:        "f5",
+	KeyF6:        "f6",
+	KeyF7:        "f7",
+	KeyF8:        "f8",
+	KeyF9:        "f9",
+	KeyF10:       "f10",
+	KeyF11:       "f11",
+	KeyF12:       "f12",
+
+	KeyCapsLock:    "capslock",
+	KeyScrollLock:  "scrolllock",
+	KeyNumLock:     "numlock",
+	KeyPrintScreen: "printscreen",
+	KeyPause:       "pause",
+	KeyMenu:        "menu",
+	KeyLeftShift:   "leftshift",
+	KeyLeftCtrl:    "leftctrl",
+	KeyLeftAlt:     "leftalt",
+	KeyLeftSuper:   "leftsuper",
+	KeyRightShift:  "rightshift",
+	KeyRightCtrl:   "rightctrl",
+	KeyRightAlt:    "rightalt",
+	KeyRightSuper:  "rightsuper",
+}
+
+// String returns the key's name, such as "enter" or "f5"
+func (k Key) String() string {
+	if k == KeyRune {
+		return "rune"
+	}
+	if name, ok := keyNames[k]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// KeyAction distinguishes presses from repeats and releases. Legacy
+// terminal input only reports presses; repeats and releases need the kitty
+// keyboard protocol with KeyboardReportEventTypes.
+type KeyAction int
+
+const (
+	KeyPress KeyAction = iota
+	KeyRepeat
+	KeyRelease
+)
+
+// Modifier is a set of modifier keys held during a key or mouse event. The
+// bits follow the xterm and kitty encoding, where a modifier parameter is
+// one more than the bit set.
+type Modifier uint8
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
+	ModSuper
+	ModHyper
+	ModMeta
+	ModCapsLock
+	ModNumLock
+)
+
+// modifierNames lists modifiers in the order they are written in key names
+var modifierNames = []struct {
+	mod  Modifier
+	name string
+}{
+	{ModCtrl, "ctrl"},
+	{ModAlt, "alt"},
+	{ModShift, "shift"},
+	{ModSuper, "super"},
+	{ModHyper, "hyper"},
+	{ModMeta, "meta"},
+}
+
+// Contains reports whether every modifier in other is held
+func (m Modifier) Contains(other Modifier) bool {
+	return m&other == other
+}
+
+// String returns the held modifiers joined with "+", such as "ctrl+shift".
+// Lock keys are not included.
+func (m Modifier) String() string {
+	var names []string
+	for _, mn := range modifierNames {
+		if m&mn.mod != 0 {
+			names = append(names, mn.name)
+		}
+	}
+	return strings.Join(names, "+")
+}
+
+// parseModifier converts a CSI modifier parameter (1 + bits) to a Modifier
+func parseModifier(param int) Modifier {
+	if param <= 1 {
+		return 0
+	}
+	return Modifier(param - 1)
+}
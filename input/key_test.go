@@ -0,0 +1,33 @@
+package input
+
+import "testing"
+
+func TestKeyEventString(t *testing.T) {
+	tests := []struct {
+		event    KeyEvent
+		expected string
+	}{
+		{KeyEvent{Key: KeyRune, Rune: 'a'}, "a"},
+		{KeyEvent{Key: KeyRune, Rune: ' '}, "space"},
+		{KeyEvent{Key: KeyRune, Rune: 'c', Mod: ModCtrl}, "ctrl+c"},
+		{KeyEvent{Key: KeyEnter, Mod: ModAlt}, "alt+enter"},
+		{KeyEvent{Key: KeyF5, Mod: ModShift | ModCtrl}, "ctrl+shift+f5"},
+		{KeyEvent{Key: KeyUp, Mod: ModCapsLock}, "up"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.event.String(); got != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestModifierContains(t *testing.T) {
+	mod := ModCtrl | ModShift
+	if !mod.Contains(ModCtrl) || !mod.Contains(ModCtrl|ModShift) {
+		t.Error("Expected ctrl+shift to contain ctrl and ctrl+shift")
+	}
+	if mod.Contains(ModAlt) {
+		t.Error("Expected ctrl+shift not to contain alt")
+	}
+}
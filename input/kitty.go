@@ -0,0 +1,146 @@
+package input
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KeyboardFlags are the kitty keyboard protocol's progressive enhancement
+// flags
+type KeyboardFlags int
+
+const (
+	// KeyboardDisambiguate sends keys that are ambiguous in legacy input,
+	// such as Ctrl+I and Tab or Escape and Alt, as distinct sequences
+	KeyboardDisambiguate KeyboardFlags = 1 << iota
+
+	// KeyboardReportEventTypes reports repeats and releases as well as presses
+	KeyboardReportEventTypes
+
+	// KeyboardReportAlternateKeys reports the shifted and base layout keys
+	KeyboardReportAlternateKeys
+
+	// KeyboardReportAllKeys sends every key, including text keys and lone
+	// modifiers, as an escape sequence
+	KeyboardReportAllKeys
+
+	// KeyboardReportText reports the text a key produces
+	KeyboardReportText
+)
+
+// EnableKeyboardEnhancements returns the sequence that pushes flags onto
+// the terminal's keyboard mode stack and then asks for the flags in effect.
+// A terminal with the kitty keyboard protocol replies with a
+// KeyboardFlagsEvent, after which the decoder stops treating a lone ESC as
+// ambiguous. The query is followed by a primary device attributes request,
+// which every terminal answers: a DeviceAttributesEvent arriving without a
+// KeyboardFlagsEvent before it means the protocol is not supported and
+// input stays in legacy form.
+func EnableKeyboardEnhancements(flags KeyboardFlags) string {
+	return fmt.Sprintf("\x1b[>%du\x1b[?u\x1b[c", flags)
+}
+
+// DisableKeyboardEnhancements returns the sequence that pops the flags
+// pushed by EnableKeyboardEnhancements, restoring the previous mode. It is
+// ignored by terminals without the protocol.
+func DisableKeyboardEnhancements() string {
+	return "\x1b[<u"
+}
+
+// kittyKeys maps the key codes of functional keys under the kitty keyboard
+// protocol. Keypad keys map to the keys they duplicate.
+var kittyKeys = map[int]Key{
+	27:    KeyEscape,
+	13:    KeyEnter,
+	9:     KeyTab,
+	127:   KeyBackspace,
+	57358: KeyCapsLock,
+	57359: KeyScrollLock,
+	57360: KeyNumLock,
+	57361: KeyPrintScreen,
+	57362: KeyPause,
+	57363: KeyMenu,
+	57414: KeyEnter,
+	57417: KeyLeft,
+	57418: KeyRight,
+	57419: KeyUp,
+	57420: KeyDown,
+	57421: KeyPageUp,
+	57422: KeyPageDown,
+	57423: KeyHome,
+	57424: KeyEnd,
+	57425: KeyInsert,
+	57426: KeyDelete,
+	57441: KeyLeftShift,
+	57442: KeyLeftCtrl,
+	57443: KeyLeftAlt,
+	57444: KeyLeftSuper,
+	57447: KeyRightShift,
+	57448: KeyRightCtrl,
+	57449: KeyRightAlt,
+	57450: KeyRightSuper,
+}
+
+// kittyKeypadRunes maps keypad keys that produce text
+var kittyKeypadRunes = map[int]rune{
+	57399: '0', 57400: '1', 57401: '2', 57402: '3', 57403: '4',
+	57404: '5', 57405: '6', 57406: '7', 57407: '8', 57408: '9',
+	57409: '.', 57410: '/', 57411: '*', 57412: '-', 57413: '+',
+	57415: '=', 57416: ',',
+}
+
+// kittyKeyEvent decodes CSI code:shifted:base ; modifiers:action ; text u
+func (s csiSequence) kittyKeyEvent() Event {
+	code := s.param(0, -1)
+	if code < 0 {
+		return nil
+	}
+
+	event := KeyEvent{
+		Mod:    parseModifier(s.param(1, 1)),
+		Action: s.keyAction(),
+	}
+	if key, ok := kittyKeys[code]; ok {
+		event.Key = key
+	} else if r, ok := kittyKeypadRunes[code]; ok {
+		event.Key, event.Rune = KeyRune, r
+	} else if code >= 57344 && code <= 63743 {
+		// Other keys in the private use area have no Key yet
+		return nil
+	} else {
+		event.Key, event.Rune = KeyRune, rune(code)
+	}
+
+	if len(s.params) > 0 {
+		if alternates := s.params[0]; len(alternates) > 1 && alternates[1] > 0 {
+			event.ShiftedRune = rune(alternates[1])
+		}
+		if alternates := s.params[0]; len(alternates) > 2 && alternates[2] > 0 {
+			event.BaseRune = rune(alternates[2])
+		}
+	}
+	if len(s.params) > 2 {
+		var text strings.Builder
+		for _, r := range s.params[2] {
+			if r > 0 {
+				text.WriteRune(rune(r))
+			}
+		}
+		event.Text = text.String()
+	}
+	return event
+}
+
+// keyAction reads the event type subparameter of the modifier parameter
+func (s csiSequence) keyAction() KeyAction {
+	if len(s.params) < 2 || len(s.params[1]) < 2 {
+		return KeyPress
+	}
+	switch s.params[1][1] {
+	case 2:
+		return KeyRepeat
+	case 3:
+		return KeyRelease
+	}
+	return KeyPress
+}
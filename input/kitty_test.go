@@ -0,0 +1,86 @@
+package input
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeKittyKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected KeyEvent
+	}{
+		{"CtrlI", "\x1b[105;5u", KeyEvent{Key: KeyRune, Rune: 'i', Mod: ModCtrl}},
+		{"Tab", "\x1b[9u", KeyEvent{Key: KeyTab}},
+		{"Escape", "\x1b[27u", KeyEvent{Key: KeyEscape}},
+		{"ShiftEnter", "\x1b[13;2u", KeyEvent{Key: KeyEnter, Mod: ModShift}},
+		{"Repeat", "\x1b[97;1:2u", KeyEvent{Key: KeyRune, Rune: 'a', Action: KeyRepeat}},
+		{"Release", "\x1b[97;1:3u", KeyEvent{Key: KeyRune, Rune: 'a', Action: KeyRelease}},
+		{"ReleaseUp", "\x1b[1;1:3A", KeyEvent{Key: KeyUp, Action: KeyRelease}},
+		{"RepeatDelete", "\x1b[3;5:2~", KeyEvent{Key: KeyDelete, Mod: ModCtrl, Action: KeyRepeat}},
+		{"F3", "\x1b[13~", KeyEvent{Key: KeyF3}},
+		{"Alternates", "\x1b[97:65:113;2u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModShift, ShiftedRune: 'A', BaseRune: 'q'}},
+		{"Text", "\x1b[97;2;65u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModShift, Text: "A"}},
+		{"TextAndShiftedKey", "\x1b[97:65;2;65u", KeyEvent{Key: KeyRune, Rune: 'a', Mod: ModShift, ShiftedRune: 'A', Text: "A"}},
+		{"CapsLockSuper", "\x1b[115;73u", KeyEvent{Key: KeyRune, Rune: 's', Mod: ModSuper | ModCapsLock}},
+		{"KeypadDigit", "\x1b[57404u", KeyEvent{Key: KeyRune, Rune: '5'}},
+		{"KeypadEnter", "\x1b[57414u", KeyEvent{Key: KeyEnter}},
+		{"LeftShift", "\x1b[57441;2u", KeyEvent{Key: KeyLeftShift, Mod: ModShift}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewDecoder().Feed([]byte(tt.input))
+			if len(got) != 1 || !reflect.DeepEqual(got[0], tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDecodeKittyUnknownPrivateKey(t *testing.T) {
+	got := NewDecoder().Feed([]byte("\x1b[57376u"))
+	if len(got) != 1 || got[0] != (UnknownEvent{Sequence: "\x1b[57376u"}) {
+		t.Errorf("Expected an unknown event, got %v", got)
+	}
+}
+
+func TestKeyboardNegotiation(t *testing.T) {
+	if got := EnableKeyboardEnhancements(KeyboardDisambiguate | KeyboardReportEventTypes); got != "\x1b[>3u\x1b[?u\x1b[c" {
+		t.Errorf("Unexpected enable sequence %q", got)
+	}
+	if got := DisableKeyboardEnhancements(); got != "\x1b[<u" {
+		t.Errorf("Unexpected disable sequence %q", got)
+	}
+
+	// A terminal with the protocol reports its flags before the device
+	// attributes, and a lone ESC then stops waiting on the escape timeout
+	d := NewDecoder()
+	events := d.Feed([]byte("\x1b[?1u\x1b[?62;22c"))
+	expected := []Event{
+		KeyboardFlagsEvent{Flags: KeyboardDisambiguate},
+		DeviceAttributesEvent{Params: []int{62, 22}},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+	if d.KeyboardFlags() != KeyboardDisambiguate {
+		t.Errorf("Expected disambiguate flag, got %d", d.KeyboardFlags())
+	}
+	d.Feed([]byte("\x1b"))
+	if d.Pending() {
+		t.Error("A lone ESC should not be ambiguous once keys are disambiguated")
+	}
+	if events := d.Feed([]byte("[27u")); len(events) != 1 || events[0] != (KeyEvent{Key: KeyEscape}) {
+		t.Errorf("Expected esc, got %v", events)
+	}
+
+	// Without the protocol only the device attributes arrive and input
+	// stays in legacy form
+	legacy := NewDecoder()
+	legacy.Feed([]byte("\x1b[?62;22c\x1b"))
+	if legacy.KeyboardFlags() != 0 || !legacy.Pending() {
+		t.Error("Expected legacy decoding with a pending ESC")
+	}
+}
@@ -0,0 +1,124 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultEscapeTimeout is how long a lone ESC waits for the rest of a
+// sequence before it is reported as the Escape key
+const DefaultEscapeTimeout = 50 * time.Millisecond
+
+// Reader reads terminal input and delivers decoded events on a channel,
+// flushing a lone ESC as the Escape key once no further input arrives
+// within the escape timeout. The input should be a terminal in raw mode.
+type Reader struct {
+	in      io.Reader
+	decoder *Decoder
+	timeout time.Duration
+	events  chan Event
+
+	mu      sync.Mutex
+	started bool
+	err     error
+}
+
+// NewReader creates a reader decoding events from in. A zero
+// escapeTimeout uses DefaultEscapeTimeout. Call Start to begin reading.
+func NewReader(in io.Reader, escapeTimeout time.Duration) *Reader {
+	if escapeTimeout <= 0 {
+		escapeTimeout = DefaultEscapeTimeout
+	}
+	return &Reader{
+		in:      in,
+		decoder: NewDecoder(),
+		timeout: escapeTimeout,
+		events:  make(chan Event, 64),
+	}
+}
+
+// Start begins reading in background goroutines
+func (r *Reader) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.started {
+		return
+	}
+	r.started = true
+
+	chunks := make(chan []byte)
+	go r.read(chunks)
+	go r.run(chunks)
+}
+
+// Events returns the channel events are delivered on. It is closed when the
+// input ends or fails.
+func (r *Reader) Events() <-chan Event {
+	return r.events
+}
+
+// Err returns the error that ended reading, or nil if the input ended
+// normally or is still being read
+func (r *Reader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// read passes input to run as it arrives
+func (r *Reader) read(chunks chan<- []byte) {
+	defer close(chunks)
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.in.Read(buf)
+		if n > 0 {
+			chunks <- append([]byte(nil), buf[:n]...)
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				r.mu.Lock()
+				r.err = err
+				r.mu.Unlock()
+			}
+			return
+		}
+	}
+}
+
+// run decodes input and flushes held-back input after the escape timeout
+func (r *Reader) run(chunks <-chan []byte) {
+	defer close(r.events)
+
+	var timer *time.Timer
+	var timeout <-chan time.Time
+	for {
+		select {
+		case chunk, ok := <-chunks:
+			if !ok {
+				r.send(r.decoder.Flush())
+				return
+			}
+			r.send(r.decoder.Feed(chunk))
+		case <-timeout:
+			r.send(r.decoder.Flush())
+		}
+
+		if timer != nil {
+			timer.Stop()
+			timer, timeout = nil, nil
+		}
+		if r.decoder.Pending() {
+			timer = time.NewTimer(r.timeout)
+			timeout = timer.C
+		}
+	}
+}
+
+func (r *Reader) send(events []Event) {
+	for _, event := range events {
+		r.events <- event
+	}
+}
@@ -0,0 +1,59 @@
+package input
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func nextEvent(t *testing.T, r *Reader) Event {
+	t.Helper()
+	select {
+	case event := <-r.Events():
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for an event")
+		return nil
+	}
+}
+
+func TestReaderEscapeTimeout(t *testing.T) {
+	in, w := io.Pipe()
+	r := NewReader(in, 10*time.Millisecond)
+	r.Start()
+
+	w.Write([]byte("\x1b"))
+	if event := nextEvent(t, r); event != (KeyEvent{Key: KeyEscape}) {
+		t.Errorf("Expected esc after the timeout, got %v", event)
+	}
+
+	// A sequence arriving in pieces within the timeout stays one event
+	w.Write([]byte("\x1b["))
+	w.Write([]byte("B"))
+	if event := nextEvent(t, r); event != (KeyEvent{Key: KeyDown}) {
+		t.Errorf("Expected down, got %v", event)
+	}
+
+	w.Close()
+	if _, ok := <-r.Events(); ok {
+		t.Error("Expected events to close at end of input")
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("Expected no error at end of input, got %v", err)
+	}
+}
+
+func TestReaderError(t *testing.T) {
+	in, w := io.Pipe()
+	r := NewReader(in, 0)
+	r.Start()
+
+	failure := errors.New("read failed")
+	w.CloseWithError(failure)
+	for range r.Events() {
+	}
+	if err := r.Err(); !errors.Is(err, failure) {
+		t.Errorf("Expected %v, got %v", failure, err)
+	}
+}
@@ -0,0 +1,123 @@
+package keymap
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SCKelemen/cli/input"
+)
+
+// keysByName maps key names, as written by input.Key.String, to keys
+var keysByName = func() map[string]input.Key {
+	names := map[string]input.Key{}
+	for k := input.KeyRune + 1; k < input.KeyRune+256; k++ {
+		if name := k.String(); name != "unknown" {
+			names[name] = k
+		}
+	}
+	names["escape"] = input.KeyEscape
+	names["return"] = input.KeyEnter
+	names["pageup"] = input.KeyPageUp
+	names["pagedown"] = input.KeyPageDown
+	names["del"] = input.KeyDelete
+	names["ins"] = input.KeyInsert
+	return names
+}()
+
+var modifiersByName = map[string]input.Modifier{
+	"shift":   input.ModShift,
+	"alt":     input.ModAlt,
+	"opt":     input.ModAlt,
+	"option":  input.ModAlt,
+	"ctrl":    input.ModCtrl,
+	"control": input.ModCtrl,
+	"super":   input.ModSuper,
+	"cmd":     input.ModSuper,
+	"hyper":   input.ModHyper,
+	"meta":    input.ModMeta,
+}
+
+// ParseChord parses a key with modifiers, such as "ctrl+s", "alt+enter",
+// "G" or "space", into the form KeyEvent.String uses for the same key.
+// Modifier and key names are case-insensitive except for single characters,
+// and shift on a letter is written as the upper-case letter.
+func ParseChord(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", fmt.Errorf("keymap: empty key")
+	}
+
+	// The key itself may be a plus sign, as in "ctrl++"
+	name, prefix := s, ""
+	if strings.HasSuffix(s, "++") || s == "+" {
+		name, prefix = "+", strings.TrimSuffix(s[:len(s)-1], "+")
+	} else if i := strings.LastIndex(s, "+"); i >= 0 {
+		name, prefix = s[i+1:], s[:i]
+	}
+
+	var mod input.Modifier
+	if prefix != "" {
+		for _, part := range strings.Split(prefix, "+") {
+			m, ok := modifiersByName[strings.ToLower(part)]
+			if !ok {
+				return "", fmt.Errorf("keymap: unknown modifier %q in %q", part, s)
+			}
+			mod |= m
+		}
+	}
+
+	event := input.KeyEvent{Mod: mod}
+	switch {
+	case utf8.RuneCountInString(name) == 1:
+		event.Key = input.KeyRune
+		event.Rune, _ = utf8.DecodeRuneInString(name)
+	case strings.EqualFold(name, "space"):
+		event.Key, event.Rune = input.KeyRune, ' '
+	default:
+		k, ok := keysByName[strings.ToLower(name)]
+		if !ok {
+			return "", fmt.Errorf("keymap: unknown key %q in %q", name, s)
+		}
+		event.Key = k
+	}
+	return chordOf(event), nil
+}
+
+// ParseSequence parses keys pressed one after another, separated by
+// spaces, such as "g g" or "ctrl+x ctrl+s"
+func ParseSequence(s string) ([]string, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("keymap: empty key sequence")
+	}
+
+	sequence := make([]string, len(fields))
+	for i, field := range fields {
+		chord, err := ParseChord(field)
+		if err != nil {
+			return nil, err
+		}
+		sequence[i] = chord
+	}
+	return sequence, nil
+}
+
+// chordOf returns the name a key event is matched by. Lock modifiers are
+// ignored, and shift on a letter becomes the upper-case letter, the same
+// whether the terminal reports it with or without the kitty protocol.
+func chordOf(key input.KeyEvent) string {
+	event := input.KeyEvent{Key: key.Key, Rune: key.Rune, Mod: key.Mod &^ (input.ModCapsLock | input.ModNumLock)}
+	if event.Key == input.KeyRune && event.Mod&input.ModShift != 0 {
+		switch {
+		case key.ShiftedRune != 0:
+			event.Rune = key.ShiftedRune
+			event.Mod &^= input.ModShift
+		case unicode.IsLetter(event.Rune):
+			event.Rune = unicode.ToUpper(event.Rune)
+			event.Mod &^= input.ModShift
+		}
+	}
+	return event.String()
+}
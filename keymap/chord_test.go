@@ -0,0 +1,69 @@
+package keymap
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+)
+
+func TestParseChord(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"ctrl+s", "ctrl+s"},
+		{"Ctrl+Alt+Delete", "ctrl+alt+delete"},
+		{"alt+ctrl+x", "ctrl+alt+x"},
+		{"shift+g", "G"},
+		{"G", "G"},
+		{"space", "space"},
+		{"ctrl+space", "ctrl+space"},
+		{"escape", "esc"},
+		{"pageup", "pgup"},
+		{"?", "?"},
+		{"+", "+"},
+		{"ctrl++", "ctrl++"},
+		{"shift+tab", "shift+tab"},
+		{"f5", "f5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseChord(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"", "hyperspace+x", "ctrl+nosuchkey"} {
+		if _, err := ParseChord(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestChordOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    input.KeyEvent
+		expected string
+	}{
+		{"Legacy", input.KeyEvent{Key: input.KeyRune, Rune: 'G'}, "G"},
+		{"KittyShifted", input.KeyEvent{Key: input.KeyRune, Rune: 'g', Mod: input.ModShift, ShiftedRune: 'G'}, "G"},
+		{"ShiftedSymbol", input.KeyEvent{Key: input.KeyRune, Rune: '/', Mod: input.ModShift, ShiftedRune: '?'}, "?"},
+		{"LocksIgnored", input.KeyEvent{Key: input.KeyRune, Rune: 's', Mod: input.ModCtrl | input.ModNumLock}, "ctrl+s"},
+		{"ShiftArrow", input.KeyEvent{Key: input.KeyUp, Mod: input.ModShift}, "shift+up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chordOf(tt.event); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package keymap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LoadFile applies user overrides from a JSON or TOML file, chosen by its
+// extension. See LoadJSON and LoadTOML for the format.
+func (k *Keymap) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("keymap: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		err = k.LoadJSON(data)
+	case ".toml":
+		err = k.LoadTOML(data)
+	default:
+		return fmt.Errorf("keymap: unsupported config format %q", ext)
+	}
+	if err != nil {
+		return fmt.Errorf("%w (%s)", err, path)
+	}
+	return nil
+}
+
+// LoadJSON applies user overrides from a JSON object. Each global action
+// maps to a key sequence or a list of them, and each scope to an object of
+// its own actions. An empty list unbinds an action.
+//
+//	{
+//	  "quit": ["ctrl+q"],
+//	  "list": {"top": ["g g", "home"]}
+//	}
+func (k *Keymap) LoadJSON(data []byte) error {
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("keymap: %w", err)
+	}
+	return k.apply(config)
+}
+
+// LoadTOML applies user overrides from TOML: top-level keys are global
+// actions and each table is a scope. Values are a key sequence or an array
+// of them. Only tables, strings, arrays of strings and comments are
+// supported.
+//
+//	quit = ["ctrl+q"]
+//
+//	[list]
+//	top = ["g g", "home"]
+func (k *Keymap) LoadTOML(data []byte) error {
+	config, err := parseTOML(string(data))
+	if err != nil {
+		return err
+	}
+	return k.apply(config)
+}
+
+// override is one action's keys from a config
+type override struct {
+	scope, action string
+	keys          []string
+	sequences     [][]string
+}
+
+// apply overrides the bindings named in a decoded config, in sorted order
+// so the first error is stable. Keys a config gives to two actions of a
+// scope are rejected, so the result does not depend on the order, while
+// keys it moves from one action to another are taken as Override does.
+func (k *Keymap) apply(config map[string]any) error {
+	var overrides []override
+	for _, name := range sortedKeys(config) {
+		if scope, ok := config[name].(map[string]any); ok {
+			for _, action := range sortedKeys(scope) {
+				o, err := parseOverride(name, action, scope[action])
+				if err != nil {
+					return err
+				}
+				overrides = append(overrides, o)
+			}
+			continue
+		}
+		o, err := parseOverride("", name, config[name])
+		if err != nil {
+			return err
+		}
+		overrides = append(overrides, o)
+	}
+
+	for i, o := range overrides {
+		for _, other := range overrides[i+1:] {
+			if other.scope != o.scope {
+				continue
+			}
+			for _, sequence := range o.sequences {
+				for _, taken := range other.sequences {
+					if overlaps(sequence, taken) {
+						return overlapError(sequence, taken, o.scope)
+					}
+				}
+			}
+		}
+	}
+	for _, o := range overrides {
+		if err := k.Override(o.scope, o.action, o.keys...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseOverride(scope, action string, value any) (override, error) {
+	var keys []string
+	switch value := value.(type) {
+	case string:
+		keys = []string{value}
+	case []string:
+		keys = value
+	case []any:
+		for _, v := range value {
+			key, ok := v.(string)
+			if !ok {
+				return override{}, fmt.Errorf("keymap: keys of %q must be strings", action)
+			}
+			keys = append(keys, key)
+		}
+	default:
+		return override{}, fmt.Errorf("keymap: keys of %q must be a string or a list of strings", action)
+	}
+	sequences, err := parseSequences(keys)
+	if err != nil {
+		return override{}, err
+	}
+	return override{scope: scope, action: action, keys: keys, sequences: sequences}, nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
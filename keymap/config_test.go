@@ -0,0 +1,121 @@
+package keymap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+)
+
+func TestLoadConfig(t *testing.T) {
+	configs := map[string]string{
+		"keys.json": `{
+			"quit": "ctrl+q",
+			"top": ["home", "g g"],
+			"list": {"list.delete": ["x"]}
+		}`,
+		"keys.toml": `
+# Global actions
+quit = "ctrl+q"
+top = [
+  "home", # Jump to the first row
+  'g g',
+]
+
+[list]
+"list.delete" = ["x"]
+`,
+	}
+
+	for name, data := range configs {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			km := testKeymap(t)
+			if err := km.LoadFile(path); err != nil {
+				t.Fatal(err)
+			}
+
+			tests := []struct {
+				chord  rune
+				scopes []string
+				action string
+			}{
+				{'q', nil, ""},
+				{'x', []string{"list"}, "list.delete"},
+				{'d', []string{"list"}, ""},
+			}
+			for _, tt := range tests {
+				if action, _ := km.Handle(key(tt.chord), tt.scopes...); action != tt.action {
+					t.Errorf("%c: expected %q, got %q", tt.chord, tt.action, action)
+				}
+			}
+
+			for _, b := range km.Bindings() {
+				if b.Action == "top" && (len(b.Keys) != 2 || b.Keys[0] != "home" || b.Keys[1] != "g g") {
+					t.Errorf("Expected top on home and g g, got %v", b.Keys)
+				}
+				if b.Action == "quit" && (len(b.Keys) != 1 || b.Keys[0] != "ctrl+q") {
+					t.Errorf("Expected quit on ctrl+q, got %v", b.Keys)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigSwapsKeys(t *testing.T) {
+	loads := map[string]func(*Keymap) error{
+		"JSON": func(km *Keymap) error {
+			return km.LoadJSON([]byte(`{"quit": "ctrl+x ctrl+s", "save": ["q", "ctrl+c"]}`))
+		},
+		"TOML": func(km *Keymap) error { return km.LoadTOML([]byte("quit = 'ctrl+x ctrl+s'\nsave = ['q', 'ctrl+c']")) },
+	}
+
+	for name, load := range loads {
+		t.Run(name, func(t *testing.T) {
+			km := testKeymap(t)
+			if err := load(km); err != nil {
+				t.Fatal(err)
+			}
+			if action, _ := km.Handle(key('q')); action != "save" {
+				t.Errorf("Expected q to save, got %q", action)
+			}
+			km.Handle(input.KeyEvent{Key: input.KeyRune, Rune: 'x', Mod: input.ModCtrl})
+			if action, _ := km.Handle(input.KeyEvent{Key: input.KeyRune, Rune: 's', Mod: input.ModCtrl}); action != "quit" {
+				t.Errorf("Expected ctrl+x ctrl+s to quit, got %q", action)
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		load func(*Keymap) error
+	}{
+		{"UnknownAction", func(km *Keymap) error { return km.LoadJSON([]byte(`{"nope": "x"}`)) }},
+		{"UnknownScope", func(km *Keymap) error { return km.LoadJSON([]byte(`{"other": {"quit": "x"}}`)) }},
+		{"BadKey", func(km *Keymap) error { return km.LoadJSON([]byte(`{"quit": "ctrl+nosuchkey"}`)) }},
+		{"NotStrings", func(km *Keymap) error { return km.LoadJSON([]byte(`{"quit": [1]}`)) }},
+		{"InvalidJSON", func(km *Keymap) error { return km.LoadJSON([]byte(`{`)) }},
+		{"TOMLNoValue", func(km *Keymap) error { return km.LoadTOML([]byte("quit\n")) }},
+		{"TOMLUnterminated", func(km *Keymap) error { return km.LoadTOML([]byte(`quit = "ctrl+q`)) }},
+		{"TOMLNumber", func(km *Keymap) error { return km.LoadTOML([]byte("quit = 1")) }},
+		{"SharedKey", func(km *Keymap) error { return km.LoadJSON([]byte(`{"quit": "x", "save": "x"}`)) }},
+		{"TOMLSharedPrefix", func(km *Keymap) error { return km.LoadTOML([]byte("quit = 'z'\nsave = 'z z'")) }},
+		{"TOMLDuplicate", func(km *Keymap) error { return km.LoadTOML([]byte("quit = 'q'\nquit = 'x'")) }},
+		{"MissingFile", func(km *Keymap) error { return km.LoadFile(filepath.Join(t.TempDir(), "keys.json")) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.load(testKeymap(t)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package keymap
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+)
+
+// DefaultSequenceTimeout is how long a partly typed key sequence waits for
+// its next key
+const DefaultSequenceTimeout = time.Second
+
+// Binding binds a named action to key sequences
+type Binding struct {
+	Action string   // Name the action is reported and overridden by
+	Keys   []string // Key sequences, such as "ctrl+s" or "g g"
+	Help   string   // Short description; bindings without one are not shown in help
+	Scope  string   // Focus scope the binding is active in; empty is global
+
+	sequences [][]string
+}
+
+// Keymap is a registry of key bindings. A binding's scope names a focus
+// context, usually the ID of a StyledNode: it is active while that node or
+// one of its descendants has focus, and takes precedence over outer scopes
+// and the global scope for the same keys.
+//
+// Sequences are typed one key after another; if a key does not continue
+// the sequence typed so far, it is matched on its own. A sequence may not
+// equal or start with another sequence of the same scope, since only one
+// of them could ever complete. Across scopes the innermost scope that matches
+// the keys typed decides: a scoped "g" fires at once even where a global
+// "g g" is bound.
+type Keymap struct {
+	// SequenceTimeout is how long a partly typed sequence waits for its
+	// next key before it is dropped
+	SequenceTimeout time.Duration
+
+	bindings  []*Binding
+	pending   []string
+	pendingAt time.Time
+	now       func() time.Time
+}
+
+// New creates an empty keymap
+func New() *Keymap {
+	return &Keymap{
+		SequenceTimeout: DefaultSequenceTimeout,
+		now:             time.Now,
+	}
+}
+
+// Add registers bindings. An action may be bound once per scope, and its
+// sequences may not equal, start with or be the start of another sequence
+// of that scope.
+func (k *Keymap) Add(bindings ...Binding) error {
+	for _, b := range bindings {
+		if b.Action == "" {
+			return fmt.Errorf("keymap: binding without an action")
+		}
+		if k.find(b.Scope, b.Action) != nil {
+			return fmt.Errorf("keymap: action %q is already bound in scope %q", b.Action, b.Scope)
+		}
+		sequences, err := parseSequences(b.Keys)
+		if err != nil {
+			return err
+		}
+		if err := checkOverlaps(sequences, b.Scope); err != nil {
+			return err
+		}
+		for _, other := range k.bindings {
+			if other.Scope != b.Scope {
+				continue
+			}
+			for _, sequence := range sequences {
+				for _, taken := range other.sequences {
+					if overlaps(sequence, taken) {
+						return overlapError(sequence, taken, b.Scope)
+					}
+				}
+			}
+		}
+
+		binding := b
+		binding.Keys = append([]string(nil), b.Keys...)
+		binding.sequences = sequences
+		k.bindings = append(k.bindings, &binding)
+	}
+	return nil
+}
+
+// Override rebinds an action to new key sequences. No keys unbind it.
+// Sequences of other actions in the scope that equal, start with or are
+// the start of the new ones are taken from them, so the override always
+// wins.
+func (k *Keymap) Override(scope, action string, keys ...string) error {
+	b := k.find(scope, action)
+	if b == nil {
+		return fmt.Errorf("keymap: no action %q in scope %q", action, scope)
+	}
+	sequences, err := parseSequences(keys)
+	if err != nil {
+		return err
+	}
+	if err := checkOverlaps(sequences, scope); err != nil {
+		return err
+	}
+	for _, other := range k.bindings {
+		if other.Scope == scope && other != b {
+			other.drop(sequences)
+		}
+	}
+	b.Keys = append([]string(nil), keys...)
+	b.sequences = sequences
+	return nil
+}
+
+func (k *Keymap) find(scope, action string) *Binding {
+	for _, b := range k.bindings {
+		if b.Scope == scope && b.Action == action {
+			return b
+		}
+	}
+	return nil
+}
+
+// drop removes the sequences that overlap any of sequences
+func (b *Binding) drop(sequences [][]string) {
+	var keys []string
+	var kept [][]string
+	for i, own := range b.sequences {
+		if !slices.ContainsFunc(sequences, func(sequence []string) bool { return overlaps(own, sequence) }) {
+			keys = append(keys, b.Keys[i])
+			kept = append(kept, own)
+		}
+	}
+	b.Keys, b.sequences = keys, kept
+}
+
+// checkOverlaps reports two of sequences that overlap
+func checkOverlaps(sequences [][]string, scope string) error {
+	for i, sequence := range sequences {
+		for _, other := range sequences[i+1:] {
+			if overlaps(sequence, other) {
+				return overlapError(sequence, other, scope)
+			}
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether a and b are equal or one starts with the other,
+// so that they cannot both be bound in a scope
+func overlaps(a, b []string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return startsWith(b, a)
+}
+
+func overlapError(a, b []string, scope string) error {
+	return fmt.Errorf("keymap: %q and %q overlap in scope %q", strings.Join(a, " "), strings.Join(b, " "), scope)
+}
+
+func parseSequences(keys []string) ([][]string, error) {
+	sequences := make([][]string, len(keys))
+	for i, key := range keys {
+		sequence, err := ParseSequence(key)
+		if err != nil {
+			return nil, err
+		}
+		sequences[i] = sequence
+	}
+	return sequences, nil
+}
+
+// Bindings returns the bindings active in scopes, innermost first, followed
+// by the global bindings. Key sequences shadowed by an inner scope are left
+// out, as are bindings left with no keys.
+func (k *Keymap) Bindings(scopes ...string) []Binding {
+	var active []Binding
+	seen := map[string]bool{}
+	for _, scope := range append(append([]string(nil), scopes...), "") {
+		for _, b := range k.bindings {
+			if b.Scope != scope {
+				continue
+			}
+
+			binding := *b
+			binding.Keys, binding.sequences = nil, nil
+			for i, sequence := range b.sequences {
+				name := strings.Join(sequence, " ")
+				if !seen[name] {
+					seen[name] = true
+					binding.Keys = append(binding.Keys, name)
+					binding.sequences = append(binding.sequences, b.sequences[i])
+				}
+			}
+			if len(binding.Keys) > 0 {
+				active = append(active, binding)
+			}
+		}
+	}
+	return active
+}
+
+// Handle matches a key press against the bindings active in scopes,
+// innermost first, then the global ones. It returns the action the key
+// completes, or an empty action when the key starts or continues a
+// sequence. Handled is false for keys no binding uses, which should be
+// passed on. Key releases are ignored.
+func (k *Keymap) Handle(key input.KeyEvent, scopes ...string) (action string, handled bool) {
+	if key.Action == input.KeyRelease {
+		return "", false
+	}
+
+	now := k.now()
+	if len(k.pending) > 0 && now.Sub(k.pendingAt) > k.SequenceTimeout {
+		k.pending = nil
+	}
+
+	chord := chordOf(key)
+	typed := append(append([]string(nil), k.pending...), chord)
+	action, prefix := k.match(typed, scopes)
+	if action == "" && !prefix && len(k.pending) > 0 {
+		// The sequence broke off, so try the key on its own
+		typed = []string{chord}
+		action, prefix = k.match(typed, scopes)
+	}
+
+	switch {
+	case prefix:
+		k.pending, k.pendingAt = typed, now
+		return "", true
+	case action != "":
+		k.pending = nil
+		return action, true
+	}
+	k.pending = nil
+	return "", false
+}
+
+// match returns the action of the first active binding whose sequence is
+// typed, and whether any active sequence continues past it. Only the
+// innermost scope with a match counts.
+func (k *Keymap) match(typed []string, scopes []string) (action string, prefix bool) {
+	bindings := k.Bindings(scopes...)
+	for i, b := range bindings {
+		if i > 0 && b.Scope != bindings[i-1].Scope && (action != "" || prefix) {
+			break
+		}
+		for _, sequence := range b.sequences {
+			if len(sequence) < len(typed) || !startsWith(sequence, typed) {
+				continue
+			}
+			if len(sequence) > len(typed) {
+				prefix = true
+			} else if action == "" {
+				action = b.Action
+			}
+		}
+	}
+	return action, prefix
+}
+
+func startsWith(sequence, typed []string) bool {
+	for i, chord := range typed {
+		if sequence[i] != chord {
+			return false
+		}
+	}
+	return true
+}
+
+// Pending returns the keys typed so far of an unfinished sequence, such as
+// "g", or an empty string
+func (k *Keymap) Pending() string {
+	return strings.Join(k.pending, " ")
+}
+
+// Reset drops an unfinished sequence
+func (k *Keymap) Reset() {
+	k.pending = nil
+}
+
+// FocusScopes returns the IDs of the focused node and its ancestors,
+// innermost first, for use as the scopes of Handle and Bindings
+func FocusScopes(focus *renderer.FocusManager) []string {
+	path := focus.FocusPath()
+	var scopes []string
+	for i := len(path) - 1; i >= 0; i-- {
+		if id := path[i].ID; id != "" {
+			scopes = append(scopes, id)
+		}
+	}
+	return scopes
+}
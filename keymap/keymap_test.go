@@ -0,0 +1,206 @@
+package keymap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/layout"
+)
+
+func key(r rune) input.KeyEvent {
+	return input.KeyEvent{Key: input.KeyRune, Rune: r}
+}
+
+func testKeymap(t *testing.T) *Keymap {
+	t.Helper()
+	km := New()
+	err := km.Add(
+		Binding{Action: "quit", Keys: []string{"q", "ctrl+c"}, Help: "quit"},
+		Binding{Action: "top", Keys: []string{"g g"}, Help: "top"},
+		Binding{Action: "save", Keys: []string{"ctrl+x ctrl+s"}},
+		Binding{Action: "list.delete", Keys: []string{"d"}, Help: "delete", Scope: "list"},
+		Binding{Action: "list.quit", Keys: []string{"q"}, Help: "close list", Scope: "list"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return km
+}
+
+func TestKeymapHandle(t *testing.T) {
+	km := testKeymap(t)
+
+	tests := []struct {
+		name    string
+		key     input.KeyEvent
+		scopes  []string
+		action  string
+		handled bool
+	}{
+		{"Single", key('q'), nil, "quit", true},
+		{"Unbound", key('x'), nil, "", false},
+		{"SequenceStart", key('g'), nil, "", true},
+		{"SequenceEnd", key('g'), nil, "top", true},
+		{"ChordSequenceStart", input.KeyEvent{Key: input.KeyRune, Rune: 'x', Mod: input.ModCtrl}, nil, "", true},
+		{"BrokenSequenceRetriesKey", key('q'), nil, "quit", true},
+		{"ScopedOutOfScope", key('d'), nil, "", false},
+		{"ScopedInScope", key('d'), []string{"list"}, "list.delete", true},
+		{"ScopeShadowsGlobal", key('q'), []string{"list"}, "list.quit", true},
+		{"GlobalInScope", input.KeyEvent{Key: input.KeyRune, Rune: 'c', Mod: input.ModCtrl}, []string{"list"}, "quit", true},
+		{"Release", input.KeyEvent{Key: input.KeyRune, Rune: 'q', Action: input.KeyRelease}, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		action, handled := km.Handle(tt.key, tt.scopes...)
+		if action != tt.action || handled != tt.handled {
+			t.Errorf("%s: expected (%q, %v), got (%q, %v)", tt.name, tt.action, tt.handled, action, handled)
+		}
+	}
+}
+
+func TestKeymapSequenceTimeout(t *testing.T) {
+	km := testKeymap(t)
+	now := time.Unix(0, 0)
+	km.now = func() time.Time { return now }
+
+	km.Handle(key('g'))
+	if km.Pending() != "g" {
+		t.Errorf("Expected g to be pending, got %q", km.Pending())
+	}
+	now = now.Add(2 * DefaultSequenceTimeout)
+	if _, handled := km.Handle(key('x')); handled || km.Pending() != "" {
+		t.Error("Expected the sequence to time out")
+	}
+
+	km.Handle(key('g'))
+	now = now.Add(2 * DefaultSequenceTimeout)
+	if action, _ := km.Handle(key('g')); action != "" || km.Pending() != "g" {
+		t.Errorf("Expected a late second g to start over, got %q", action)
+	}
+}
+
+func TestKeymapRejectsOverlaps(t *testing.T) {
+	km := testKeymap(t)
+
+	if err := km.Add(Binding{Action: "go", Keys: []string{"g"}}); err == nil {
+		t.Error("Expected g to be rejected beside g g")
+	}
+	if err := km.Add(Binding{Action: "exit", Keys: []string{"ctrl+c"}}); err == nil {
+		t.Error("Expected a key another action holds to be rejected")
+	}
+	if err := km.Add(Binding{Action: "save.all", Keys: []string{"ctrl+x ctrl+s a"}}); err == nil {
+		t.Error("Expected a sequence continuing ctrl+x ctrl+s to be rejected")
+	}
+	if err := km.Add(Binding{Action: "both", Keys: []string{"z", "z z"}}); err == nil {
+		t.Error("Expected a binding overlapping itself to be rejected")
+	}
+	if err := km.Override("", "top", "g g", "g"); err == nil {
+		t.Error("Expected an override overlapping itself to be rejected")
+	}
+	if err := km.Override("", "top", "g g g"); err != nil {
+		t.Errorf("Expected an action to overlap its own old keys, got %v", err)
+	}
+}
+
+func TestKeymapOverrideTakesKeys(t *testing.T) {
+	km := testKeymap(t)
+
+	if err := km.Override("", "save", "ctrl+c"); err != nil {
+		t.Fatal(err)
+	}
+	if action, _ := km.Handle(input.KeyEvent{Key: input.KeyRune, Rune: 'c', Mod: input.ModCtrl}); action != "save" {
+		t.Errorf("Expected ctrl+c to save, got %q", action)
+	}
+	if err := km.Override("", "quit", "g"); err != nil {
+		t.Fatal(err)
+	}
+	if action, _ := km.Handle(key('g')); action != "quit" || km.Pending() != "" {
+		t.Errorf("Expected g to quit at once, got %q pending %q", action, km.Pending())
+	}
+
+	keys := map[string][]string{}
+	for _, b := range km.Bindings() {
+		keys[b.Action] = b.Keys
+	}
+	if got := keys["quit"]; len(got) != 1 || got[0] != "g" {
+		t.Errorf("Expected quit on g alone, got %v", got)
+	}
+	if got := keys["save"]; len(got) != 1 || got[0] != "ctrl+c" {
+		t.Errorf("Expected save on ctrl+c, got %v", got)
+	}
+	if got, ok := keys["top"]; ok {
+		t.Errorf("Expected top to lose g g, got %v", got)
+	}
+}
+
+func TestKeymapScopedPrefix(t *testing.T) {
+	km := testKeymap(t)
+	if err := km.Add(Binding{Action: "list.go", Keys: []string{"g"}, Scope: "list"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if action, _ := km.Handle(key('g'), "list"); action != "list.go" || km.Pending() != "" {
+		t.Errorf("Expected the scoped g to fire at once, got %q pending %q", action, km.Pending())
+	}
+	km.Handle(key('g'))
+	if action, _ := km.Handle(key('g')); action != "top" {
+		t.Errorf("Expected g g out of scope, got %q", action)
+	}
+}
+
+func TestKeymapBindings(t *testing.T) {
+	km := testKeymap(t)
+	if err := km.Add(Binding{Action: "quit"}); err == nil {
+		t.Error("Expected an error binding an action twice in a scope")
+	}
+	if err := km.Add(Binding{Action: "bad", Keys: []string{"ctrl+nosuchkey"}}); err == nil {
+		t.Error("Expected an error for an unknown key")
+	}
+
+	bindings := km.Bindings("list")
+	var actions []string
+	for _, b := range bindings {
+		actions = append(actions, b.Action)
+	}
+	expected := []string{"list.delete", "list.quit", "quit", "top", "save"}
+	if len(actions) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, actions)
+	}
+	for i := range expected {
+		if actions[i] != expected[i] {
+			t.Fatalf("Expected %v, got %v", expected, actions)
+		}
+	}
+	if keys := bindings[2].Keys; len(keys) != 1 || keys[0] != "ctrl+c" {
+		t.Errorf("Expected q to be shadowed by the list scope, got %v", keys)
+	}
+
+	if err := km.Override("", "quit"); err != nil {
+		t.Fatal(err)
+	}
+	if _, handled := km.Handle(key('q')); handled {
+		t.Error("Expected an override with no keys to unbind the action")
+	}
+}
+
+func TestFocusScopes(t *testing.T) {
+	root := renderer.NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 10, Height: 4}}, nil)
+	root.ID = "app"
+	panel := renderer.NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 10, Height: 3}}, nil)
+	item := renderer.NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 10, Height: 1}}, nil)
+	item.ID = "list"
+	item.Focusable = true
+	panel.AddChild(item)
+	root.AddChild(panel)
+
+	focus := renderer.NewFocusManager(root)
+	if scopes := FocusScopes(focus); len(scopes) != 0 {
+		t.Errorf("Expected no scopes without focus, got %v", scopes)
+	}
+	focus.Focus(item)
+	if scopes := FocusScopes(focus); len(scopes) != 2 || scopes[0] != "list" || scopes[1] != "app" {
+		t.Errorf("Expected [list app], got %v", scopes)
+	}
+}
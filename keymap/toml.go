@@ -0,0 +1,185 @@
+package keymap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML parses the subset of TOML a keymap config uses: tables of
+// keys whose values are strings or arrays of strings, which may span lines.
+// Tables decode to map[string]any and arrays to []string.
+func parseTOML(data string) (map[string]any, error) {
+	root := map[string]any{}
+	table := root
+
+	lines := strings.Split(data, "\n")
+	for n := 0; n < len(lines); n++ {
+		lineNo := n + 1
+		line := strings.TrimSpace(stripComment(lines[n]))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("keymap: line %d: invalid table header", lineNo)
+			}
+			name, err := parseKey(strings.TrimSpace(line[1 : len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("keymap: line %d: %w", lineNo, err)
+			}
+			if _, exists := root[name]; exists {
+				return nil, fmt.Errorf("keymap: line %d: duplicate table %q", lineNo, name)
+			}
+			table = map[string]any{}
+			root[name] = table
+			continue
+		}
+
+		eq := strings.Index(line, "=")
+		if eq < 0 {
+			return nil, fmt.Errorf("keymap: line %d: expected key = value", lineNo)
+		}
+		key, err := parseKey(strings.TrimSpace(line[:eq]))
+		if err != nil {
+			return nil, fmt.Errorf("keymap: line %d: %w", lineNo, err)
+		}
+		value := strings.TrimSpace(line[eq+1:])
+
+		// Arrays may continue on the following lines until they close
+		for strings.HasPrefix(value, "[") && !arrayClosed(value) && n+1 < len(lines) {
+			n++
+			value += " " + strings.TrimSpace(stripComment(lines[n]))
+		}
+
+		parsed, err := parseValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("keymap: line %d: %w", lineNo, err)
+		}
+		if _, exists := table[key]; exists {
+			return nil, fmt.Errorf("keymap: line %d: duplicate key %q", lineNo, key)
+		}
+		table[key] = parsed
+	}
+	return root, nil
+}
+
+// stripComment removes a # comment that is not inside a string
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// arrayClosed reports whether an array value has its closing bracket
+func arrayClosed(value string) bool {
+	return strings.HasSuffix(strings.TrimSpace(stripComment(value)), "]")
+}
+
+// parseKey parses a bare or quoted key
+func parseKey(key string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("empty key")
+	}
+	if key[0] == '"' || key[0] == '\'' {
+		s, rest, err := parseString(key)
+		if err != nil {
+			return "", err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return "", fmt.Errorf("unexpected %q after key", rest)
+		}
+		return s, nil
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return "", fmt.Errorf("invalid bare key %q", key)
+		}
+	}
+	return key, nil
+}
+
+// parseValue parses a string or an array of strings
+func parseValue(value string) (any, error) {
+	if !strings.HasPrefix(value, "[") {
+		s, rest, err := parseString(value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, fmt.Errorf("unexpected %q after value", rest)
+		}
+		return s, nil
+	}
+
+	items := []string{}
+	rest := strings.TrimSpace(value[1:])
+	for {
+		if strings.HasPrefix(rest, "]") {
+			if strings.TrimSpace(rest[1:]) != "" {
+				return nil, fmt.Errorf("unexpected %q after array", rest[1:])
+			}
+			return items, nil
+		}
+		s, after, err := parseString(rest)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, s)
+
+		rest = strings.TrimSpace(after)
+		if strings.HasPrefix(rest, ",") {
+			rest = strings.TrimSpace(rest[1:])
+		} else if !strings.HasPrefix(rest, "]") {
+			return nil, fmt.Errorf("expected , or ] in array")
+		}
+	}
+}
+
+// parseString parses a basic ("...") or literal ('...') string at the start
+// of s and returns the rest
+func parseString(s string) (value, rest string, err error) {
+	if s == "" {
+		return "", "", fmt.Errorf("expected a string")
+	}
+
+	switch s[0] {
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : end+1], s[end+2:], nil
+
+	case '"':
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				value, err := strconv.Unquote(s[:i+1])
+				if err != nil {
+					return "", "", fmt.Errorf("invalid string %s", s[:i+1])
+				}
+				return value, s[i+1:], nil
+			}
+		}
+		return "", "", fmt.Errorf("unterminated string")
+	}
+	return "", "", fmt.Errorf("expected a string, got %q", s)
+}
@@ -116,6 +116,24 @@ func (r *ANSIRenderer) ExitAltScreen() string {
 	return "\x1b[?1049l"
 }
 
+// Synchronized update (DEC mode 2026) sequences
+const (
+	syncBegin = "\x1b[?2026h"
+	syncEnd   = "\x1b[?2026l"
+)
+
+// BeginSynchronizedUpdate starts a synchronized update (DEC mode 2026).
+// The terminal holds drawing until EndSynchronizedUpdate, so a frame is
+// shown all at once instead of tearing. Terminals without support ignore it.
+func (r *ANSIRenderer) BeginSynchronizedUpdate() string {
+	return syncBegin
+}
+
+// EndSynchronizedUpdate ends a synchronized update and shows the frame
+func (r *ANSIRenderer) EndSynchronizedUpdate() string {
+	return syncEnd
+}
+
 // renderColor renders a color based on terminal capabilities
 func (r *ANSIRenderer) renderColor(c *color.Color, foreground bool) string {
 	if c == nil {
@@ -239,3 +239,13 @@ func TestColorModeConstants(t *testing.T) {
 		seen[mode] = true
 	}
 }
+
+func TestSynchronizedUpdate(t *testing.T) {
+	r := NewANSIRendererWithMode(ColorModeNone)
+	if got := r.BeginSynchronizedUpdate(); got != "\x1b[?2026h" {
+		t.Errorf("Expected begin sequence, got %q", got)
+	}
+	if got := r.EndSynchronizedUpdate(); got != "\x1b[?2026l" {
+		t.Errorf("Expected end sequence, got %q", got)
+	}
+}
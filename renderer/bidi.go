@@ -0,0 +1,126 @@
+package renderer
+
+import "github.com/SCKelemen/unicode/uax9"
+
+// visualGraphemes splits a line into grapheme clusters in visual order,
+// applying the Unicode Bidirectional Algorithm with the given base
+// direction. Brackets inside right-to-left runs are mirrored. It also
+// reports whether the line's resolved base direction is right-to-left.
+func visualGraphemes(line string, dir Direction) ([]string, bool) {
+	graphemes := textMeasurer.Graphemes(line)
+
+	rtl := paragraphDirection(line, dir) == DirectionRTL
+	if !rtl && !hasRTL(line) {
+		return graphemes, false
+	}
+
+	// Classify each cluster by its base character so combining marks and
+	// emoji sequences move as a unit
+	classes := make([]uax9.BidiClass, len(graphemes))
+	for i, g := range graphemes {
+		for _, r := range g {
+			classes[i] = uax9.GetBidiClass(r)
+			break
+		}
+	}
+
+	paraLevel := 0
+	if rtl {
+		paraLevel = 1
+	}
+	levels := uax9.ComputeLevels(classes, paraLevel)
+
+	return reorderGraphemes(graphemes, levels), rtl
+}
+
+// paragraphDirection resolves DirectionAuto from the first strong
+// character of a paragraph, defaulting to left-to-right
+func paragraphDirection(para string, dir Direction) Direction {
+	if dir != DirectionAuto {
+		return dir
+	}
+	if uax9.GetParagraphDirection(para) == uax9.DirectionRTL {
+		return DirectionRTL
+	}
+	return DirectionLTR
+}
+
+// hasRTL reports whether a line contains characters that can change the
+// order of a left-to-right line
+func hasRTL(line string) bool {
+	for _, r := range line {
+		switch uax9.GetBidiClass(r) {
+		case uax9.ClassR, uax9.ClassAL, uax9.ClassAN,
+			uax9.ClassRLE, uax9.ClassRLO, uax9.ClassRLI, uax9.ClassFSI:
+			return true
+		}
+	}
+	return false
+}
+
+// reorderGraphemes applies rules L2 and L4: from the highest level down to
+// the lowest odd level, every run at that level or above is reversed, and
+// mirrored characters at odd levels are replaced by their mirror glyphs.
+// Clusters with level -1 are formatting characters removed from display.
+func reorderGraphemes(graphemes []string, levels []int) []string {
+	maxLevel, minOdd := 0, -1
+	for _, level := range levels {
+		if level > maxLevel {
+			maxLevel = level
+		}
+		if level%2 == 1 && (minOdd < 0 || level < minOdd) {
+			minOdd = level
+		}
+	}
+
+	order := make([]int, 0, len(graphemes))
+	for i, level := range levels {
+		if level >= 0 {
+			order = append(order, i)
+		}
+	}
+
+	if minOdd > 0 {
+		for level := maxLevel; level >= minOdd; level-- {
+			for i := 0; i < len(order); {
+				if levels[order[i]] < level {
+					i++
+					continue
+				}
+				start := i
+				for i < len(order) && levels[order[i]] >= level {
+					i++
+				}
+				for a, b := start, i-1; a < b; a, b = a+1, b-1 {
+					order[a], order[b] = order[b], order[a]
+				}
+			}
+		}
+	}
+
+	visual := make([]string, len(order))
+	for i, idx := range order {
+		g := graphemes[idx]
+		if levels[idx]%2 == 1 {
+			g = textMeasurer.MirrorBrackets(g)
+		}
+		visual[i] = g
+	}
+	return visual
+}
+
+// resolveAlign maps start- and end-relative alignment to physical alignment.
+// TextAlignLeft and TextAlignRight mean start and end, so they swap in
+// right-to-left lines.
+func resolveAlign(align TextAlign, rtl bool) TextAlign {
+	if !rtl {
+		return align
+	}
+	switch align {
+	case TextAlignLeft:
+		return TextAlignRight
+	case TextAlignRight:
+		return TextAlignLeft
+	}
+	return align
+}
@@ -0,0 +1,121 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/layout"
+)
+
+func TestVisualGraphemes(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		dir      Direction
+		expected string
+		rtl      bool
+	}{
+		{"PlainLTR", "hello", DirectionLTR, "hello", false},
+		{"HebrewInLTR", "abc אבג def", DirectionLTR, "abc גבא def", false},
+		{"RTLBase", "אבג abc", DirectionRTL, "abc גבא", true},
+		{"NumbersInRTL", "אב 123", DirectionRTL, "123 בא", true},
+		{"MirroredBrackets", "(אב)", DirectionRTL, "(בא)", true},
+		{"AutoDetectsRTL", "אבג abc", DirectionAuto, "abc גבא", true},
+		{"AutoDetectsLTR", "abc אבג", DirectionAuto, "abc גבא", false},
+		{"CombiningMarksStay", "אָב", DirectionLTR, "באָ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			graphemes, rtl := visualGraphemes(tt.line, tt.dir)
+			if got := strings.Join(graphemes, ""); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+			if rtl != tt.rtl {
+				t.Errorf("Expected rtl=%v, got %v", tt.rtl, rtl)
+			}
+		})
+	}
+}
+
+func TestResolveAlign(t *testing.T) {
+	if got := resolveAlign(TextAlignLeft, true); got != TextAlignRight {
+		t.Errorf("Expected start to resolve to right in RTL, got %v", got)
+	}
+	if got := resolveAlign(TextAlignRight, true); got != TextAlignLeft {
+		t.Errorf("Expected end to resolve to left in RTL, got %v", got)
+	}
+	if got := resolveAlign(TextAlignCenter, true); got != TextAlignCenter {
+		t.Errorf("Expected center to stay center, got %v", got)
+	}
+	if got := resolveAlign(TextAlignRight, false); got != TextAlignRight {
+		t.Errorf("Expected LTR alignment unchanged, got %v", got)
+	}
+}
+
+func TestRenderRTLText(t *testing.T) {
+	tests := []struct {
+		name     string
+		align    TextAlign
+		expected string
+	}{
+		{"StartAlignsRight", TextAlignLeft, "   םולש"},
+		{"EndAlignsLeft", TextAlignRight, "םולש   "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScreen(7, 1)
+			node := &layout.Node{
+				Rect: layout.Rect{X: 0, Y: 0, Width: 7, Height: 1},
+			}
+			style := NewStyle().WithDirection(DirectionRTL)
+			style.TextAlign = tt.align
+
+			styled := NewStyledNode(node, style)
+			styled.Content = "שלום"
+			s.Render(styled)
+
+			if got := rowString(s, 0); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenderRTLClipsLogicalEnd(t *testing.T) {
+	s := NewScreen(3, 1)
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 3, Height: 1},
+	}
+	styled := NewStyledNode(node, NewStyle().WithDirection(DirectionRTL))
+	styled.Content = "אבגד"
+	s.Render(styled)
+
+	// The start of the line sits at the right edge; the end is cut off
+	expected := "גבא"
+	if got := rowString(s, 0); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestRenderAutoDirectionPerParagraph(t *testing.T) {
+	s := NewScreen(5, 3)
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 5, Height: 3},
+	}
+	style := NewStyle().WithDirection(DirectionAuto)
+	style.TextWrap = TextWrapNormal
+	styled := NewStyledNode(node, style)
+	styled.Content = "שלום abc\nabc"
+	s.Render(styled)
+
+	// The wrapped line keeps the right-to-left direction of its paragraph;
+	// the next paragraph resolves its own
+	expected := []string{" םולש", "  abc", "abc  "}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package renderer
+
+import "github.com/SCKelemen/color"
+
+// borderSide identifies one side of a border
+type borderSide int
+
+const (
+	borderTop borderSide = iota
+	borderRight
+	borderBottom
+	borderLeft
+)
+
+// renderBorder renders a border around the specified rectangle. Collapsed
+// borders are drawn into the junction layer so lines from neighbouring
+// borders merge with the correct junction glyphs.
+func (s *Screen) renderBorder(x, y, w, h int, style *Style, collapse bool) {
+	if style.Border == nil {
+		return
+	}
+
+	border := style.Border
+	chars := border.Chars
+	if !s.unicode {
+		chars = ASCIIBorder
+	}
+	paint := s.newBorderPainter(x, y, w, h, style)
+
+	// Only line-drawing borders can merge; block borders simply overlap
+	collapse = collapse && chars.joinable()
+	weight := borderWeight(chars)
+	rounded := chars.TopLeft == RoundedBorder.TopLeft
+	ascii := chars == ASCIIBorder
+	put := func(col, row int, ch rune, arms lineArms, cellStyle *Style) {
+		if col < 0 || col >= s.Width {
+			return
+		}
+		if !collapse {
+			s.SetCell(col, row, string(ch), cellStyle)
+			return
+		}
+		for i, arm := range arms {
+			if arm != lineNone {
+				arms[i] = weight
+			}
+		}
+		line := ch
+		if !arms.straight() {
+			line = 0
+		}
+		s.setJunction(col, row, arms, line, rounded, ascii, cellStyle)
+	}
+
+	// Top border
+	if border.Top && y >= 0 && y < s.Height {
+		if border.Left {
+			put(x, y, chars.TopLeft, lineArms{0, 1, 1, 0}, paint(borderTop, x, y))
+		}
+		for i := 1; i < w-1; i++ {
+			put(x+i, y, chars.Horizontal, lineArms{0, 1, 0, 1}, paint(borderTop, x+i, y))
+		}
+		if border.Right {
+			put(x+w-1, y, chars.TopRight, lineArms{0, 0, 1, 1}, paint(borderTop, x+w-1, y))
+		}
+	}
+
+	// Bottom border
+	if border.Bottom && y+h-1 >= 0 && y+h-1 < s.Height {
+		if border.Left {
+			put(x, y+h-1, chars.BottomLeft, lineArms{1, 1, 0, 0}, paint(borderBottom, x, y+h-1))
+		}
+		for i := 1; i < w-1; i++ {
+			put(x+i, y+h-1, chars.bottom(), lineArms{0, 1, 0, 1}, paint(borderBottom, x+i, y+h-1))
+		}
+		if border.Right {
+			put(x+w-1, y+h-1, chars.BottomRight, lineArms{1, 0, 0, 1}, paint(borderBottom, x+w-1, y+h-1))
+		}
+	}
+
+	// Left and right borders
+	for i := 1; i < h-1; i++ {
+		if y+i < 0 || y+i >= s.Height {
+			continue
+		}
+		if border.Left {
+			put(x, y+i, chars.Vertical, lineArms{1, 0, 1, 0}, paint(borderLeft, x, y+i))
+		}
+		if border.Right {
+			put(x+w-1, y+i, chars.right(), lineArms{1, 0, 1, 0}, paint(borderRight, x+w-1, y+i))
+		}
+	}
+
+	// Labels embedded in the horizontal borders
+	if border.Top && border.Title != "" {
+		s.renderBorderLabel(x, y, w, border.Title, border.TitleAlign, func(col int) *Style {
+			return paint(borderTop, col, y)
+		})
+	}
+	if border.Bottom && border.Footer != "" {
+		s.renderBorderLabel(x, y+h-1, w, border.Footer, border.FooterAlign, func(col int) *Style {
+			return paint(borderBottom, col, y+h-1)
+		})
+	}
+}
+
+// newBorderPainter returns a function that yields the style for the border
+// cell at (col, row) on the given side. Side colors fall back to the border
+// color, then the foreground color. Gradients follow the same rules as text
+// gradients and collapse to a single color in 16-color and no-color modes.
+func (s *Screen) newBorderPainter(x, y, w, h int, style *Style) func(side borderSide, col, row int) *Style {
+	border := style.Border
+
+	base := style.BorderColor
+	if base == nil {
+		base = style.Foreground
+	}
+
+	if gradient := border.Gradient; gradient != nil {
+		if s.renderer.ColorMode == ColorModeNone || s.renderer.ColorMode == ColorMode16 {
+			c := gradient.At(0.5)
+			solid := &Style{Foreground: &c}
+			return func(borderSide, int, int) *Style { return solid }
+		}
+		return func(_ borderSide, col, row int) *Style {
+			c := gradient.At(gradient.position(col-x, row-y, w, h))
+			return &Style{Foreground: &c}
+		}
+	}
+
+	sides := [4]*Style{}
+	for side, c := range [4]*color.Color{border.TopColor, border.RightColor, border.BottomColor, border.LeftColor} {
+		if c != nil {
+			sides[side] = &Style{Foreground: c}
+		}
+	}
+
+	// Use border color if specified, otherwise use foreground color
+	borderStyle := &Style{
+		Foreground: base,
+	}
+
+	return func(side borderSide, _, _ int) *Style {
+		if sides[side] != nil {
+			return sides[side]
+		}
+		return borderStyle
+	}
+}
+
+// renderBorderLabel draws a label inside a horizontal border row, padded by
+// one space on each side and kept clear of the corners. Labels that do not
+// fit are elided with an ellipsis.
+func (s *Screen) renderBorderLabel(x, row, w int, label string, align TextAlign, paint func(col int) *Style) {
+	if row < 0 || row >= s.Height {
+		return
+	}
+
+	// Corners, one horizontal run on each side, and the padding spaces
+	maxWidth := w - 6
+	if maxWidth < 1 {
+		return
+	}
+
+	if textMeasurer.Width(label) > float64(maxWidth) {
+		label = textMeasurer.ElideEndWith(label, float64(maxWidth), s.ellipsis())
+	}
+	segmentWidth := int(textMeasurer.Width(label)) + 2
+
+	col := x + 2
+	switch align {
+	case TextAlignCenter:
+		col = x + (w-segmentWidth)/2
+	case TextAlignRight:
+		col = x + w - 2 - segmentWidth
+	}
+
+	s.SetCell(col, row, " ", paint(col))
+	col++
+
+	for _, grapheme := range textMeasurer.Graphemes(label) {
+		graphemeWidth := int(textMeasurer.Width(grapheme))
+		cellStyle := paint(col)
+		s.setGlyph(col, row, grapheme, graphemeWidth, cellStyle)
+		col += graphemeWidth
+	}
+
+	s.SetCell(col, row, " ", paint(col))
+}
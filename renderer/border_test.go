@@ -0,0 +1,225 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+// rowString returns the cell contents of a screen row
+func rowString(s *Screen, row int) string {
+	var out string
+	for x := 0; x < s.Width; x++ {
+		out += s.Cells[row][x].Content
+	}
+	return out
+}
+
+func renderBordered(width, height int, style *Style) *Screen {
+	s := NewScreen(width, height)
+	s.SetUnicode(true)
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: float64(width), Height: float64(height)},
+	}
+	s.Render(NewStyledNode(node, style))
+	return s
+}
+
+func TestBorderTitleAlignment(t *testing.T) {
+	tests := []struct {
+		name     string
+		align    TextAlign
+		expected string
+	}{
+		{"Left", TextAlignLeft, "┌─ Logs ─────────┐"},
+		{"Center", TextAlignCenter, "┌───── Logs ─────┐"},
+		{"Right", TextAlignRight, "┌───────── Logs ─┐"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style := NewStyle().WithBorder(NormalBorder).WithBorderTitle("Logs", tt.align)
+			s := renderBordered(18, 3, style)
+
+			if got := rowString(s, 0); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBorderTitleElided(t *testing.T) {
+	style := NewStyle().WithBorder(NormalBorder).WithBorderTitle("A very long title", TextAlignLeft)
+	s := renderBordered(12, 3, style)
+
+	expected := "┌─ A ver… ─┐"
+	if got := rowString(s, 0); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestBorderFooter(t *testing.T) {
+	style := NewStyle().WithBorder(NormalBorder).WithBorderFooter("ok", TextAlignRight)
+	s := renderBordered(10, 3, style)
+
+	expected := "└─── ok ─┘"
+	if got := rowString(s, 2); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestBorderPerSideColor(t *testing.T) {
+	red, _ := color.ParseColor("#FF0000")
+	gray, _ := color.ParseColor("#808080")
+	style := NewStyle().WithBorder(NormalBorder).WithBorderColor(&gray)
+	style.Border.LeftColor = &red
+	s := renderBordered(6, 4, style)
+
+	if got := s.Cells[1][0].Style.Foreground; got != &red {
+		t.Error("Expected left border to use the left color")
+	}
+	if got := s.Cells[1][5].Style.Foreground; got != &gray {
+		t.Error("Expected right border to fall back to the border color")
+	}
+}
+
+func TestBorderGradient(t *testing.T) {
+	red, _ := color.ParseColor("#FF0000")
+	blue, _ := color.ParseColor("#0000FF")
+	style := NewStyle().WithBorder(NormalBorder).WithBorderGradient(NewGradient(red, blue))
+
+	s := NewScreen(6, 3)
+	s.SetColorMode(ColorModeTrueColor)
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 6, Height: 3},
+	}
+	s.Render(NewStyledNode(node, style))
+
+	if got := color.RGBToHex(*s.Cells[0][0].Style.Foreground); got != "#ff0000" {
+		t.Errorf("Expected left corner #ff0000, got %s", got)
+	}
+	if got := color.RGBToHex(*s.Cells[0][5].Style.Foreground); got != "#0000ff" {
+		t.Errorf("Expected right corner #0000ff, got %s", got)
+	}
+}
+
+func TestJunctionGlyphs(t *testing.T) {
+	tests := []struct {
+		name     string
+		arms     lineArms
+		expected rune
+	}{
+		{"LightCross", lineArms{1, 1, 1, 1}, '┼'},
+		{"LightTee", lineArms{0, 1, 1, 1}, '┬'},
+		{"MixedHeavy", lineArms{1, 2, 1, 1}, '┾'},
+		{"DoubleWithLight", lineArms{1, 3, 1, 0}, '╞'},
+		{"HeavyWithDouble", lineArms{2, 3, 2, 3}, '╪'},
+		{"MixedDoubleAxis", lineArms{3, 1, 1, 1}, '╫'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.arms.glyph(); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBorderCollapseSiblings(t *testing.T) {
+	s := NewScreen(11, 3)
+	s.SetUnicode(true)
+
+	root := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 11, Height: 3},
+	}
+	left := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 5, Height: 3},
+	}
+	right := &layout.Node{
+		Rect: layout.Rect{X: 5, Y: 0, Width: 6, Height: 3},
+	}
+
+	rootStyled := NewStyledNode(root, NewStyle().WithBorderCollapse(true))
+	rootStyled.AddChild(NewStyledNode(left, NewStyle().WithBorder(NormalBorder)))
+	rootStyled.AddChild(NewStyledNode(right, NewStyle().WithBorder(NormalBorder)))
+
+	s.Render(rootStyled)
+
+	expected := []string{
+		"┌───┬─────┐",
+		"│   │     │",
+		"└───┴─────┘",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestBorderCollapseWithParent(t *testing.T) {
+	s := NewScreen(7, 4)
+	s.SetUnicode(true)
+
+	root := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 7, Height: 4},
+	}
+	cell := &layout.Node{
+		Rect: layout.Rect{X: 1, Y: 1, Width: 3, Height: 2},
+	}
+
+	rootStyled := NewStyledNode(root, NewStyle().WithBorder(NormalBorder).WithBorderCollapse(true))
+	rootStyled.AddChild(NewStyledNode(cell, NewStyle().WithBorder(NormalBorder)))
+
+	s.Render(rootStyled)
+
+	expected := []string{
+		"┌──┬──┐",
+		"│  │  │",
+		"│  │  │",
+		"└──┴──┘",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestBorderASCIIFallback(t *testing.T) {
+	s := NewScreen(6, 3)
+	s.SetUnicode(false)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 6, Height: 3},
+	}
+	s.Render(NewStyledNode(node, NewStyle().WithBorder(RoundedBorder)))
+
+	expected := []string{
+		"+----+",
+		"|    |",
+		"+----+",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestHalfBlockBorderSides(t *testing.T) {
+	s := renderBordered(4, 3, NewStyle().WithBorder(OuterHalfBlockBorder))
+
+	expected := []string{
+		"▛▀▀▜",
+		"▌  ▐",
+		"▙▄▄▟",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
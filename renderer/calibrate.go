@@ -0,0 +1,302 @@
+package renderer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// WidthTable records the number of cells a terminal actually uses for
+// graphemes whose width differs between terminals, such as emoji ZWJ
+// sequences, VS16 presentation and some CJK symbols. Once installed with
+// SetWidthOverrides, a table must not be modified.
+type WidthTable struct {
+	Terminal string         `json:"terminal"`
+	Version  string         `json:"version"`
+	Widths   map[string]int `json:"widths"`
+}
+
+// NewWidthTable creates an empty width table for a terminal
+func NewWidthTable(terminal, version string) *WidthTable {
+	return &WidthTable{
+		Terminal: terminal,
+		Version:  version,
+		Widths:   make(map[string]int),
+	}
+}
+
+// Set records the measured width of a grapheme
+func (t *WidthTable) Set(grapheme string, width int) {
+	if t.Widths == nil {
+		t.Widths = make(map[string]int)
+	}
+	t.Widths[grapheme] = width
+}
+
+// Lookup returns the measured width of a grapheme, if it was measured
+func (t *WidthTable) Lookup(grapheme string) (int, bool) {
+	if t == nil {
+		return 0, false
+	}
+	width, ok := t.Widths[grapheme]
+	return width, ok
+}
+
+// widthOverrides is the table consulted by textMeasurer
+var widthOverrides atomic.Pointer[WidthTable]
+
+// SetWidthOverrides installs a width table consulted by all text
+// measurement in the renderer. Pass nil to return to the built-in widths.
+func SetWidthOverrides(table *WidthTable) {
+	widthOverrides.Store(table)
+}
+
+// WidthOverrides returns the installed width table, or nil
+func WidthOverrides() *WidthTable {
+	return widthOverrides.Load()
+}
+
+// DefaultWidthProbes are graphemes whose width commonly varies between
+// terminals
+var DefaultWidthProbes = []string{
+	"👨‍👩‍👧", // ZWJ family
+	"🏳️‍🌈",  // ZWJ flag with VS16
+	"🧑‍💻",   // ZWJ profession
+	"👍🏽",    // Skin tone modifier
+	"🇺🇸",    // Regional indicator pair
+	"❤️",    // Text default with VS16
+	"☺️",    // Text default with VS16
+	"✔️",    // Text default with VS16
+	"1️⃣",   // Keycap sequence
+	"☀",     // Text default without selector
+	"⚡",     // Emoji presentation default
+	"※",     // Ambiguous CJK symbol
+	"①",     // Ambiguous circled digit
+	"〜",     // Wave dash
+	"…",     // Ambiguous ellipsis
+	"→",     // Ambiguous arrow
+	"한",     // Precomposed Hangul
+	"가",    // Conjoining jamo
+}
+
+// CalibrationOptions configures width calibration
+type CalibrationOptions struct {
+	Probes   []string      // Graphemes to measure (default DefaultWidthProbes)
+	Timeout  time.Duration // Time to wait for all replies (default 1s)
+	Terminal string        // Terminal name for the table (default detected)
+	Version  string        // Terminal version for the table (default detected)
+}
+
+// ErrCalibrationTimeout is returned when the terminal does not answer
+// cursor position reports in time
+var ErrCalibrationTimeout = errors.New("renderer: terminal did not report cursor positions")
+
+// Calibrate measures probe graphemes by printing each one at the start of a
+// line on the alternate screen and asking the terminal where the cursor
+// ended up with a cursor position report (CSI 6n). The user's screen is
+// left untouched. In must be in raw mode so replies are not echoed or line
+// buffered. Anything else read from in, such as keys typed meanwhile, is
+// kept for PendingInput.
+func Calibrate(in io.Reader, out io.Writer, opts CalibrationOptions) (*WidthTable, error) {
+	probes := opts.Probes
+	if len(probes) == 0 {
+		probes = DefaultWidthProbes
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	name, version := DetectTerminal()
+	if opts.Terminal != "" {
+		name, version = opts.Terminal, opts.Version
+	}
+
+	// Send every probe at once so a slow link pays one round trip
+	var query strings.Builder
+	query.WriteString("\x1b[?1049h\x1b[?25l\x1b[H")
+	for _, probe := range probes {
+		query.WriteString("\r\x1b[2K")
+		query.WriteString(probe)
+		query.WriteString("\x1b[6n")
+	}
+	query.WriteString("\r\x1b[2K\x1b[?25h\x1b[?1049l")
+	if _, err := io.WriteString(out, query.String()); err != nil {
+		return nil, err
+	}
+
+	var columns []int
+	err := readReplies(in, timeout, func(buf []byte) (bool, []byte) {
+		var rest []byte
+		columns, rest = parseCursorReports(buf)
+		return len(columns) >= len(probes), rest
+	})
+	switch {
+	case errors.Is(err, errReplyTimeout):
+		return nil, ErrCalibrationTimeout
+	case err != nil:
+		return nil, fmt.Errorf("renderer: reading cursor position: %w", err)
+	}
+
+	table := NewWidthTable(name, version)
+	for i, probe := range probes {
+		table.Set(probe, columns[i]-1)
+	}
+	return table, nil
+}
+
+// CalibrateTerminal runs Calibrate against the controlling terminal on
+// stdin and stdout
+func CalibrateTerminal(opts CalibrationOptions) (*WidthTable, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil, errors.New("renderer: calibration requires a terminal")
+	}
+
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer term.Restore(fd, oldState)
+
+	return Calibrate(os.Stdin, os.Stdout, opts)
+}
+
+// LoadOrCalibrate returns the cached width table for the current terminal,
+// calibrating and caching a new one if none exists, and installs it
+func LoadOrCalibrate(opts CalibrationOptions) (*WidthTable, error) {
+	name, version := DetectTerminal()
+	if opts.Terminal != "" {
+		name, version = opts.Terminal, opts.Version
+	}
+	opts.Terminal, opts.Version = name, version
+
+	path, err := WidthCachePath(name, version)
+	if err != nil {
+		return nil, err
+	}
+
+	table, err := LoadWidthTable(path)
+	if err != nil {
+		table, err = CalibrateTerminal(opts)
+		if err != nil {
+			return nil, err
+		}
+		// A cache that cannot be written only costs a recalibration next run
+		_ = table.Save(path)
+	}
+
+	SetWidthOverrides(table)
+	return table, nil
+}
+
+// DetectTerminal identifies the terminal emulator from TERM_PROGRAM and
+// TERM_PROGRAM_VERSION, falling back to TERM
+func DetectTerminal() (name, version string) {
+	if name = os.Getenv("TERM_PROGRAM"); name != "" {
+		return name, os.Getenv("TERM_PROGRAM_VERSION")
+	}
+	return os.Getenv("TERM"), ""
+}
+
+// WidthCachePath returns the cache file for a terminal's width table in
+// the user cache directory
+func WidthCachePath(terminal, version string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	key := terminal
+	if version != "" {
+		key += "-" + version
+	}
+	key = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	if key == "" {
+		key = "unknown"
+	}
+
+	return filepath.Join(dir, "cli", "widths", key+".json"), nil
+}
+
+// LoadWidthTable reads a width table saved with Save
+func LoadWidthTable(path string) (*WidthTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var table WidthTable
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("renderer: invalid width table %s: %w", path, err)
+	}
+	return &table, nil
+}
+
+// Save writes the width table as JSON, creating parent directories
+func (t *WidthTable) Save(path string) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// parseCursorReports extracts the columns of complete cursor position
+// reports (ESC [ row ; col R) from buf. The rest of buf, including a
+// trailing partial report that the next read may complete, is returned
+// as other input.
+func parseCursorReports(buf []byte) (columns []int, rest []byte) {
+	for {
+		start := bytes.Index(buf, []byte("\x1b["))
+		if start < 0 {
+			return columns, append(rest, buf...)
+		}
+		rest = append(rest, buf[:start]...)
+		buf = buf[start:]
+
+		end := 2
+		for end < len(buf) && (buf[end] >= '0' && buf[end] <= '9' || buf[end] == ';') {
+			end++
+		}
+		if end == len(buf) {
+			// Incomplete report
+			return columns, append(rest, buf...)
+		}
+
+		params := strings.Split(string(buf[2:end]), ";")
+		if buf[end] != 'R' || len(params) != 2 {
+			// Some other escape sequence, such as a key
+			rest = append(rest, buf[:end]...)
+			buf = buf[end:]
+			continue
+		}
+		col, err := strconv.Atoi(params[1])
+		if err != nil {
+			rest = append(rest, buf[:end+1]...)
+			buf = buf[end+1:]
+			continue
+		}
+		columns = append(columns, col)
+		buf = buf[end+1:]
+	}
+}
@@ -0,0 +1,196 @@
+package renderer
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SCKelemen/layout"
+)
+
+func TestParseCursorReports(t *testing.T) {
+	columns, rest := parseCursorReports([]byte("x\x1b[1;3R\x1b[A\x1b[12;5R\x1b[1;"))
+
+	if want := []int{3, 5}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("Expected columns %v, got %v", want, columns)
+	}
+	if string(rest) != "x\x1b[A\x1b[1;" {
+		t.Errorf("Expected other input and the partial report to be kept, got %q", rest)
+	}
+}
+
+func TestCalibrate(t *testing.T) {
+	in := strings.NewReader("\x1b[1;3R\x1b[1;2R")
+	var out strings.Builder
+
+	table, err := Calibrate(in, &out, CalibrationOptions{
+		Probes:   []string{"👨‍👩‍👧", "※"},
+		Terminal: "TestTerm",
+		Version:  "1.0",
+	})
+	if err != nil {
+		t.Fatalf("Calibrate failed: %v", err)
+	}
+
+	if table.Terminal != "TestTerm" || table.Version != "1.0" {
+		t.Errorf("Expected table for TestTerm 1.0, got %s %s", table.Terminal, table.Version)
+	}
+	if w, _ := table.Lookup("👨‍👩‍👧"); w != 2 {
+		t.Errorf("Expected family width 2, got %d", w)
+	}
+	if w, _ := table.Lookup("※"); w != 1 {
+		t.Errorf("Expected reference mark width 1, got %d", w)
+	}
+
+	query := out.String()
+	if strings.Count(query, "\x1b[6n") != 2 {
+		t.Errorf("Expected one cursor position query per probe, got %q", query)
+	}
+	if !strings.HasPrefix(query, "\x1b[?1049h") || !strings.HasSuffix(query, "\x1b[?1049l") {
+		t.Errorf("Expected probes to be printed on the alternate screen, got %q", query)
+	}
+}
+
+func TestCalibrateTimeout(t *testing.T) {
+	in, _ := io.Pipe()
+	_, err := Calibrate(in, io.Discard, CalibrationOptions{
+		Probes:  []string{"❤️"},
+		Timeout: 10 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrCalibrationTimeout) {
+		t.Errorf("Expected ErrCalibrationTimeout, got %v", err)
+	}
+}
+
+func TestCalibrateTimeoutReleasesTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	_, err = Calibrate(r, io.Discard, CalibrationOptions{
+		Probes:  []string{"❤️"},
+		Timeout: 10 * time.Millisecond,
+	})
+	if !errors.Is(err, ErrCalibrationTimeout) {
+		t.Fatalf("Expected ErrCalibrationTimeout, got %v", err)
+	}
+
+	// Input after the timeout belongs to the app, not to a leftover read
+	w.Write([]byte("q"))
+	buf := make([]byte, 8)
+	n, _ := r.Read(buf)
+	if got := string(buf[:n]); got != "q" {
+		t.Errorf("Expected later input to reach the next reader, got %q", got)
+	}
+}
+
+func TestCalibrateKeepsOtherInput(t *testing.T) {
+	PendingInput()
+	in := strings.NewReader("a\x1b[1;3Rb\x1b[A")
+	if _, err := Calibrate(in, io.Discard, CalibrationOptions{Probes: []string{"※"}}); err != nil {
+		t.Fatalf("Calibrate failed: %v", err)
+	}
+	if got := string(PendingInput()); got != "ab\x1b[A" {
+		t.Errorf("Expected keys around the reply to be kept, got %q", got)
+	}
+	if got := PendingInput(); len(got) != 0 {
+		t.Errorf("Expected PendingInput to clear, got %q", got)
+	}
+}
+
+func TestWidthOverrides(t *testing.T) {
+	table := NewWidthTable("TestTerm", "")
+	table.Set("👨‍👩‍👧", 6)
+	table.Set("※", 2)
+	SetWidthOverrides(table)
+	t.Cleanup(func() { SetWidthOverrides(nil) })
+
+	if got := textMeasurer.Width("a👨‍👩‍👧"); got != 7 {
+		t.Errorf("Expected overridden width 7, got %v", got)
+	}
+	if got := textMeasurer.Width("※b"); got != 3 {
+		t.Errorf("Expected overridden width 3, got %v", got)
+	}
+
+	// Rendering reserves every cell the terminal uses for the glyph
+	s := NewScreen(8, 1)
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 8, Height: 1},
+	}
+	styled := NewStyledNode(node, nil)
+	styled.Content = "👨‍👩‍👧x"
+	s.Render(styled)
+
+	if got := s.Cells[0][6].Content; got != "x" {
+		t.Errorf("Expected text after the glyph at column 6, got %q", got)
+	}
+}
+
+func TestWidthOverridesWrapAndElide(t *testing.T) {
+	table := NewWidthTable("TestTerm", "")
+	table.Set("👨‍👩‍👧", 6)
+	SetWidthOverrides(table)
+	t.Cleanup(func() { SetWidthOverrides(nil) })
+
+	if got, want := WrapText("👨‍👩‍👧ab", 7), []string{"👨‍👩‍👧a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected wrap points from the calibrated width %q, got %q", want, got)
+	}
+	if got := textMeasurer.ElideEndWith("👨‍👩‍👧abc", 7, "…"); got != "👨‍👩‍👧…" {
+		t.Errorf("Expected elision by the calibrated width, got %q", got)
+	}
+	if got := textMeasurer.ElideStartWith("abc👨‍👩‍👧", 7, "…"); got != "…👨‍👩‍👧" {
+		t.Errorf("Expected start elision by the calibrated width, got %q", got)
+	}
+}
+
+func TestWidthTableSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "widths", "term.json")
+	table := NewWidthTable("TestTerm", "2.1")
+	table.Set("❤️", 1)
+
+	if err := table.Save(path); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	loaded, err := LoadWidthTable(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, table) {
+		t.Errorf("Expected %+v, got %+v", table, loaded)
+	}
+}
+
+func TestWidthCachePath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := WidthCachePath("iTerm.app", "3.5/beta")
+	if err != nil {
+		t.Fatalf("WidthCachePath failed: %v", err)
+	}
+	if got := filepath.Base(path); got != "iTerm.app-3.5_beta.json" {
+		t.Errorf("Expected sanitized file name, got %q", got)
+	}
+}
+
+func TestDetectTerminal(t *testing.T) {
+	t.Setenv("TERM_PROGRAM", "WezTerm")
+	t.Setenv("TERM_PROGRAM_VERSION", "20240203")
+	if name, version := DetectTerminal(); name != "WezTerm" || version != "20240203" {
+		t.Errorf("Expected WezTerm 20240203, got %s %s", name, version)
+	}
+
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("TERM", "xterm-kitty")
+	if name, version := DetectTerminal(); name != "xterm-kitty" || version != "" {
+		t.Errorf("Expected xterm-kitty, got %s %s", name, version)
+	}
+}
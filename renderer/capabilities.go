@@ -1,8 +1,11 @@
 package renderer
 
 import (
+	"io"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ColorMode represents the terminal's color capabilities
@@ -20,6 +23,10 @@ type TerminalCapabilities struct {
 	ColorMode   ColorMode
 	IsTTY       bool
 	SupportsAlt bool // Alternate screen buffer
+	Unicode     bool // Box drawing and other non-ASCII glyphs render correctly
+
+	// SynchronizedOutput reports support for synchronized updates (DEC mode 2026)
+	SynchronizedOutput bool
 }
 
 // DetectCapabilities detects the terminal's capabilities
@@ -28,6 +35,7 @@ func DetectCapabilities() *TerminalCapabilities {
 		ColorMode:   ColorModeNone,
 		IsTTY:       false,
 		SupportsAlt: true,
+		Unicode:     DetectUnicode(),
 	}
 
 	// Check if stdout is a terminal
@@ -41,6 +49,7 @@ func DetectCapabilities() *TerminalCapabilities {
 
 	// Detect color mode
 	caps.ColorMode = detectColorMode()
+	caps.SynchronizedOutput = DetectSynchronizedOutput()
 
 	return caps
 }
@@ -93,6 +102,96 @@ func detectColorMode() ColorMode {
 	return ColorModeNone
 }
 
+// DetectUnicode reports whether the locale and terminal can display
+// Unicode glyphs such as box drawing characters. An unset locale is assumed
+// to be UTF-8; an explicit non-UTF-8 locale or a terminal known to lack box
+// drawing switches output to ASCII.
+func DetectUnicode() bool {
+	switch os.Getenv("TERM") {
+	case "dumb", "vt52", "vt100", "cons25":
+		return false
+	}
+
+	// The first non-empty variable wins, as in setlocale(3)
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := os.Getenv(name)
+		if locale == "" {
+			continue
+		}
+		locale = strings.ToLower(locale)
+		return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+	}
+
+	return true
+}
+
+// DetectSynchronizedOutput reports whether the terminal is known to support
+// synchronized updates (DEC mode 2026), judging by its environment. Use
+// QuerySynchronizedOutput to ask the terminal directly.
+func DetectSynchronizedOutput() bool {
+	// Windows Terminal
+	if os.Getenv("WT_SESSION") != "" {
+		return true
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "ghostty", "vscode", "tmux":
+		return true
+	}
+
+	term := os.Getenv("TERM")
+	for _, name := range []string{"kitty", "alacritty", "foot", "ghostty", "contour", "wezterm"} {
+		if strings.Contains(term, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// QuerySynchronizedOutput asks the terminal whether it supports synchronized
+// updates with a DEC private mode report request (DECRQM). In must be in raw
+// mode. Terminals that do not recognize the request stay silent, which is
+// reported as unsupported once the timeout expires. Anything else read from
+// in, such as keys typed meanwhile, is kept for PendingInput.
+func QuerySynchronizedOutput(in io.Reader, out io.Writer, timeout time.Duration) bool {
+	if _, err := io.WriteString(out, "\x1b[?2026$p"); err != nil {
+		return false
+	}
+
+	var supported bool
+	err := readReplies(in, timeout, func(buf []byte) (bool, []byte) {
+		var ok bool
+		var rest []byte
+		supported, ok, rest = parseModeReport(buf, 2026)
+		return ok, rest
+	})
+	return err == nil && supported
+}
+
+// parseModeReport finds a DECRPM reply (ESC [ ? mode ; value $ y) for the
+// given mode. Values 1 to 3 mean the mode is recognized; 0 means unknown
+// and 4 permanently reset. The input around the reply is returned as rest.
+func parseModeReport(buf []byte, mode int) (supported, ok bool, rest []byte) {
+	prefix := "\x1b[?" + strconv.Itoa(mode) + ";"
+	reply := string(buf)
+	start := strings.Index(reply, prefix)
+	if start < 0 {
+		return false, false, buf
+	}
+	value := reply[start+len(prefix):]
+
+	end := strings.Index(value, "$y")
+	if end < 0 {
+		return false, false, buf
+	}
+	rest = append([]byte(reply[:start]), value[end+2:]...)
+	n, err := strconv.Atoi(value[:end])
+	if err != nil {
+		return false, true, rest
+	}
+	return n >= 1 && n <= 3, true, rest
+}
+
 // String returns a human-readable description of the color mode
 func (cm ColorMode) String() string {
 	switch cm {
@@ -0,0 +1,124 @@
+package renderer
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDetectUnicode(t *testing.T) {
+	tests := []struct {
+		name     string
+		term     string
+		lcAll    string
+		lang     string
+		expected bool
+	}{
+		{"UTF8Lang", "xterm-256color", "", "en_US.UTF-8", true},
+		{"LowercaseUTF8", "xterm", "", "de_DE.utf8", true},
+		{"CLocale", "xterm", "", "C", false},
+		{"LCAllOverridesLang", "xterm", "POSIX", "en_US.UTF-8", false},
+		{"UnsetLocale", "xterm", "", "", true},
+		{"DumbTerminal", "dumb", "", "en_US.UTF-8", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TERM", tt.term)
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_CTYPE", "")
+			t.Setenv("LANG", tt.lang)
+
+			if got := DetectUnicode(); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseModeReport(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		supported bool
+		ok        bool
+	}{
+		{"Set", "\x1b[?2026;1$y", true, true},
+		{"Reset", "\x1b[?2026;2$y", true, true},
+		{"NotRecognized", "\x1b[?2026;0$y", false, true},
+		{"PermanentlyReset", "\x1b[?2026;4$y", false, true},
+		{"Incomplete", "\x1b[?2026;1", false, false},
+		{"OtherMode", "\x1b[?25;1$y", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supported, ok, _ := parseModeReport([]byte(tt.reply), 2026)
+			if supported != tt.supported || ok != tt.ok {
+				t.Errorf("Expected (%v, %v), got (%v, %v)", tt.supported, tt.ok, supported, ok)
+			}
+		})
+	}
+}
+
+func TestQuerySynchronizedOutput(t *testing.T) {
+	var out strings.Builder
+	if !QuerySynchronizedOutput(strings.NewReader("\x1b[?2026;2$y"), &out, time.Second) {
+		t.Error("Expected synchronized output to be supported")
+	}
+	if out.String() != "\x1b[?2026$p" {
+		t.Errorf("Expected DECRQM query, got %q", out.String())
+	}
+
+	if QuerySynchronizedOutput(strings.NewReader(""), io.Discard, time.Second) {
+		t.Error("Expected silence to mean unsupported")
+	}
+}
+
+func TestQuerySynchronizedOutputKeepsOtherInput(t *testing.T) {
+	PendingInput()
+	if !QuerySynchronizedOutput(strings.NewReader("j\x1b[?2026;1$yk"), io.Discard, time.Second) {
+		t.Fatal("Expected synchronized output to be supported")
+	}
+	if got := string(PendingInput()); got != "jk" {
+		t.Errorf("Expected keys around the reply to be kept, got %q", got)
+	}
+}
+
+func TestQuerySynchronizedOutputTimeoutReleasesTerminal(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if QuerySynchronizedOutput(r, io.Discard, 10*time.Millisecond) {
+		t.Fatal("Expected silence to mean unsupported")
+	}
+
+	// Input after the timeout belongs to the app, not to a leftover read
+	w.Write([]byte("q"))
+	buf := make([]byte, 8)
+	n, _ := r.Read(buf)
+	if got := string(buf[:n]); got != "q" {
+		t.Errorf("Expected later input to reach the next reader, got %q", got)
+	}
+}
+
+func TestDetectSynchronizedOutput(t *testing.T) {
+	t.Setenv("WT_SESSION", "")
+	t.Setenv("TERM_PROGRAM", "")
+
+	t.Setenv("TERM", "xterm-kitty")
+	if !DetectSynchronizedOutput() {
+		t.Error("Expected kitty to support synchronized output")
+	}
+
+	t.Setenv("TERM", "xterm-256color")
+	if DetectSynchronizedOutput() {
+		t.Error("Expected plain xterm not to be assumed to support synchronized output")
+	}
+}
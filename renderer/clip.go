@@ -0,0 +1,89 @@
+package renderer
+
+import "sort"
+
+// clipRect is a screen rectangle outside which painting is dropped
+type clipRect struct {
+	x0, y0, x1, y1 int // x1 and y1 are exclusive
+}
+
+// contains reports whether the cell at x, y is inside the rectangle
+func (r *clipRect) contains(x, y int) bool {
+	return r == nil || (x >= r.x0 && x < r.x1 && y >= r.y0 && y < r.y1)
+}
+
+// intersect narrows r to the box at x, y of size w by h. A nil r is
+// unbounded.
+func (r *clipRect) intersect(x, y, w, h int) *clipRect {
+	next := &clipRect{x0: x, y0: y, x1: x + w, y1: y + h}
+	if r != nil {
+		next.x0 = max(next.x0, r.x0)
+		next.y0 = max(next.y0, r.y0)
+		next.x1 = min(next.x1, r.x1)
+		next.y1 = min(next.y1, r.y1)
+	}
+	return next
+}
+
+// clipBox returns the box a node clips its content and children to: the
+// painted box inside the border. Ok is false for nodes that do not clip.
+func clipBox(node *StyledNode, x, y, w, h int) (cx, cy, cw, ch int, ok bool) {
+	if node.Style == nil || node.Style.Overflow == OverflowVisible {
+		return 0, 0, 0, 0, false
+	}
+	if b := node.Style.Border; b != nil {
+		if b.Top {
+			y++
+			h--
+		}
+		if b.Bottom {
+			h--
+		}
+		if b.Left {
+			x++
+			w--
+		}
+		if b.Right {
+			w--
+		}
+	}
+	return x, y, max(w, 0), max(h, 0), true
+}
+
+// scrollOffset returns how far a node's content and children are shifted
+// up and left
+func scrollOffset(node *StyledNode) (x, y int) {
+	if node.Style == nil || node.Style.Overflow != OverflowScroll {
+		return 0, 0
+	}
+	return node.ScrollX, node.ScrollY
+}
+
+// paintOrder returns children in the order they are painted: by
+// ascending ZIndex of their layout style, then in tree order. Hit-testing
+// walks the same order backwards.
+func paintOrder(children []*StyledNode) []*StyledNode {
+	stacked := false
+	for _, child := range children {
+		if child != nil && child.Node != nil && child.Node.Style.ZIndex != 0 {
+			stacked = true
+			break
+		}
+	}
+	if !stacked {
+		return children
+	}
+
+	ordered := append([]*StyledNode(nil), children...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return zIndex(ordered[i]) < zIndex(ordered[j])
+	})
+	return ordered
+}
+
+func zIndex(node *StyledNode) int {
+	if node == nil || node.Node == nil {
+		return 0
+	}
+	return node.Node.Style.ZIndex
+}
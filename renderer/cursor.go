@@ -0,0 +1,83 @@
+package renderer
+
+import "fmt"
+
+// CursorShape is a terminal cursor shape, as numbered by DECSCUSR
+type CursorShape int
+
+const (
+	CursorDefault CursorShape = iota // The terminal's configured shape
+	CursorBlinkingBlock
+	CursorBlock
+	CursorBlinkingUnderline
+	CursorUnderline
+	CursorBlinkingBar
+	CursorBar
+)
+
+// Sequence returns the DECSCUSR sequence that sets the shape
+func (c CursorShape) Sequence() string {
+	return fmt.Sprintf("\x1b[%d q", int(c))
+}
+
+// Cursor asks for the terminal cursor at a cell of a node. It is shown
+// only while the node has focus.
+type Cursor struct {
+	X, Y  int // Cell in the node's content, inside any border
+	Shape CursorShape
+}
+
+// placedCursor is a cursor at a screen position
+type placedCursor struct {
+	x, y  int
+	shape CursorShape
+}
+
+// placeCursor records the cursor of node, painted at x, y with size w, h.
+// Cursors outside the node's visible content are not shown.
+func (s *Screen) placeCursor(node *StyledNode, x, y, w, h int) {
+	if node.Style != nil && node.Style.Border != nil {
+		x, y, w, h = x+1, y+1, w-2, h-2
+	}
+	scrollX, scrollY := scrollOffset(node)
+	cx := x + node.Cursor.X - scrollX
+	cy := y + node.Cursor.Y - scrollY
+	if cx < x || cx >= x+w || cy < y || cy >= y+h {
+		return
+	}
+	if cx < 0 || cx >= s.Width || cy < 0 || cy >= s.Height || !s.clip.contains(cx, cy) {
+		return
+	}
+	s.cursor = &placedCursor{x: cx, y: cy, shape: node.Cursor.Shape}
+}
+
+// Cursor returns where the focused node placed the terminal cursor in the
+// last Render, and false when no cursor should be shown
+func (s *Screen) Cursor() (x, y int, shape CursorShape, ok bool) {
+	if s.cursor == nil {
+		return 0, 0, CursorDefault, false
+	}
+	return s.cursor.x, s.cursor.y, s.cursor.shape, true
+}
+
+// SetCursorEmulation sets whether Render draws the cursor into the cells as
+// a reversed cell. It is on by default, for output where the terminal
+// cursor cannot be moved; turn it off when the caller shows the terminal
+// cursor at the position Cursor returns.
+func (s *Screen) SetCursorEmulation(enabled bool) {
+	s.emulateCursor = enabled
+}
+
+// drawCursor reverses the cell under the cursor
+func (s *Screen) drawCursor() {
+	if s.cursor == nil {
+		return
+	}
+	cell := &s.Cells[s.cursor.y][s.cursor.x]
+	style := Style{}
+	if cell.Style != nil {
+		style = *cell.Style
+	}
+	style.Reverse = !style.Reverse
+	cell.Style = &style
+}
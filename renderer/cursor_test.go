@@ -0,0 +1,65 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/layout"
+)
+
+// cursorTree builds a bordered 6x3 field at 2,1 asking for the cursor at x
+func cursorTree(x int) (root, field *StyledNode) {
+	root = NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 10, Height: 5}}, nil)
+	style := &Style{}
+	style.WithBorder(RoundedBorder)
+	field = NewStyledNode(&layout.Node{Rect: layout.Rect{X: 2, Y: 1, Width: 6, Height: 3}}, style)
+	field.Focusable = true
+	field.Cursor = &Cursor{X: x, Shape: CursorBar}
+	root.AddChild(field)
+	return root, field
+}
+
+func TestScreenCursor(t *testing.T) {
+	root, field := cursorTree(2)
+	screen := NewScreen(10, 5)
+	screen.SetOutputMode(OutputModeANSI)
+
+	screen.Render(root)
+	if _, _, _, ok := screen.Cursor(); ok {
+		t.Error("Expected no cursor while the field is unfocused")
+	}
+
+	NewFocusManager(root).Focus(field)
+	screen.Render(root)
+	x, y, shape, ok := screen.Cursor()
+	if !ok || x != 5 || y != 2 || shape != CursorBar {
+		t.Errorf("Expected a bar cursor at 5,2 inside the border, got %d,%d %v %v", x, y, shape, ok)
+	}
+	if cell := screen.Cells[2][5]; cell.Style == nil || !cell.Style.Reverse {
+		t.Error("Expected the emulated cursor to reverse its cell")
+	}
+
+	screen.SetCursorEmulation(false)
+	screen.Render(root)
+	if cell := screen.Cells[2][5]; cell.Style != nil && cell.Style.Reverse {
+		t.Error("Expected no reversed cell without cursor emulation")
+	}
+}
+
+func TestScreenCursorOutsideContent(t *testing.T) {
+	root, field := cursorTree(4) // Past the 4 content cells of the field
+	NewFocusManager(root).Focus(field)
+	screen := NewScreen(10, 5)
+	screen.Render(root)
+	if _, _, _, ok := screen.Cursor(); ok {
+		t.Error("Expected a cursor past the content box to be hidden")
+	}
+}
+
+func TestCursorShapeSequence(t *testing.T) {
+	if got := CursorBlinkingBar.Sequence(); got != "\x1b[5 q" {
+		t.Errorf("Expected DECSCUSR 5, got %q", got)
+	}
+	if got := CursorDefault.Sequence(); got != "\x1b[0 q" {
+		t.Errorf("Expected DECSCUSR 0, got %q", got)
+	}
+}
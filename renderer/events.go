@@ -0,0 +1,403 @@
+package renderer
+
+import (
+	"slices"
+	"time"
+
+	"github.com/SCKelemen/cli/input"
+)
+
+// EventType identifies an event dispatched to StyledNode listeners
+type EventType int
+
+const (
+	EventMouseDown   EventType = iota // A button was pressed over the target
+	EventMouseUp                      // A button was released over the target
+	EventMouseMove                    // The pointer moved over the target
+	EventClick                        // A press and release over the target without dragging
+	EventDoubleClick                  // A second click on the same node within the double-click interval
+	EventWheel                        // A wheel was turned over the target
+	EventMouseEnter                   // The pointer moved onto the target; does not bubble
+	EventMouseLeave                   // The pointer moved off the target; does not bubble
+	EventDragStart                    // The pointer moved with a button held down on the target
+	EventDrag                         // The pointer moved during a drag started on the target
+	EventDragEnd                      // The button was released, ending a drag started on the target
+	EventKeyDown                      // A key was pressed while the target had focus
+	EventFocus                        // The target gained focus; does not bubble
+	EventBlur                         // The target lost focus; does not bubble
+	EventPaste                        // Text was pasted while the target had focus
+)
+
+var eventTypeNames = map[EventType]string{
+	EventMouseDown:   "mousedown",
+	EventMouseUp:     "mouseup",
+	EventMouseMove:   "mousemove",
+	EventClick:       "click",
+	EventDoubleClick: "dblclick",
+	EventWheel:       "wheel",
+	EventMouseEnter:  "mouseenter",
+	EventMouseLeave:  "mouseleave",
+	EventDragStart:   "dragstart",
+	EventDrag:        "drag",
+	EventDragEnd:     "dragend",
+	EventKeyDown:     "keydown",
+	EventFocus:       "focus",
+	EventBlur:        "blur",
+	EventPaste:       "paste",
+}
+
+// String returns the DOM name of the event type
+func (t EventType) String() string {
+	if name, ok := eventTypeNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// bubbles reports whether events of this type are delivered to the
+// target's ancestors after the target
+func (t EventType) bubbles() bool {
+	switch t {
+	case EventMouseEnter, EventMouseLeave, EventFocus, EventBlur:
+		return false
+	}
+	return true
+}
+
+// EventPhase is the stage of dispatch an event is in
+type EventPhase int
+
+const (
+	PhaseCapture EventPhase = iota + 1 // Travelling from the root down to the target's parent
+	PhaseTarget                        // At the target
+	PhaseBubble                        // Travelling from the target's parent back up to the root
+)
+
+// Event is a pointer, key or focus event delivered to the listeners of the
+// nodes on the path from the root to its target
+type Event struct {
+	Type          EventType
+	Phase         EventPhase
+	Target        *StyledNode // Deepest node the event is for
+	CurrentTarget *StyledNode // Node whose listener is running
+
+	X, Y           int // Screen cell of the pointer
+	LocalX, LocalY int // Pointer relative to CurrentTarget's laid-out rect
+
+	Button input.MouseButton // Button pressed, released, dragged with or wheel turned
+	Mod    input.Modifier
+
+	Key     input.KeyEvent // Key pressed, for EventKeyDown
+	Text    string         // Text pasted, for EventPaste
+	Related *StyledNode    // Node losing focus for EventFocus, gaining it for EventBlur
+
+	stopped   bool
+	prevented bool
+}
+
+// StopPropagation keeps the event from reaching further nodes. Other
+// listeners on the current node still run.
+func (e *Event) StopPropagation() {
+	e.stopped = true
+}
+
+// PreventDefault marks the event as handled, which for EventKeyDown and
+// EventPaste keeps it from moving focus or reaching the app
+func (e *Event) PreventDefault() {
+	e.prevented = true
+}
+
+// DefaultPrevented reports whether a listener called PreventDefault
+func (e *Event) DefaultPrevented() bool {
+	return e.prevented
+}
+
+// EventHandler handles an event
+type EventHandler func(e *Event)
+
+// listener is an event handler registered on a node
+type listener struct {
+	eventType EventType
+	capture   bool
+	handler   EventHandler
+}
+
+// On adds a handler for events of type t that run when the node is the
+// target or, for bubbling events, a descendant is
+func (n *StyledNode) On(t EventType, handler EventHandler) {
+	n.listeners = append(n.listeners, listener{eventType: t, handler: handler})
+}
+
+// OnCapture adds a handler for events of type t that runs on the way down
+// to the target, before any handler added with On
+func (n *StyledNode) OnCapture(t EventType, handler EventHandler) {
+	n.listeners = append(n.listeners, listener{eventType: t, capture: true, handler: handler})
+}
+
+// dispatchEvent delivers e along path, from the root to the target, in
+// capture, target and bubble phases. It reports whether any handler ran.
+func dispatchEvent(path []*StyledNode, e *Event) bool {
+	if len(path) == 0 {
+		return false
+	}
+
+	origins := pathOrigins(path)
+	last := len(path) - 1
+	e.Target = path[last]
+	handled := false
+
+	deliver := func(i int, phase EventPhase, capture bool) {
+		node := path[i]
+		for _, l := range node.listeners {
+			if l.eventType != e.Type || l.capture != capture {
+				continue
+			}
+			e.Phase = phase
+			e.CurrentTarget = node
+			e.LocalX, e.LocalY = e.X-origins[i][0], e.Y-origins[i][1]
+			l.handler(e)
+			handled = true
+		}
+	}
+
+	for i := 0; i < last && !e.stopped; i++ {
+		deliver(i, PhaseCapture, true)
+	}
+	if !e.stopped {
+		deliver(last, PhaseTarget, true)
+		deliver(last, PhaseTarget, false)
+	}
+	if e.Type.bubbles() {
+		for i := last - 1; i >= 0 && !e.stopped; i-- {
+			deliver(i, PhaseBubble, false)
+		}
+	}
+	return handled
+}
+
+// DefaultDoubleClickInterval is the longest gap between two clicks that
+// counts as a double click
+const DefaultDoubleClickInterval = 500 * time.Millisecond
+
+// EventDispatcher turns raw mouse events into DOM-style events on a laid
+// out StyledNode tree. It tracks the hovered path for enter and leave
+// events, the pressed node for clicks and drags, and the last click for
+// double clicks.
+type EventDispatcher struct {
+	// DoubleClickInterval is the longest gap between two clicks on the
+	// same node that dispatches EventDoubleClick
+	DoubleClickInterval time.Duration
+
+	root  *StyledNode
+	hover trackedPath // Path under the pointer
+	focus *FocusManager
+
+	press       trackedPath // Path under the pointer when the button went down
+	pressButton input.MouseButton
+	pressX      int
+	pressY      int
+	dragging    bool
+
+	lastClick       trackedPath
+	lastClickButton input.MouseButton
+	lastClickAt     time.Time
+
+	now func() time.Time
+}
+
+// NewEventDispatcher creates a dispatcher for the tree at root
+func NewEventDispatcher(root *StyledNode) *EventDispatcher {
+	return &EventDispatcher{
+		DoubleClickInterval: DefaultDoubleClickInterval,
+		root:                root,
+		now:                 time.Now,
+	}
+}
+
+// SetRoot sets the tree events are hit-tested against. It should be the
+// tree as last laid out and drawn. The hovered, pressed and last clicked
+// nodes are found again in a rebuilt tree, so a tree rebuilt between a
+// press and its release still gets the click, and hovering it does not
+// repeat enter and leave events.
+func (d *EventDispatcher) SetRoot(root *StyledNode) {
+	d.root = root
+	d.hover = d.hover.remap(root)
+	d.press = d.press.remap(root)
+	d.lastClick = d.lastClick.remap(root)
+}
+
+// SetFocusManager makes a button press focus the deepest focusable node
+// under the pointer
+func (d *EventDispatcher) SetFocusManager(focus *FocusManager) {
+	d.focus = focus
+}
+
+// Dispatch hit-tests a mouse event and delivers the events it produces.
+// It reports whether any handler ran.
+func (d *EventDispatcher) Dispatch(m input.MouseEvent) bool {
+	path := HitPath(d.root, m.X, m.Y)
+	handled := d.moveHover(path, m)
+
+	newEvent := func(t EventType, button input.MouseButton) *Event {
+		return &Event{Type: t, X: m.X, Y: m.Y, Button: button, Mod: m.Mod}
+	}
+	dispatch := func(path []*StyledNode, t EventType, button input.MouseButton) {
+		if dispatchEvent(path, newEvent(t, button)) {
+			handled = true
+		}
+	}
+
+	switch {
+	case m.IsWheel():
+		if m.Action == input.MousePress {
+			dispatch(path, EventWheel, m.Button)
+		}
+
+	case m.Action == input.MousePress:
+		dispatch(path, EventMouseDown, m.Button)
+		if d.focus != nil {
+			d.focus.focusPath(path)
+		}
+		d.press = track(path)
+		d.pressButton = m.Button
+		d.pressX, d.pressY = m.X, m.Y
+		d.dragging = false
+
+	case m.Action == input.MouseMotion:
+		if len(d.press.nodes) > 0 {
+			if !d.dragging && (m.X != d.pressX || m.Y != d.pressY) {
+				d.dragging = true
+				dispatch(d.press.nodes, EventDragStart, d.pressButton)
+			}
+			if d.dragging {
+				dispatch(d.press.nodes, EventDrag, d.pressButton)
+			}
+		}
+		dispatch(path, EventMouseMove, m.Button)
+
+	case m.Action == input.MouseRelease:
+		button := m.Button
+		if button == input.MouseNone {
+			// Legacy encodings do not report which button was released
+			button = d.pressButton
+		}
+		dispatch(path, EventMouseUp, button)
+
+		if d.dragging {
+			dispatch(d.press.nodes, EventDragEnd, button)
+		} else if len(d.press.nodes) > 0 {
+			// As in the DOM, a click goes to the deepest node that was
+			// under both the press and the release
+			if target := commonPrefix(d.press.nodes, path); len(target) > 0 {
+				dispatch(target, EventClick, button)
+				d.click(target, button, dispatch)
+			}
+		}
+		d.press = trackedPath{}
+		d.dragging = false
+	}
+	return handled
+}
+
+// click dispatches EventDoubleClick if this click follows another on the
+// same node soon enough, and otherwise remembers it
+func (d *EventDispatcher) click(target []*StyledNode, button input.MouseButton, dispatch func([]*StyledNode, EventType, input.MouseButton)) {
+	node := target[len(target)-1]
+	now := d.now()
+	if last := d.lastClick.nodes; len(last) > 0 && node == last[len(last)-1] &&
+		button == d.lastClickButton && now.Sub(d.lastClickAt) <= d.DoubleClickInterval {
+		dispatch(target, EventDoubleClick, button)
+		d.lastClick = trackedPath{}
+		return
+	}
+	d.lastClick, d.lastClickButton, d.lastClickAt = track(target), button, now
+}
+
+// moveHover dispatches leave events to the nodes no longer under the
+// pointer, deepest first, and enter events to the nodes newly under it,
+// outermost first
+func (d *EventDispatcher) moveHover(path []*StyledNode, m input.MouseEvent) bool {
+	hover := d.hover.nodes
+	shared := len(commonPrefix(hover, path))
+	handled := false
+
+	for i := len(hover) - 1; i >= shared; i-- {
+		e := &Event{Type: EventMouseLeave, X: m.X, Y: m.Y, Button: m.Button, Mod: m.Mod}
+		if dispatchEvent(hover[:i+1], e) {
+			handled = true
+		}
+	}
+	for i := shared; i < len(path); i++ {
+		e := &Event{Type: EventMouseEnter, X: m.X, Y: m.Y, Button: m.Button, Mod: m.Mod}
+		if dispatchEvent(path[:i+1], e) {
+			handled = true
+		}
+	}
+
+	d.hover = track(path)
+	return handled
+}
+
+// trackedPath is a path from the root kept between events, with the
+// position of each node among its parent's children when it was recorded
+type trackedPath struct {
+	nodes     []*StyledNode
+	positions []int
+}
+
+// track records a path and the positions of its nodes
+func track(nodes []*StyledNode) trackedPath {
+	positions := make([]int, len(nodes))
+	for i := 1; i < len(nodes); i++ {
+		positions[i] = slices.Index(nodes[i-1].Children, nodes[i])
+	}
+	return trackedPath{nodes: nodes, positions: positions}
+}
+
+// remap finds the path again in the tree at root, one level at a time:
+// each node is matched among the children of the node matched above it,
+// keeping the same node if it is still there, else taking the child with
+// its ID, else the child at its recorded position. The path stops where
+// nothing matches.
+func (p trackedPath) remap(root *StyledNode) trackedPath {
+	if root == nil || len(p.nodes) == 0 {
+		return trackedPath{}
+	}
+	nodes := []*StyledNode{root}
+	for i := 1; i < len(p.nodes); i++ {
+		next := matchChild(nodes[i-1], p.nodes[i], p.positions[i])
+		if next == nil {
+			break
+		}
+		nodes = append(nodes, next)
+	}
+	return track(nodes)
+}
+
+// matchChild returns the child of parent standing for old, a node of an
+// earlier tree that was at position among its siblings
+func matchChild(parent, old *StyledNode, position int) *StyledNode {
+	if slices.Contains(parent.Children, old) {
+		return old
+	}
+	if old.ID != "" {
+		for _, child := range parent.Children {
+			if child != nil && child.ID == old.ID {
+				return child
+			}
+		}
+	}
+	if position >= 0 && position < len(parent.Children) {
+		return parent.Children[position]
+	}
+	return nil
+}
+
+// commonPrefix returns the leading nodes a and b share
+func commonPrefix(a, b []*StyledNode) []*StyledNode {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
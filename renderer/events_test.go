@@ -0,0 +1,204 @@
+package renderer
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/layout"
+)
+
+// eventTree builds a root with a panel holding a button, and a sibling
+// label, recording every event each node receives
+func eventTree(log *[]string) (root, panel, button, label *StyledNode) {
+	root = NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 20, Height: 10}}, nil)
+	panel = NewStyledNode(&layout.Node{Rect: layout.Rect{X: 2, Y: 2, Width: 10, Height: 5}}, nil)
+	button = NewStyledNode(&layout.Node{Rect: layout.Rect{X: 1, Y: 1, Width: 4, Height: 1}}, nil)
+	label = NewStyledNode(&layout.Node{Rect: layout.Rect{X: 14, Y: 2, Width: 4, Height: 1}}, nil)
+	panel.AddChild(button)
+	root.AddChild(panel)
+	root.AddChild(label)
+
+	names := map[*StyledNode]string{root: "root", panel: "panel", button: "button", label: "label"}
+	for node, name := range names {
+		for t := EventMouseDown; t <= EventDragEnd; t++ {
+			node.On(t, func(e *Event) {
+				*log = append(*log, fmt.Sprintf("%s %s", name, e.Type))
+			})
+		}
+	}
+	return root, panel, button, label
+}
+
+func TestDispatchPhases(t *testing.T) {
+	var log []string
+	root, panel, button, _ := eventTree(&log)
+	root.OnCapture(EventClick, func(e *Event) {
+		log = append(log, fmt.Sprintf("capture root %d,%d", e.LocalX, e.LocalY))
+	})
+	button.On(EventClick, func(e *Event) {
+		if e.Phase != PhaseTarget || e.Target != button || e.CurrentTarget != button {
+			t.Errorf("Expected the target phase at the button, got phase %d", e.Phase)
+		}
+		log = append(log, fmt.Sprintf("local %d,%d", e.LocalX, e.LocalY))
+	})
+
+	path := HitPath(root, 4, 3)
+	log = nil
+	dispatchEvent(path, &Event{Type: EventClick, X: 4, Y: 3})
+	expected := []string{"capture root 4,3", "button click", "local 1,0", "panel click", "root click"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+
+	panel.On(EventClick, func(e *Event) { e.StopPropagation() })
+	log = nil
+	dispatchEvent(path, &Event{Type: EventClick, X: 4, Y: 3})
+	expected = []string{"capture root 4,3", "button click", "local 1,0", "panel click"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected propagation to stop at the panel, got %v", log)
+	}
+}
+
+func TestEventDispatcher(t *testing.T) {
+	var log []string
+	root, _, _, _ := eventTree(&log)
+	d := NewEventDispatcher(root)
+	now := time.Unix(0, 0)
+	d.now = func() time.Time { return now }
+
+	press := input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MousePress}
+	release := input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MouseRelease}
+
+	tests := []struct {
+		name     string
+		event    input.MouseEvent
+		advance  time.Duration
+		expected []string
+	}{
+		{"Enter", input.MouseEvent{X: 3, Y: 3, Action: input.MouseMotion},
+			0, []string{"root mouseenter", "panel mouseenter", "button mouseenter", "button mousemove", "panel mousemove", "root mousemove"}},
+		{"Press", press,
+			0, []string{"button mousedown", "panel mousedown", "root mousedown"}},
+		{"Click", release,
+			0, []string{"button mouseup", "panel mouseup", "root mouseup", "button click", "panel click", "root click"}},
+		{"SecondPress", press,
+			100 * time.Millisecond, []string{"button mousedown", "panel mousedown", "root mousedown"}},
+		{"DoubleClick", release,
+			0, []string{"button mouseup", "panel mouseup", "root mouseup", "button click", "panel click", "root click", "button dblclick", "panel dblclick", "root dblclick"}},
+		{"Wheel", input.MouseEvent{X: 4, Y: 3, Button: input.MouseWheelDown, Action: input.MousePress},
+			0, []string{"button wheel", "panel wheel", "root wheel"}},
+		{"Leave", input.MouseEvent{X: 15, Y: 2, Action: input.MouseMotion},
+			0, []string{"button mouseleave", "panel mouseleave", "label mouseenter", "label mousemove", "root mousemove"}},
+		{"PressLabel", input.MouseEvent{X: 15, Y: 2, Button: input.MouseLeft, Action: input.MousePress},
+			0, []string{"label mousedown", "root mousedown"}},
+		{"DragStart", input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MouseMotion},
+			0, []string{"label mouseleave", "panel mouseenter", "button mouseenter", "label dragstart", "root dragstart", "label drag", "root drag", "button mousemove", "panel mousemove", "root mousemove"}},
+		{"DragEnd", release,
+			0, []string{"button mouseup", "panel mouseup", "root mouseup", "label dragend", "root dragend"}},
+		{"ClickOnCommonAncestor", input.MouseEvent{X: 15, Y: 2, Button: input.MouseLeft, Action: input.MousePress},
+			0, []string{"button mouseleave", "panel mouseleave", "label mouseenter", "label mousedown", "root mousedown"}},
+		{"ReleaseElsewhere", input.MouseEvent{X: 0, Y: 0, Action: input.MouseRelease},
+			0, []string{"label mouseleave", "root mouseup", "root click"}},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+		log = nil
+		handled := d.Dispatch(tt.event)
+		if !reflect.DeepEqual(log, tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, log)
+		}
+		if !handled {
+			t.Errorf("%s: expected the event to be handled", tt.name)
+		}
+	}
+}
+
+func TestEventDispatcherDoubleClickInterval(t *testing.T) {
+	var clicks, doubles int
+	root := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 4, Height: 1}}, nil)
+	root.On(EventClick, func(e *Event) { clicks++ })
+	root.On(EventDoubleClick, func(e *Event) { doubles++ })
+
+	d := NewEventDispatcher(root)
+	now := time.Unix(0, 0)
+	d.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		d.Dispatch(input.MouseEvent{Button: input.MouseLeft, Action: input.MousePress})
+		d.Dispatch(input.MouseEvent{Button: input.MouseLeft, Action: input.MouseRelease})
+		now = now.Add(time.Second)
+	}
+	if clicks != 3 || doubles != 0 {
+		t.Errorf("Expected 3 clicks and no double clicks, got %d and %d", clicks, doubles)
+	}
+
+	if d.Dispatch(input.MouseEvent{X: 10, Y: 10, Button: input.MouseLeft, Action: input.MousePress}) {
+		t.Error("Expected a press outside the tree to be unhandled")
+	}
+}
+
+func TestEventDispatcherRebuiltTree(t *testing.T) {
+	var log []string
+	root, _, _, _ := eventTree(&log)
+	d := NewEventDispatcher(root)
+	now := time.Unix(0, 0)
+	d.now = func() time.Time { return now }
+
+	// Each step rebuilds the tree, as a view function does every frame
+	rebuild := func() {
+		root, _, _, _ = eventTree(&log)
+		d.SetRoot(root)
+	}
+	steps := []struct {
+		name     string
+		event    input.MouseEvent
+		expected []string
+	}{
+		{"Enter", input.MouseEvent{X: 4, Y: 3, Action: input.MouseMotion},
+			[]string{"root mouseenter", "panel mouseenter", "button mouseenter", "button mousemove", "panel mousemove", "root mousemove"}},
+		{"Press", input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MousePress},
+			[]string{"button mousedown", "panel mousedown", "root mousedown"}},
+		{"Click", input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MouseRelease},
+			[]string{"button mouseup", "panel mouseup", "root mouseup", "button click", "panel click", "root click"}},
+		{"SecondPress", input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MousePress},
+			[]string{"button mousedown", "panel mousedown", "root mousedown"}},
+		{"DoubleClick", input.MouseEvent{X: 4, Y: 3, Button: input.MouseLeft, Action: input.MouseRelease},
+			[]string{"button mouseup", "panel mouseup", "root mouseup", "button click", "panel click", "root click", "button dblclick", "panel dblclick", "root dblclick"}},
+	}
+	for _, step := range steps {
+		log = nil
+		d.Dispatch(step.event)
+		if !reflect.DeepEqual(log, step.expected) {
+			t.Errorf("%s: expected %v, got %v", step.name, step.expected, log)
+		}
+		rebuild()
+	}
+}
+
+func TestEventDispatcherRemapsByID(t *testing.T) {
+	var clicks []string
+	build := func(order ...string) *StyledNode {
+		root := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 10, Height: 2}}, nil)
+		for i, id := range order {
+			child := NewStyledNode(&layout.Node{Rect: layout.Rect{Y: float64(i), Width: 10, Height: 1}}, nil)
+			child.ID = id
+			child.On(EventClick, func(e *Event) { clicks = append(clicks, id) })
+			root.AddChild(child)
+		}
+		return root
+	}
+
+	d := NewEventDispatcher(build("a", "b"))
+	d.Dispatch(input.MouseEvent{X: 1, Y: 0, Button: input.MouseLeft, Action: input.MousePress})
+
+	// The pressed node moved to the second row; a release over it clicks it
+	d.SetRoot(build("b", "a"))
+	d.Dispatch(input.MouseEvent{X: 1, Y: 1, Button: input.MouseLeft, Action: input.MouseRelease})
+	if !reflect.DeepEqual(clicks, []string{"a"}) {
+		t.Errorf("Expected a click on a, got %v", clicks)
+	}
+}
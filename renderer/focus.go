@@ -0,0 +1,411 @@
+package renderer
+
+import (
+	"sort"
+
+	"github.com/SCKelemen/cli/input"
+)
+
+// FocusDirection is a direction for spatial keyboard navigation
+type FocusDirection int
+
+const (
+	FocusUp FocusDirection = iota
+	FocusDown
+	FocusLeft
+	FocusRight
+)
+
+// Focused reports whether the node has keyboard focus
+func (n *StyledNode) Focused() bool {
+	return n.focused
+}
+
+// focusStyle returns the style a focused node is drawn with: its
+// FocusStyle, or else an automatic focus ring. Bordered nodes switch to a
+// thick border and borderless nodes are drawn in reverse video, which stay
+// visible without color.
+func focusStyle(style *Style) *Style {
+	if style == nil {
+		return &Style{Reverse: true}
+	}
+	if style.FocusStyle != nil {
+		return style.FocusStyle
+	}
+
+	ring := *style
+	if style.Border != nil {
+		border := *style.Border
+		border.Chars = ThickBorder
+		ring.Border = &border
+	} else {
+		ring.Reverse = !style.Reverse
+	}
+	return &ring
+}
+
+// FocusManager tracks the focused node of a StyledNode tree. Nodes take
+// part when Focusable is set. Tab and Shift+Tab move through them in tab
+// order: nodes with a positive TabIndex first, in ascending order, then
+// nodes with a zero TabIndex in tree order. Nodes with a negative TabIndex
+// are skipped by Tab but can be focused by clicking or with Focus. The
+// arrow keys move focus spatially, to the nearest node on screen in that
+// direction.
+//
+// Key events go to the focused node first and bubble up to the root as
+// EventKeyDown. Navigation only happens for keys no listener prevented.
+type FocusManager struct {
+	root    *StyledNode
+	focused *StyledNode
+}
+
+// NewFocusManager creates a focus manager for the tree at root
+func NewFocusManager(root *StyledNode) *FocusManager {
+	return &FocusManager{root: root}
+}
+
+// SetRoot sets the tree focus moves within. If the focused node is not in
+// the new tree, focus moves silently to the node with the same ID, so
+// rebuilt trees keep their focus, or is dropped with EventBlur.
+func (f *FocusManager) SetRoot(root *StyledNode) {
+	f.root = root
+	if f.focused == nil || findPath(root, f.focused) != nil {
+		return
+	}
+
+	old := f.focused
+	if old.ID != "" {
+		if node := findByID(root, old.ID); node != nil && node.Focusable {
+			old.focused = false
+			node.focused = true
+			f.focused = node
+			return
+		}
+	}
+	old.focused = false
+	f.focused = nil
+	dispatchEvent([]*StyledNode{old}, &Event{Type: EventBlur})
+}
+
+// Focused returns the focused node, or nil
+func (f *FocusManager) Focused() *StyledNode {
+	return f.focused
+}
+
+// FocusPath returns the nodes from the root down to the focused node, or
+// nil when nothing is focused
+func (f *FocusManager) FocusPath() []*StyledNode {
+	return findPath(f.root, f.focused)
+}
+
+// Focus moves focus to node, dispatching EventBlur to the node losing
+// focus and EventFocus to node. It reports false if node is not a
+// focusable node of the tree.
+func (f *FocusManager) Focus(node *StyledNode) bool {
+	if node == nil || !node.Focusable {
+		return false
+	}
+	path := findPath(f.root, node)
+	if path == nil {
+		return false
+	}
+	if node == f.focused {
+		return true
+	}
+
+	old := f.focused
+	if old != nil {
+		old.focused = false
+		if oldPath := findPath(f.root, old); oldPath != nil {
+			dispatchEvent(oldPath, &Event{Type: EventBlur, Related: node})
+		}
+	}
+	node.focused = true
+	f.focused = node
+	dispatchEvent(path, &Event{Type: EventFocus, Related: old})
+	return true
+}
+
+// Blur removes focus, dispatching EventBlur to the focused node
+func (f *FocusManager) Blur() {
+	old := f.focused
+	if old == nil {
+		return
+	}
+	old.focused = false
+	f.focused = nil
+	if path := findPath(f.root, old); path != nil {
+		dispatchEvent(path, &Event{Type: EventBlur})
+	}
+}
+
+// focusPath focuses the deepest focusable node of a hit-tested path
+func (f *FocusManager) focusPath(path []*StyledNode) {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].Focusable {
+			f.Focus(path[i])
+			return
+		}
+	}
+}
+
+// Next moves focus to the next node in tab order, wrapping around. It
+// reports whether focus moved.
+func (f *FocusManager) Next() bool {
+	return f.step(1)
+}
+
+// Previous moves focus to the previous node in tab order, wrapping around
+func (f *FocusManager) Previous() bool {
+	return f.step(-1)
+}
+
+func (f *FocusManager) step(delta int) bool {
+	order := f.tabOrder()
+	if len(order) == 0 {
+		return false
+	}
+
+	current := -1
+	for i, node := range order {
+		if node == f.focused {
+			current = i
+			break
+		}
+	}
+
+	var next int
+	switch {
+	case current >= 0:
+		next = (current + delta + len(order)) % len(order)
+	case delta > 0:
+		next = 0
+	default:
+		next = len(order) - 1
+	}
+	if order[next] == f.focused {
+		return false
+	}
+	return f.Focus(order[next])
+}
+
+// tabOrder returns the focusable nodes Tab visits, in order
+func (f *FocusManager) tabOrder() []*StyledNode {
+	var order []*StyledNode
+	for _, placed := range focusables(f.root) {
+		if placed.node.TabIndex >= 0 {
+			order = append(order, placed.node)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := order[i].TabIndex, order[j].TabIndex
+		if a == 0 || b == 0 {
+			return a != 0 && b == 0
+		}
+		return a < b
+	})
+	return order
+}
+
+// Move moves focus to the nearest focusable node on screen in direction
+// dir from the focused one, using the laid-out rects. With nothing focused
+// it focuses the first node in tab order. It reports whether focus moved.
+func (f *FocusManager) Move(dir FocusDirection) bool {
+	nodes := focusables(f.root)
+
+	var from cellRect
+	found := false
+	for _, placed := range nodes {
+		if placed.node == f.focused {
+			from, found = placed.rect, true
+			break
+		}
+	}
+	if !found {
+		return f.Next()
+	}
+
+	var best *StyledNode
+	bestScore := 0
+	for _, placed := range nodes {
+		if placed.node == f.focused {
+			continue
+		}
+		score, ok := spatialScore(from, placed.rect, dir)
+		if ok && (best == nil || score < bestScore) {
+			best, bestScore = placed.node, score
+		}
+	}
+	if best == nil {
+		return false
+	}
+	return f.Focus(best)
+}
+
+// spatialScore rates how close to rect from candidate is in direction dir,
+// lower being closer. The distance across the direction counts double so
+// that nodes in line are preferred to nearer diagonal ones. Ok is false
+// for candidates not in that direction.
+func spatialScore(from, candidate cellRect, dir FocusDirection) (score int, ok bool) {
+	var gap, offset int
+	switch dir {
+	case FocusUp:
+		if candidate.y+candidate.h/2 >= from.y+from.h/2 || candidate.y >= from.y {
+			return 0, false
+		}
+		gap = from.y - (candidate.y + candidate.h)
+		offset = rangeDistance(from.x, from.w, candidate.x, candidate.w)
+	case FocusDown:
+		if candidate.y+candidate.h/2 <= from.y+from.h/2 || candidate.y+candidate.h <= from.y+from.h {
+			return 0, false
+		}
+		gap = candidate.y - (from.y + from.h)
+		offset = rangeDistance(from.x, from.w, candidate.x, candidate.w)
+	case FocusLeft:
+		if candidate.x+candidate.w/2 >= from.x+from.w/2 || candidate.x >= from.x {
+			return 0, false
+		}
+		gap = from.x - (candidate.x + candidate.w)
+		offset = rangeDistance(from.y, from.h, candidate.y, candidate.h)
+	case FocusRight:
+		if candidate.x+candidate.w/2 <= from.x+from.w/2 || candidate.x+candidate.w <= from.x+from.w {
+			return 0, false
+		}
+		gap = candidate.x - (from.x + from.w)
+		offset = rangeDistance(from.y, from.h, candidate.y, candidate.h)
+	}
+	return max(gap, 0) + 2*offset, true
+}
+
+// rangeDistance returns the gap between two spans, or zero if they overlap
+func rangeDistance(a, aLen, b, bLen int) int {
+	switch {
+	case b >= a+aLen:
+		return b - (a + aLen)
+	case a >= b+bLen:
+		return a - (b + bLen)
+	}
+	return 0
+}
+
+// HandleKey delivers a key press to the focused node as EventKeyDown,
+// bubbling up to the root, or to the root alone when nothing is focused.
+// If no listener prevented it, Tab and Shift+Tab and the arrow keys move
+// focus. It reports whether the key was used, so unhandled keys can be
+// passed on. Key releases are ignored.
+func (f *FocusManager) HandleKey(key input.KeyEvent) bool {
+	if key.Action == input.KeyRelease || f.root == nil {
+		return false
+	}
+
+	e := &Event{Type: EventKeyDown, Key: key, Mod: key.Mod}
+	dispatchEvent(f.keyPath(), e)
+	if e.DefaultPrevented() {
+		return true
+	}
+
+	mod := key.Mod &^ (input.ModCapsLock | input.ModNumLock)
+	switch {
+	case key.Key == input.KeyTab && mod == 0:
+		return f.Next()
+	case key.Key == input.KeyTab && mod == input.ModShift:
+		return f.Previous()
+	case mod != 0:
+		return false
+	case key.Key == input.KeyUp:
+		return f.Move(FocusUp)
+	case key.Key == input.KeyDown:
+		return f.Move(FocusDown)
+	case key.Key == input.KeyLeft:
+		return f.Move(FocusLeft)
+	case key.Key == input.KeyRight:
+		return f.Move(FocusRight)
+	}
+	return false
+}
+
+// HandlePaste dispatches EventPaste with pasted text along the path to the
+// focused node, or to the root when nothing has focus. It returns true if
+// a listener prevented the default.
+func (f *FocusManager) HandlePaste(text string) bool {
+	if f.root == nil {
+		return false
+	}
+	e := &Event{Type: EventPaste, Text: text}
+	dispatchEvent(f.keyPath(), e)
+	return e.DefaultPrevented()
+}
+
+// keyPath returns the path key and paste events are dispatched along
+func (f *FocusManager) keyPath() []*StyledNode {
+	if f.focused != nil {
+		if path := findPath(f.root, f.focused); path != nil {
+			return path
+		}
+	}
+	return []*StyledNode{f.root}
+}
+
+// placedNode is a node with its rect on screen
+type placedNode struct {
+	node *StyledNode
+	rect cellRect
+}
+
+// focusables returns the focusable nodes of the tree in tree order, with
+// their screen rects. Nodes with an empty rect are not drawn and skipped.
+func focusables(root *StyledNode) []placedNode {
+	var nodes []placedNode
+	var walk func(node *StyledNode, offsetX, offsetY int)
+	walk = func(node *StyledNode, offsetX, offsetY int) {
+		if node == nil || node.Node == nil {
+			return
+		}
+		rect := node.Node.Rect
+		originX := int(rect.X) + offsetX
+		originY := int(rect.Y) + offsetY
+		if node.Focusable && rect.Width > 0 && rect.Height > 0 {
+			nodes = append(nodes, placedNode{node, cellRect{x: originX, y: originY, w: int(rect.Width), h: int(rect.Height)}})
+		}
+
+		scrollX, scrollY := scrollOffset(node)
+		for _, child := range node.Children {
+			walk(child, originX-scrollX, originY-scrollY)
+		}
+	}
+	walk(root, 0, 0)
+	return nodes
+}
+
+// findPath returns the nodes from root down to target, or nil if target is
+// not in the tree
+func findPath(root, target *StyledNode) []*StyledNode {
+	if root == nil || target == nil {
+		return nil
+	}
+	if root == target {
+		return []*StyledNode{root}
+	}
+	for _, child := range root.Children {
+		if path := findPath(child, target); path != nil {
+			return append([]*StyledNode{root}, path...)
+		}
+	}
+	return nil
+}
+
+// findByID returns the first node in tree order with the given ID
+func findByID(root *StyledNode, id string) *StyledNode {
+	if root == nil {
+		return nil
+	}
+	if root.ID == id {
+		return root
+	}
+	for _, child := range root.Children {
+		if node := findByID(child, id); node != nil {
+			return node
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package renderer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/SCKelemen/cli/input"
+	"github.com/SCKelemen/layout"
+)
+
+// focusGrid builds a 2x2 grid of focusable 4x1 cells named a b / c d
+func focusGrid() (root *StyledNode, cells map[string]*StyledNode) {
+	root = NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 20, Height: 6}}, nil)
+	cells = map[string]*StyledNode{}
+	for i, name := range []string{"a", "b", "c", "d"} {
+		cell := NewStyledNode(&layout.Node{Rect: layout.Rect{X: float64(i%2) * 6, Y: float64(i/2) * 3, Width: 4, Height: 1}}, nil)
+		cell.Focusable = true
+		cell.ID = name
+		cells[name] = cell
+		root.AddChild(cell)
+	}
+	return root, cells
+}
+
+func press(key input.Key, mod input.Modifier) input.KeyEvent {
+	return input.KeyEvent{Key: key, Mod: mod}
+}
+
+func TestFocusTabOrder(t *testing.T) {
+	root, cells := focusGrid()
+	cells["d"].TabIndex = 1
+	cells["b"].TabIndex = -1
+	f := NewFocusManager(root)
+
+	var order []string
+	for i := 0; i < 4; i++ {
+		f.HandleKey(press(input.KeyTab, 0))
+		order = append(order, f.Focused().ID)
+	}
+	if expected := []string{"d", "a", "c", "d"}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("Expected tab order %v, got %v", expected, order)
+	}
+
+	f.HandleKey(press(input.KeyTab, input.ModShift))
+	if f.Focused() != cells["c"] {
+		t.Errorf("Expected Shift+Tab to go back to c, got %s", f.Focused().ID)
+	}
+	if !f.Focus(cells["b"]) {
+		t.Error("Expected a negative TabIndex node to take focus directly")
+	}
+}
+
+func TestFocusSpatialNavigation(t *testing.T) {
+	root, cells := focusGrid()
+	f := NewFocusManager(root)
+
+	tests := []struct {
+		key      input.Key
+		expected string
+	}{
+		{input.KeyDown, "a"}, // Nothing focused: first in tab order
+		{input.KeyRight, "b"},
+		{input.KeyDown, "d"},
+		{input.KeyLeft, "c"},
+		{input.KeyUp, "a"},
+		{input.KeyUp, "a"}, // Nothing above
+	}
+	for _, tt := range tests {
+		f.HandleKey(press(tt.key, 0))
+		if got := f.Focused(); got != cells[tt.expected] {
+			t.Errorf("After %v expected %s, got %v", tt.key, tt.expected, got.ID)
+		}
+	}
+}
+
+func TestFocusKeyBubbling(t *testing.T) {
+	root, cells := focusGrid()
+	f := NewFocusManager(root)
+	f.Focus(cells["a"])
+
+	var log []string
+	cells["a"].On(EventKeyDown, func(e *Event) {
+		log = append(log, "a "+e.Key.String())
+		if e.Key.Key == input.KeyEnter {
+			e.PreventDefault()
+		}
+	})
+	root.On(EventKeyDown, func(e *Event) {
+		log = append(log, "root "+e.Key.String())
+		if e.Key.Key == input.KeyRight {
+			e.PreventDefault()
+		}
+	})
+
+	if !f.HandleKey(press(input.KeyEnter, 0)) {
+		t.Error("Expected a prevented key to be handled")
+	}
+	if f.HandleKey(input.KeyEvent{Key: input.KeyRune, Rune: 'x'}) {
+		t.Error("Expected an unprevented key to be unhandled")
+	}
+	if !f.HandleKey(press(input.KeyRight, 0)) || f.Focused() != cells["a"] {
+		t.Error("Expected a prevented arrow key not to move focus")
+	}
+
+	expected := []string{"a enter", "root enter", "a x", "root x", "a right", "root right"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+}
+
+func TestFocusPaste(t *testing.T) {
+	root, cells := focusGrid()
+	f := NewFocusManager(root)
+
+	var pasted []string
+	root.On(EventPaste, func(e *Event) {
+		pasted = append(pasted, e.Target.ID+":"+e.Text)
+	})
+	cells["b"].On(EventPaste, func(e *Event) {
+		e.PreventDefault()
+	})
+
+	if f.HandlePaste("one") {
+		t.Error("Expected a paste nothing prevented to be unhandled")
+	}
+	f.Focus(cells["b"])
+	if !f.HandlePaste("two") {
+		t.Error("Expected the focused node to take the paste")
+	}
+	if expected := []string{":one", "b:two"}; !reflect.DeepEqual(pasted, expected) {
+		t.Errorf("Expected %v, got %v", expected, pasted)
+	}
+}
+
+func TestFocusEvents(t *testing.T) {
+	root, cells := focusGrid()
+	f := NewFocusManager(root)
+
+	var log []string
+	for name, cell := range cells {
+		name := name
+		cell.On(EventFocus, func(e *Event) { log = append(log, "focus "+name) })
+		cell.On(EventBlur, func(e *Event) { log = append(log, "blur "+name+" to "+e.Related.ID) })
+	}
+	root.On(EventFocus, func(e *Event) { log = append(log, "root") })
+
+	f.Focus(cells["a"])
+	f.Focus(cells["b"])
+	expected := []string{"focus a", "blur a to b", "focus b"}
+	if !reflect.DeepEqual(log, expected) {
+		t.Errorf("Expected %v, got %v", expected, log)
+	}
+
+	// A rebuilt tree keeps focus on the node with the same ID
+	rebuilt, rebuiltCells := focusGrid()
+	f.SetRoot(rebuilt)
+	if f.Focused() != rebuiltCells["b"] || !rebuiltCells["b"].Focused() || cells["b"].Focused() {
+		t.Error("Expected focus to move to the rebuilt node with the same ID")
+	}
+
+	// A click focuses the node under the pointer
+	d := NewEventDispatcher(rebuilt)
+	d.SetFocusManager(f)
+	d.Dispatch(input.MouseEvent{X: 1, Y: 3, Button: input.MouseLeft, Action: input.MousePress})
+	if f.Focused() != rebuiltCells["c"] {
+		t.Error("Expected a press to focus the node under the pointer")
+	}
+}
+
+func TestFocusRing(t *testing.T) {
+	root := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 6, Height: 4}}, nil)
+	boxed := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 4, Height: 3}}, (&Style{}).WithBorder(NormalBorder))
+	boxed.Focusable = true
+	plain := NewStyledNode(&layout.Node{Rect: layout.Rect{Y: 3, Width: 2, Height: 1}}, nil)
+	plain.Focusable = true
+	plain.Content = "ok"
+	root.AddChild(boxed)
+	root.AddChild(plain)
+
+	screen := NewScreen(6, 4)
+	screen.SetUnicode(true)
+	screen.SetOutputMode(OutputModePlain)
+	f := NewFocusManager(root)
+	f.Focus(boxed)
+	screen.Render(root)
+	if got := screen.Lines()[0]; got != "┏━━┓" {
+		t.Errorf("Expected a thick focus ring, got %q", got)
+	}
+
+	f.Focus(plain)
+	screen.Render(root)
+	if got := screen.Lines()[0]; got != "┌──┐" {
+		t.Errorf("Expected the blurred border back, got %q", got)
+	}
+	if style := screen.Cells[3][0].Style; style == nil || !style.Reverse {
+		t.Error("Expected a borderless focused node in reverse video")
+	}
+}
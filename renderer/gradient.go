@@ -0,0 +1,110 @@
+package renderer
+
+import (
+	"sort"
+
+	"github.com/SCKelemen/color"
+)
+
+// GradientDirection defines the axis a gradient runs along
+type GradientDirection int
+
+const (
+	GradientHorizontal GradientDirection = iota // Left to right
+	GradientVertical                            // Top to bottom
+	GradientDiagonal                            // Top-left to bottom-right
+)
+
+// Gradient defines a multi-stop color gradient
+type Gradient struct {
+	Stops     []color.GradientStop
+	Direction GradientDirection
+	Space     color.GradientSpace // Interpolation space (OKLCH recommended)
+}
+
+// NewGradient creates a horizontal OKLCH gradient between two colors
+func NewGradient(start, end color.Color) *Gradient {
+	return &Gradient{
+		Stops: []color.GradientStop{
+			{Color: start, Position: 0},
+			{Color: end, Position: 1},
+		},
+		Direction: GradientHorizontal,
+		Space:     color.GradientOKLCH,
+	}
+}
+
+// WithDirection sets the gradient direction
+func (g *Gradient) WithDirection(direction GradientDirection) *Gradient {
+	g.Direction = direction
+	return g
+}
+
+// WithStop adds a color stop at the given position (0.0 to 1.0)
+func (g *Gradient) WithStop(c color.Color, position float64) *Gradient {
+	g.Stops = append(g.Stops, color.GradientStop{Color: c, Position: position})
+	return g
+}
+
+// At returns the gradient color at position t (0.0 to 1.0)
+func (g *Gradient) At(t float64) color.Color {
+	if g == nil || len(g.Stops) == 0 {
+		return nil
+	}
+	if len(g.Stops) == 1 {
+		return g.Stops[0].Color
+	}
+
+	stops := make([]color.GradientStop, len(g.Stops))
+	copy(stops, g.Stops)
+	sort.SliceStable(stops, func(i, j int) bool {
+		return stops[i].Position < stops[j].Position
+	})
+
+	if t <= stops[0].Position {
+		return stops[0].Color
+	}
+	last := stops[len(stops)-1]
+	if t >= last.Position {
+		return last.Color
+	}
+
+	for i := 0; i < len(stops)-1; i++ {
+		start, end := stops[i], stops[i+1]
+		if t < start.Position || t > end.Position {
+			continue
+		}
+		if t == end.Position {
+			return end.Color
+		}
+		if end.Position == start.Position {
+			return start.Color
+		}
+		local := (t - start.Position) / (end.Position - start.Position)
+		return color.MixInSpace(start.Color, end.Color, local, g.Space)
+	}
+
+	return last.Color
+}
+
+// position returns the gradient position of the cell at (col, row) inside
+// a span of w by h cells
+func (g *Gradient) position(col, row, w, h int) float64 {
+	switch g.Direction {
+	case GradientVertical:
+		if h <= 1 {
+			return 0
+		}
+		return float64(row) / float64(h-1)
+	case GradientDiagonal:
+		if w+h <= 2 {
+			return 0
+		}
+		return float64(col+row) / float64(w-1+h-1)
+	default:
+		if w <= 1 {
+			return 0
+		}
+		return float64(col) / float64(w-1)
+	}
+}
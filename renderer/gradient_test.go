@@ -0,0 +1,92 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+func TestGradientAtEndpoints(t *testing.T) {
+	red, _ := color.ParseColor("#FF0000")
+	blue, _ := color.ParseColor("#0000FF")
+	g := NewGradient(red, blue)
+
+	if got := color.RGBToHex(g.At(0)); got != "#ff0000" {
+		t.Errorf("Expected start color #ff0000, got %s", got)
+	}
+	if got := color.RGBToHex(g.At(1)); got != "#0000ff" {
+		t.Errorf("Expected end color #0000ff, got %s", got)
+	}
+}
+
+func TestGradientAtMultiStop(t *testing.T) {
+	red, _ := color.ParseColor("#FF0000")
+	green, _ := color.ParseColor("#00FF00")
+	blue, _ := color.ParseColor("#0000FF")
+	g := NewGradient(red, blue).WithStop(green, 0.5)
+
+	if got := color.RGBToHex(g.At(0.5)); got != "#00ff00" {
+		t.Errorf("Expected middle stop #00ff00, got %s", got)
+	}
+}
+
+func TestGradientPosition(t *testing.T) {
+	g := &Gradient{Direction: GradientHorizontal}
+	if p := g.position(9, 0, 10, 1); p != 1 {
+		t.Errorf("Expected horizontal position 1, got %f", p)
+	}
+
+	g.Direction = GradientDiagonal
+	if p := g.position(4, 2, 5, 3); p != 1 {
+		t.Errorf("Expected diagonal position 1 at bottom-right, got %f", p)
+	}
+	if p := g.position(0, 0, 5, 3); p != 0 {
+		t.Errorf("Expected diagonal position 0 at top-left, got %f", p)
+	}
+}
+
+func TestRenderForegroundGradient(t *testing.T) {
+	s := NewScreen(10, 1)
+	s.SetColorMode(ColorModeTrueColor)
+
+	red, _ := color.ParseColor("#FF0000")
+	blue, _ := color.ParseColor("#0000FF")
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 1},
+	}
+	style := NewStyle().WithForegroundGradient(NewGradient(red, blue))
+	styledNode := NewStyledNode(node, style)
+	styledNode.Content = "Hello"
+
+	s.Render(styledNode)
+
+	first := color.RGBToHex(*s.Cells[0][0].Style.Foreground)
+	last := color.RGBToHex(*s.Cells[0][4].Style.Foreground)
+	if first != "#ff0000" {
+		t.Errorf("Expected first glyph #ff0000, got %s", first)
+	}
+	if last != "#0000ff" {
+		t.Errorf("Expected last glyph #0000ff, got %s", last)
+	}
+}
+
+func TestRenderForegroundGradientCollapses(t *testing.T) {
+	s := NewScreen(10, 1)
+	s.SetColorMode(ColorMode16)
+
+	red, _ := color.ParseColor("#FF0000")
+	blue, _ := color.ParseColor("#0000FF")
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 1},
+	}
+	style := NewStyle().WithForegroundGradient(NewGradient(red, blue))
+	styledNode := NewStyledNode(node, style)
+	styledNode.Content = "Hello"
+
+	s.Render(styledNode)
+
+	if s.Cells[0][0].Style != s.Cells[0][4].Style {
+		t.Error("Expected a single shared style in 16-color mode")
+	}
+}
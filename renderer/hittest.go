@@ -0,0 +1,68 @@
+package renderer
+
+// HitTest returns the deepest node drawn at the cell x, y, or nil if no
+// node of root's tree covers the point. It follows the same geometry as
+// rendering: child rects are relative to their parent and shifted by its
+// scroll offset, nodes whose Overflow is not visible clip their children,
+// and children are tested from the top of the stacking order down.
+func HitTest(root *StyledNode, x, y int) *StyledNode {
+	path := HitPath(root, x, y)
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// HitPath returns the nodes from root down to the node HitTest finds at
+// x, y, or nil if none is hit. Event dispatch uses the path for its capture
+// and bubble phases.
+func HitPath(root *StyledNode, x, y int) []*StyledNode {
+	return hitPath(root, x, y, 0, 0, nil)
+}
+
+func hitPath(node *StyledNode, x, y, offsetX, offsetY int, clip *clipRect) []*StyledNode {
+	if node == nil || node.Node == nil || !clip.contains(x, y) {
+		return nil
+	}
+
+	rect := node.Node.Rect
+	originX := int(rect.X) + offsetX
+	originY := int(rect.Y) + offsetY
+	w, h := int(rect.Width), int(rect.Height)
+
+	// Children may overflow a visible box, so they are tested even when
+	// the point is outside this node
+	childClip := clip
+	if cx, cy, cw, ch, ok := clipBox(node, originX, originY, w, h); ok {
+		childClip = clip.intersect(cx, cy, cw, ch)
+	}
+	scrollX, scrollY := scrollOffset(node)
+
+	children := paintOrder(node.Children)
+	for i := len(children) - 1; i >= 0; i-- {
+		if path := hitPath(children[i], x, y, originX-scrollX, originY-scrollY, childClip); path != nil {
+			return append([]*StyledNode{node}, path...)
+		}
+	}
+
+	if x < originX || y < originY || x >= originX+w || y >= originY+h {
+		return nil
+	}
+	return []*StyledNode{node}
+}
+
+// pathOrigins returns the screen position of each node's laid-out rect
+// along a path from the root
+func pathOrigins(path []*StyledNode) [][2]int {
+	origins := make([][2]int, len(path))
+	offsetX, offsetY := 0, 0
+	for i, node := range path {
+		originX := int(node.Node.Rect.X) + offsetX
+		originY := int(node.Node.Rect.Y) + offsetY
+		origins[i] = [2]int{originX, originY}
+
+		scrollX, scrollY := scrollOffset(node)
+		offsetX, offsetY = originX-scrollX, originY-scrollY
+	}
+	return origins
+}
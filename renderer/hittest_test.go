@@ -0,0 +1,113 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/layout"
+)
+
+func TestHitTest(t *testing.T) {
+	root := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 20, Height: 10}}, nil)
+	panel := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 2, Y: 2, Width: 10, Height: 5}}, nil)
+	button := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 1, Y: 1, Width: 4, Height: 1}}, nil)
+	overlay := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 8, Y: 2, Width: 6, Height: 2}}, nil)
+	panel.AddChild(button)
+	root.AddChild(panel)
+	root.AddChild(overlay)
+
+	tests := []struct {
+		name     string
+		x, y     int
+		expected *StyledNode
+	}{
+		{"Root", 0, 0, root},
+		{"Panel", 2, 2, panel},
+		{"NestedOffset", 3, 3, button},
+		{"ButtonEnd", 6, 3, button},
+		{"PastButton", 7, 3, panel},
+		{"LaterSiblingOnTop", 9, 3, overlay},
+		{"Outside", 20, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HitTest(root, tt.x, tt.y); got != tt.expected {
+				t.Errorf("Expected %p, got %p", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHitTestClipScrollAndZIndex(t *testing.T) {
+	root := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 20, Height: 10}}, nil)
+
+	// A bordered viewport of 8x4 cells scrolled down by 2 rows
+	viewport := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 1, Y: 1, Width: 8, Height: 4}},
+		(&Style{Overflow: OverflowScroll}).WithBorder(NormalBorder))
+	viewport.ScrollY = 2
+	row := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 1, Y: 3, Width: 6, Height: 1}}, nil)
+	wide := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 1, Y: 4, Width: 15, Height: 1}}, nil)
+	viewport.AddChild(row)
+	viewport.AddChild(wide)
+	root.AddChild(viewport)
+
+	// Earlier in the tree but stacked above the later sibling
+	popup := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 12, Y: 6, Width: 4, Height: 2}, Style: layout.Style{ZIndex: 1}}, nil)
+	below := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 10, Y: 6, Width: 8, Height: 3}}, nil)
+	root.AddChild(popup)
+	root.AddChild(below)
+
+	tests := []struct {
+		name     string
+		x, y     int
+		expected *StyledNode
+	}{
+		{"ScrolledChild", 3, 2, row},
+		{"ScrolledSecondChild", 3, 3, wide},
+		{"ClippedByBorder", 3, 4, viewport},
+		{"ClippedOverflow", 12, 3, root},
+		{"ZIndexAbove", 13, 6, popup},
+		{"ZIndexBeside", 11, 6, below},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HitTest(root, tt.x, tt.y); got != tt.expected {
+				t.Errorf("Expected %p, got %p", tt.expected, got)
+			}
+		})
+	}
+
+	if path := HitPath(root, 3, 2); len(path) != 3 || path[0] != root || path[1] != viewport || path[2] != row {
+		t.Errorf("Expected the path root, viewport, row, got %v", path)
+	}
+}
+
+func TestRenderOverflow(t *testing.T) {
+	root := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 10, Height: 4}}, nil)
+	box := NewStyledNode(&layout.Node{Rect: layout.Rect{X: 1, Y: 1, Width: 3, Height: 2}}, &Style{Overflow: OverflowHidden})
+	child := NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 8, Height: 3}}, nil)
+	child.Content = "abcdefgh\nijklmnop\nqrstuvwx"
+	box.AddChild(child)
+	root.AddChild(box)
+
+	screen := NewScreen(10, 4)
+	screen.SetOutputMode(OutputModePlain)
+	screen.Render(root)
+	expected := []string{"", " abc", " ijk", ""}
+	for i, line := range screen.Lines() {
+		if line != expected[i] {
+			t.Errorf("Hidden row %d: expected %q, got %q", i, expected[i], line)
+		}
+	}
+
+	box.Style.Overflow = OverflowScroll
+	box.ScrollX, box.ScrollY = 2, 1
+	screen.Render(root)
+	expected = []string{"", " klm", " stu", ""}
+	for i, line := range screen.Lines() {
+		if line != expected[i] {
+			t.Errorf("Scrolled row %d: expected %q, got %q", i, expected[i], line)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package renderer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// InlineRenderer draws a live region below the shell prompt instead of
+// taking over the alternate screen. Each frame rewrites the region in place
+// using only relative cursor movement, so it works wherever the prompt
+// happens to be. The region may grow or shrink between frames; when it
+// grows at the bottom of the terminal the rows above scroll into
+// scrollback. Rows committed with Commit are printed permanently above the
+// region, which is redrawn underneath them. Close leaves the final frame on
+// screen.
+//
+// In plain output mode, used by default when out is a file or pipe that is
+// not a terminal, nothing is redrawn: committed rows are printed as plain
+// text as they arrive and only the final frame is printed, on Close.
+type InlineRenderer struct {
+	out          io.Writer
+	renderer     *ANSIRenderer
+	width        int
+	lines        []string // Rows of the live region currently on screen
+	widths       []int    // Display width of each row, for reflow on resize
+	stale        bool     // Rows on screen no longer match lines
+	synchronized bool
+	output       OutputMode
+	started      bool
+	closed       bool
+}
+
+// NewInlineRenderer creates an inline renderer writing to out for a
+// terminal width columns wide
+func NewInlineRenderer(out io.Writer, width int) *InlineRenderer {
+	return &InlineRenderer{
+		out:      out,
+		renderer: NewANSIRenderer(),
+		width:    width,
+		output:   outputModeFor(out),
+	}
+}
+
+// SetColorMode sets the color mode used for committed styled text and nodes
+func (r *InlineRenderer) SetColorMode(mode ColorMode) {
+	r.renderer = NewANSIRendererWithMode(mode)
+}
+
+// SetSynchronized sets whether frames are wrapped in synchronized updates
+// (DEC mode 2026)
+func (r *InlineRenderer) SetSynchronized(enabled bool) {
+	r.synchronized = enabled
+}
+
+// SetOutputMode sets whether frames are drawn in place or, in plain mode,
+// only the final frame is printed as plain text
+func (r *InlineRenderer) SetOutputMode(mode OutputMode) {
+	r.output = mode
+}
+
+// SetWidth updates the terminal width after a resize. The terminal may have
+// rewrapped rows wider than the new width, so the next frame erases the
+// region using the rewrapped height and redraws it in full.
+func (r *InlineRenderer) SetWidth(width int) {
+	if width == r.width || width <= 0 {
+		return
+	}
+	if width < r.width && len(r.lines) > 0 {
+		// Count the rows the old frame occupies after wrapping at the new
+		// width; the cursor is on the last of them
+		rows := 0
+		for _, w := range r.widths {
+			rows += max(1, (w+width-1)/width)
+		}
+		r.lines = make([]string, rows)
+		r.widths = make([]int, rows)
+	}
+	r.stale = true
+	r.width = width
+}
+
+// Height returns the number of rows in the live region
+func (r *InlineRenderer) Height() int {
+	return len(r.lines)
+}
+
+// Render draws a screen as the live region, replacing the previous frame
+func (r *InlineRenderer) Render(screen *Screen) error {
+	lines := screen.Lines()
+	widths := make([]int, len(lines))
+	for i := range widths {
+		widths[i] = screen.Width
+	}
+	return r.RenderLines(lines, widths)
+}
+
+// RenderLines draws pre-rendered rows as the live region. Widths gives the
+// display width of each row, used to account for rewrapping on resize.
+func (r *InlineRenderer) RenderLines(lines []string, widths []int) error {
+	if r.closed {
+		return fmt.Errorf("renderer: inline renderer is closed")
+	}
+	if r.output == OutputModePlain {
+		// Keep the frame for Close; intermediate frames are never printed
+		r.lines = append(r.lines[:0], lines...)
+		return nil
+	}
+
+	var buf strings.Builder
+	r.begin(&buf)
+	r.moveToTop(&buf)
+	r.writeLines(&buf, lines)
+
+	r.lines = append(r.lines[:0], lines...)
+	r.widths = append(r.widths[:0], widths...)
+	r.stale = false
+	return r.end(&buf)
+}
+
+// Commit prints rows permanently above the live region, like Ink's
+// <Static>. The rows move into scrollback as further output arrives, and
+// the live region is redrawn below them.
+func (r *InlineRenderer) Commit(lines ...string) error {
+	if r.closed {
+		return fmt.Errorf("renderer: inline renderer is closed")
+	}
+	if r.output == OutputModePlain {
+		return r.writePlain(lines)
+	}
+
+	var buf strings.Builder
+	r.begin(&buf)
+	r.moveToTop(&buf)
+	for _, line := range lines {
+		buf.WriteString("\x1b[2K")
+		buf.WriteString(line)
+		buf.WriteString("\r\n")
+	}
+
+	// Redraw the live region in full from here, erasing whatever is left
+	// of the old region below it
+	live := r.lines
+	r.lines = make([]string, max(len(live)-len(lines), 0))
+	r.stale = true
+	r.writeLines(&buf, live)
+
+	r.lines = live
+	r.stale = false
+	return r.end(&buf)
+}
+
+// CommitStyled commits text above the live region, one row per line, in
+// the given style
+func (r *InlineRenderer) CommitStyled(text string, style *Style) error {
+	lines := strings.Split(text, "\n")
+	if r.output == OutputModePlain {
+		return r.Commit(lines...)
+	}
+	if codes := r.renderer.RenderStyle(style); codes != "" {
+		for i, line := range lines {
+			lines[i] = codes + line + r.renderer.Reset()
+		}
+	}
+	return r.Commit(lines...)
+}
+
+// CommitScreen commits every row of a rendered screen above the live region
+func (r *InlineRenderer) CommitScreen(screen *Screen) error {
+	return r.Commit(screen.Lines()...)
+}
+
+// CommitNode renders a laid-out node and commits it above the live region
+func (r *InlineRenderer) CommitNode(node *StyledNode) error {
+	if node == nil || node.Node == nil {
+		return nil
+	}
+	rect := node.Node.Rect
+	screen := NewScreen(int(rect.X+rect.Width), int(rect.Y+rect.Height))
+	screen.SetColorMode(r.renderer.ColorMode)
+	screen.SetOutputMode(r.output)
+	screen.Render(node)
+	return r.CommitScreen(screen)
+}
+
+// Close leaves the final frame in scrollback, moves the cursor to the line
+// below it and shows the cursor again
+func (r *InlineRenderer) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	if r.output == OutputModePlain {
+		return r.writePlain(r.lines)
+	}
+
+	var buf strings.Builder
+	if len(r.lines) > 0 {
+		buf.WriteString("\r\n")
+	}
+	buf.WriteString(r.renderer.ShowCursor())
+
+	_, err := io.WriteString(r.out, buf.String())
+	return err
+}
+
+// writePlain prints rows as plain text, one per line
+func (r *InlineRenderer) writePlain(lines []string) error {
+	var buf strings.Builder
+	for _, line := range lines {
+		buf.WriteString(plainLine(line))
+		buf.WriteString("\n")
+	}
+	_, err := io.WriteString(r.out, buf.String())
+	return err
+}
+
+// begin starts a frame, hiding the cursor before the first one
+func (r *InlineRenderer) begin(buf *strings.Builder) {
+	if r.synchronized {
+		buf.WriteString(syncBegin)
+	}
+	if !r.started {
+		buf.WriteString(r.renderer.HideCursor())
+		r.started = true
+	}
+}
+
+// end finishes a frame and writes it out
+func (r *InlineRenderer) end(buf *strings.Builder) error {
+	if r.synchronized {
+		buf.WriteString(syncEnd)
+	}
+	_, err := io.WriteString(r.out, buf.String())
+	return err
+}
+
+// moveToTop moves the cursor from the last row of the region to the start
+// of its first row
+func (r *InlineRenderer) moveToTop(buf *strings.Builder) {
+	buf.WriteString("\r")
+	if up := len(r.lines) - 1; up > 0 {
+		fmt.Fprintf(buf, "\x1b[%dA", up)
+	}
+}
+
+// writeLines rewrites the region from its first row, leaving the cursor on
+// the last row. Unchanged rows are skipped, and rows left over from a
+// taller previous frame are erased.
+func (r *InlineRenderer) writeLines(buf *strings.Builder, lines []string) {
+	for i, line := range lines {
+		if i > 0 {
+			buf.WriteString("\r\n")
+		}
+		if !r.stale && i < len(r.lines) && r.lines[i] == line {
+			continue
+		}
+		// Erase the whole row first; erasing after a full-width row would
+		// clear its last cell
+		buf.WriteString("\x1b[2K")
+		buf.WriteString(line)
+	}
+
+	if extra := len(r.lines) - len(lines); extra > 0 {
+		if len(lines) == 0 {
+			// The cursor is on the first old row; erase it and everything below
+			buf.WriteString("\x1b[J")
+			return
+		}
+		for i := 0; i < extra; i++ {
+			buf.WriteString("\x1b[1B\x1b[2K")
+		}
+		fmt.Fprintf(buf, "\x1b[%dA", extra)
+	}
+}
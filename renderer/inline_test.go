@@ -0,0 +1,158 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineRendererRewritesInPlace(t *testing.T) {
+	var out strings.Builder
+	r := NewInlineRenderer(&out, 10)
+
+	steps := []struct {
+		name     string
+		lines    []string
+		expected string
+	}{
+		{"FirstFrame", []string{"aa", "bb"}, "\x1b[?25l\r\x1b[2Kaa\r\n\x1b[2Kbb"},
+		{"ChangedRowOnly", []string{"aa", "cc"}, "\r\x1b[1A\r\n\x1b[2Kcc"},
+		{"Grow", []string{"aa", "cc", "dd"}, "\r\x1b[1A\r\n\r\n\x1b[2Kdd"},
+		{"Shrink", []string{"ee"}, "\r\x1b[2A\x1b[2Kee\x1b[1B\x1b[2K\x1b[1B\x1b[2K\x1b[2A"},
+	}
+
+	for _, step := range steps {
+		out.Reset()
+		if err := r.RenderLines(step.lines, []int{2, 2, 2}[:len(step.lines)]); err != nil {
+			t.Fatalf("%s: render failed: %v", step.name, err)
+		}
+		if got := out.String(); got != step.expected {
+			t.Errorf("%s: expected %q, got %q", step.name, step.expected, got)
+		}
+	}
+
+	if r.Height() != 1 {
+		t.Errorf("Expected live region height 1, got %d", r.Height())
+	}
+
+	out.Reset()
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if got := out.String(); got != "\r\n\x1b[?25h" {
+		t.Errorf("Expected final frame to be left in place, got %q", got)
+	}
+	if err := r.RenderLines([]string{"x"}, []int{1}); err == nil {
+		t.Error("Expected rendering after Close to fail")
+	}
+}
+
+func TestInlineRendererSynchronized(t *testing.T) {
+	var out strings.Builder
+	r := NewInlineRenderer(&out, 10)
+	r.SetSynchronized(true)
+	r.RenderLines([]string{"a"}, []int{1})
+
+	got := out.String()
+	if !strings.HasPrefix(got, "\x1b[?2026h") || !strings.HasSuffix(got, "\x1b[?2026l") {
+		t.Errorf("Expected frame wrapped in synchronized update, got %q", got)
+	}
+}
+
+func TestInlineRendererResize(t *testing.T) {
+	var out strings.Builder
+	r := NewInlineRenderer(&out, 10)
+	r.RenderLines([]string{"0123456789", "ab"}, []int{10, 2})
+
+	// The first row rewraps onto two rows at width 5
+	r.SetWidth(5)
+	if r.Height() != 3 {
+		t.Fatalf("Expected rewrapped height 3, got %d", r.Height())
+	}
+
+	out.Reset()
+	r.RenderLines([]string{"ab"}, []int{2})
+	expected := "\r\x1b[2A\x1b[2Kab\x1b[1B\x1b[2K\x1b[1B\x1b[2K\x1b[2A"
+	if got := out.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestScreenLines(t *testing.T) {
+	s := NewScreen(3, 2)
+	s.SetColorMode(ColorMode16)
+	s.SetOutputMode(OutputModeANSI)
+	bold := NewStyle().WithBold(true)
+	s.SetCell(0, 0, "a", bold)
+	s.SetCell(0, 1, "b", nil)
+
+	lines := s.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+	if expected := "\x1b[0m\x1b[1ma\x1b[0m  "; lines[0] != expected {
+		t.Errorf("Expected %q, got %q", expected, lines[0])
+	}
+	if lines[1] != "b  " {
+		t.Errorf("Expected unstyled row %q, got %q", "b  ", lines[1])
+	}
+}
+
+func TestInlineRendererCommit(t *testing.T) {
+	var out strings.Builder
+	r := NewInlineRenderer(&out, 20)
+
+	// Without a live region, committed rows leave the cursor on a fresh row
+	r.Commit("start")
+	if got := out.String(); got != "\x1b[?25l\r\x1b[2Kstart\r\n" {
+		t.Errorf("Expected committed row, got %q", got)
+	}
+
+	r.RenderLines([]string{"spin |", "50%"}, []int{6, 3})
+
+	out.Reset()
+	r.Commit("compiled pkg/foo")
+	expected := "\r\x1b[1A\x1b[2Kcompiled pkg/foo\r\n\x1b[2Kspin |\r\n\x1b[2K50%"
+	if got := out.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if r.Height() != 2 {
+		t.Errorf("Expected the live region to keep 2 rows, got %d", r.Height())
+	}
+
+	// Live rows are diffed against the redrawn region afterwards
+	out.Reset()
+	r.RenderLines([]string{"spin /", "50%"}, []int{6, 3})
+	if got := out.String(); got != "\r\x1b[1A\x1b[2Kspin /\r\n" {
+		t.Errorf("Expected only the changed row, got %q", got)
+	}
+}
+
+func TestInlineRendererCommitStyled(t *testing.T) {
+	var out strings.Builder
+	r := NewInlineRenderer(&out, 20)
+	r.SetColorMode(ColorMode16)
+
+	r.CommitStyled("ok\ndone", NewStyle().WithBold(true))
+	expected := "\x1b[?25l\r\x1b[2K\x1b[1mok\x1b[0m\r\n\x1b[2K\x1b[1mdone\x1b[0m\r\n"
+	if got := out.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestInlineRendererPlainOutput(t *testing.T) {
+	var out strings.Builder
+	r := NewInlineRenderer(&out, 10)
+	r.SetOutputMode(OutputModePlain)
+
+	r.RenderLines([]string{"\x1b[1mframe 1\x1b[0m  "}, []int{10})
+	r.CommitStyled("done", NewStyle().WithBold(true))
+	r.RenderLines([]string{"frame 2   ", "status"}, []int{10, 10})
+	if got := out.String(); got != "done\n" {
+		t.Errorf("Expected only committed rows before Close, got %q", got)
+	}
+
+	r.Close()
+	if expected := "done\nframe 2\nstatus\n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
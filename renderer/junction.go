@@ -0,0 +1,332 @@
+package renderer
+
+// lineWeight is the stroke of one arm of a box-drawing glyph
+type lineWeight uint8
+
+const (
+	lineNone lineWeight = iota
+	lineLight
+	lineHeavy
+	lineDouble
+)
+
+// lineArms records the line segments meeting at a cell, in the order
+// up, right, down, left
+type lineArms [4]lineWeight
+
+const (
+	armUp = iota
+	armRight
+	armDown
+	armLeft
+)
+
+// junction is a cell in the collapsed-border layer
+type junction struct {
+	arms    lineArms
+	line    rune // Character for a straight run, so dashed lines stay dashed
+	rounded bool // Prefer rounded corners when the cell resolves to a corner
+	ascii   bool // Resolve to ASCII characters
+}
+
+// boxGlyphs maps line arms to box-drawing characters
+var boxGlyphs = map[lineArms]rune{
+	// Light and heavy
+	{0, 1, 0, 1}: '─', {0, 2, 0, 2}: '━',
+	{1, 0, 1, 0}: '│', {2, 0, 2, 0}: '┃',
+	{0, 1, 1, 0}: '┌', {0, 2, 1, 0}: '┍', {0, 1, 2, 0}: '┎', {0, 2, 2, 0}: '┏',
+	{0, 0, 1, 1}: '┐', {0, 0, 1, 2}: '┑', {0, 0, 2, 1}: '┒', {0, 0, 2, 2}: '┓',
+	{1, 1, 0, 0}: '└', {1, 2, 0, 0}: '┕', {2, 1, 0, 0}: '┖', {2, 2, 0, 0}: '┗',
+	{1, 0, 0, 1}: '┘', {1, 0, 0, 2}: '┙', {2, 0, 0, 1}: '┚', {2, 0, 0, 2}: '┛',
+	{1, 1, 1, 0}: '├', {1, 2, 1, 0}: '┝', {2, 1, 1, 0}: '┞', {1, 1, 2, 0}: '┟',
+	{2, 1, 2, 0}: '┠', {2, 2, 1, 0}: '┡', {1, 2, 2, 0}: '┢', {2, 2, 2, 0}: '┣',
+	{1, 0, 1, 1}: '┤', {1, 0, 1, 2}: '┥', {2, 0, 1, 1}: '┦', {1, 0, 2, 1}: '┧',
+	{2, 0, 2, 1}: '┨', {2, 0, 1, 2}: '┩', {1, 0, 2, 2}: '┪', {2, 0, 2, 2}: '┫',
+	{0, 1, 1, 1}: '┬', {0, 1, 1, 2}: '┭', {0, 2, 1, 1}: '┮', {0, 2, 1, 2}: '┯',
+	{0, 1, 2, 1}: '┰', {0, 1, 2, 2}: '┱', {0, 2, 2, 1}: '┲', {0, 2, 2, 2}: '┳',
+	{1, 1, 0, 1}: '┴', {1, 1, 0, 2}: '┵', {1, 2, 0, 1}: '┶', {1, 2, 0, 2}: '┷',
+	{2, 1, 0, 1}: '┸', {2, 1, 0, 2}: '┹', {2, 2, 0, 1}: '┺', {2, 2, 0, 2}: '┻',
+	{1, 1, 1, 1}: '┼', {1, 1, 1, 2}: '┽', {1, 2, 1, 1}: '┾', {1, 2, 1, 2}: '┿',
+	{2, 1, 1, 1}: '╀', {1, 1, 2, 1}: '╁', {2, 1, 2, 1}: '╂', {2, 1, 1, 2}: '╃',
+	{2, 2, 1, 1}: '╄', {1, 1, 2, 2}: '╅', {1, 2, 2, 1}: '╆', {2, 2, 1, 2}: '╇',
+	{1, 2, 2, 2}: '╈', {2, 1, 2, 2}: '╉', {2, 2, 2, 1}: '╊', {2, 2, 2, 2}: '╋',
+
+	// Line ends
+	{0, 0, 0, 1}: '╴', {1, 0, 0, 0}: '╵', {0, 1, 0, 0}: '╶', {0, 0, 1, 0}: '╷',
+	{0, 0, 0, 2}: '╸', {2, 0, 0, 0}: '╹', {0, 2, 0, 0}: '╺', {0, 0, 2, 0}: '╻',
+	{0, 2, 0, 1}: '╼', {1, 0, 2, 0}: '╽', {0, 1, 0, 2}: '╾', {2, 0, 1, 0}: '╿',
+
+	// Double, alone and mixed with light
+	{0, 3, 0, 3}: '═', {3, 0, 3, 0}: '║',
+	{0, 3, 1, 0}: '╒', {0, 1, 3, 0}: '╓', {0, 3, 3, 0}: '╔',
+	{0, 0, 1, 3}: '╕', {0, 0, 3, 1}: '╖', {0, 0, 3, 3}: '╗',
+	{1, 3, 0, 0}: '╘', {3, 1, 0, 0}: '╙', {3, 3, 0, 0}: '╚',
+	{1, 0, 0, 3}: '╛', {3, 0, 0, 1}: '╜', {3, 0, 0, 3}: '╝',
+	{1, 3, 1, 0}: '╞', {3, 1, 3, 0}: '╟', {3, 3, 3, 0}: '╠',
+	{1, 0, 1, 3}: '╡', {3, 0, 3, 1}: '╢', {3, 0, 3, 3}: '╣',
+	{0, 3, 1, 3}: '╤', {0, 1, 3, 1}: '╥', {0, 3, 3, 3}: '╦',
+	{1, 3, 0, 3}: '╧', {3, 1, 0, 1}: '╨', {3, 3, 0, 3}: '╩',
+	{1, 3, 1, 3}: '╪', {3, 1, 3, 1}: '╫', {3, 3, 3, 3}: '╬',
+}
+
+// roundedCorners maps light corners to their rounded forms
+var roundedCorners = map[rune]rune{
+	'┌': '╭',
+	'┐': '╮',
+	'└': '╰',
+	'┘': '╯',
+}
+
+// merge combines two sets of arms, keeping the heavier stroke of each arm
+func (a lineArms) merge(b lineArms) lineArms {
+	for i := range a {
+		if b[i] > a[i] {
+			a[i] = b[i]
+		}
+	}
+	return a
+}
+
+// glyph resolves arms to a box-drawing character. Unicode has no glyphs
+// mixing heavy and double strokes, or mixing weights along a double axis,
+// so such combinations are progressively normalized until one exists.
+func (a lineArms) glyph() rune {
+	if r, ok := boxGlyphs[a]; ok {
+		return r
+	}
+
+	// Heavy strokes become light next to double strokes
+	hasDouble := false
+	for _, w := range a {
+		if w == lineDouble {
+			hasDouble = true
+		}
+	}
+	if hasDouble {
+		for i, w := range a {
+			if w == lineHeavy {
+				a[i] = lineLight
+			}
+		}
+		if r, ok := boxGlyphs[a]; ok {
+			return r
+		}
+	}
+
+	// Both arms of an axis take the heavier stroke
+	for _, axis := range [2][2]int{{armUp, armDown}, {armLeft, armRight}} {
+		first, second := a[axis[0]], a[axis[1]]
+		if first != lineNone && second != lineNone && first != second {
+			heavier := max(first, second)
+			a[axis[0]], a[axis[1]] = heavier, heavier
+		}
+	}
+	if r, ok := boxGlyphs[a]; ok {
+		return r
+	}
+
+	// Fall back to light strokes
+	for i, w := range a {
+		if w != lineNone {
+			a[i] = lineLight
+		}
+	}
+	if r, ok := boxGlyphs[a]; ok {
+		return r
+	}
+	return ' '
+}
+
+// asciiGlyph resolves arms to an ASCII character
+func (a lineArms) asciiGlyph() rune {
+	vertical := a[armUp] != lineNone || a[armDown] != lineNone
+	horizontal := a[armLeft] != lineNone || a[armRight] != lineNone
+	switch {
+	case vertical && horizontal:
+		return '+'
+	case vertical:
+		return '|'
+	case horizontal:
+		return '-'
+	default:
+		return ' '
+	}
+}
+
+// joinable reports whether a border is drawn with lines that can be merged
+// at junctions
+func (c BorderChars) joinable() bool {
+	switch c.Horizontal {
+	case '─', '━', '═', '╌', '┈', '-':
+		return true
+	default:
+		return false
+	}
+}
+
+// borderWeight returns the stroke drawn by a set of border characters
+func borderWeight(chars BorderChars) lineWeight {
+	switch chars.Horizontal {
+	case '━':
+		return lineHeavy
+	case '═':
+		return lineDouble
+	default:
+		return lineLight
+	}
+}
+
+// straight reports whether the arms form an unbroken horizontal or vertical line
+func (a lineArms) straight() bool {
+	vertical := a[armUp] != lineNone && a[armUp] == a[armDown]
+	horizontal := a[armLeft] != lineNone && a[armLeft] == a[armRight]
+	return (vertical && a[armLeft] == lineNone && a[armRight] == lineNone) ||
+		(horizontal && a[armUp] == lineNone && a[armDown] == lineNone)
+}
+
+// setJunction adds arms to a cell of the collapsed-border layer and draws
+// the glyph resolved from every line that has met there so far. Line is the
+// character the caller would draw for a straight run, or zero for corners.
+func (s *Screen) setJunction(x, y int, arms lineArms, line rune, rounded, ascii bool, style *Style) {
+	if x < 0 || x >= s.Width || y < 0 || y >= s.Height {
+		return
+	}
+	if s.junctions == nil {
+		s.junctions = make(map[int]*junction)
+	}
+
+	key := y*s.Width + x
+	j, ok := s.junctions[key]
+	if !ok {
+		j = &junction{line: line, rounded: rounded, ascii: ascii}
+		s.junctions[key] = j
+	} else {
+		j.rounded = j.rounded && rounded
+		if j.line != line {
+			j.line = 0
+		}
+	}
+	j.arms = j.arms.merge(arms)
+	s.SetCell(x, y, string(j.glyph()), style)
+}
+
+// glyph resolves the character drawn for every line that has met at the cell
+func (j *junction) glyph() rune {
+	if j.ascii {
+		return j.arms.asciiGlyph()
+	}
+	if j.line != 0 && j.arms.straight() {
+		return j.line
+	}
+	glyph := j.arms.glyph()
+	if j.rounded {
+		if r, ok := roundedCorners[glyph]; ok {
+			glyph = r
+		}
+	}
+	return glyph
+}
+
+// connectJunctions joins lines of the collapsed-border layer that end
+// against another line: a cell gains an arm toward each neighbour whose
+// arm points at it, so a rule stopping at a border or a crossing rule
+// resolves to a junction such as ┬ or ├ instead of merely touching it.
+// Cells no longer showing their glyph, because they were clipped or drawn
+// over, are left alone.
+func (s *Screen) connectJunctions() {
+	type growth struct {
+		key  int
+		arms lineArms
+	}
+	offsets := [4][2]int{armUp: {0, -1}, armRight: {1, 0}, armDown: {0, 1}, armLeft: {-1, 0}}
+
+	var grown []growth
+	for key, j := range s.junctions {
+		x, y := key%s.Width, key/s.Width
+		var arms lineArms
+		added := false
+		for arm, off := range offsets {
+			nx, ny := x+off[0], y+off[1]
+			if j.arms[arm] != lineNone || nx < 0 || nx >= s.Width || ny < 0 || ny >= s.Height {
+				continue
+			}
+			other, ok := s.junctions[ny*s.Width+nx]
+			if !ok {
+				continue
+			}
+			if w := other.arms[(arm+2)%4]; w != lineNone {
+				arms[arm] = w
+				added = true
+			}
+		}
+		if added {
+			grown = append(grown, growth{key, arms})
+		}
+	}
+
+	for _, g := range grown {
+		j := s.junctions[g.key]
+		x, y := g.key%s.Width, g.key/s.Width
+		cell := &s.Cells[y][x]
+		if cell.Content != string(j.glyph()) {
+			continue
+		}
+		j.arms = j.arms.merge(g.arms)
+		cell.Content = string(j.glyph())
+	}
+}
+
+// collapseGrowth returns how far a child's painted box grows so that its
+// border lands on the border of a touching sibling or of the parent. Only
+// the left and top edges grow toward siblings, so each shared line is
+// drawn once by each neighbour and merged in the junction layer.
+func collapseGrowth(parent, child *StyledNode) boxGrowth {
+	var grow boxGrowth
+	if child.Style == nil || child.Style.Border == nil || child.Node == nil {
+		return grow
+	}
+
+	cx := int(child.Node.Rect.X)
+	cy := int(child.Node.Rect.Y)
+	cw := int(child.Node.Rect.Width)
+	ch := int(child.Node.Rect.Height)
+
+	if parent.Style != nil && parent.Style.Border != nil {
+		pw := int(parent.Node.Rect.Width)
+		ph := int(parent.Node.Rect.Height)
+		border := parent.Style.Border
+		if border.Left && cx == 1 {
+			grow.left = 1
+		}
+		if border.Top && cy == 1 {
+			grow.top = 1
+		}
+		if border.Right && cx+cw == pw-1 {
+			grow.right = 1
+		}
+		if border.Bottom && cy+ch == ph-1 {
+			grow.bottom = 1
+		}
+	}
+
+	for _, sibling := range parent.Children {
+		if sibling == child || sibling.Node == nil || sibling.Style == nil || sibling.Style.Border == nil {
+			continue
+		}
+		sx := int(sibling.Node.Rect.X)
+		sy := int(sibling.Node.Rect.Y)
+		sw := int(sibling.Node.Rect.Width)
+		sh := int(sibling.Node.Rect.Height)
+
+		overlapsRows := sy < cy+ch && cy < sy+sh
+		overlapsCols := sx < cx+cw && cx < sx+sw
+		if overlapsRows && sx+sw == cx {
+			grow.left = 1
+		}
+		if overlapsCols && sy+sh == cy {
+			grow.top = 1
+		}
+	}
+
+	return grow
+}
@@ -0,0 +1,84 @@
+package renderer
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// OutputMode selects how rendered output is written
+type OutputMode int
+
+const (
+	// OutputModeANSI writes styles and cursor movement as escape sequences
+	OutputModeANSI OutputMode = iota
+
+	// OutputModePlain writes plain text with no escape sequences and no
+	// trailing spaces, for pipes, files and CI logs. Live regions print only
+	// their final frame.
+	OutputModePlain
+)
+
+// DetectOutputMode returns OutputModePlain when stdout is not a terminal
+func DetectOutputMode() OutputMode {
+	if DetectCapabilities().IsTTY {
+		return OutputModeANSI
+	}
+	return OutputModePlain
+}
+
+// outputModeFor returns the output mode suited to a writer: plain for files
+// and pipes that are not terminals, ANSI for terminals and for writers that
+// are not files, such as buffers
+func outputModeFor(out io.Writer) OutputMode {
+	if f, ok := out.(*os.File); ok && !term.IsTerminal(int(f.Fd())) {
+		return OutputModePlain
+	}
+	return OutputModeANSI
+}
+
+// StripANSI removes escape sequences (CSI, OSC and two-byte escapes) from s
+func StripANSI(s string) string {
+	if !strings.Contains(s, "\x1b") {
+		return s
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x1b' {
+			buf.WriteByte(s[i])
+			continue
+		}
+		if i+1 >= len(s) {
+			break
+		}
+
+		switch s[i+1] {
+		case '[':
+			// CSI: parameters and intermediates up to a final byte in @–~
+			i += 2
+			for i < len(s) && (s[i] < 0x40 || s[i] > 0x7e) {
+				i++
+			}
+		case ']':
+			// OSC: terminated by BEL or ST (ESC \)
+			i += 2
+			for i < len(s) && s[i] != '\a' && !(s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\') {
+				i++
+			}
+			if i < len(s) && s[i] == '\x1b' {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return buf.String()
+}
+
+// plainLine strips escape sequences and trailing spaces from a row
+func plainLine(line string) string {
+	return strings.TrimRight(StripANSI(line), " ")
+}
@@ -0,0 +1,52 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/layout"
+)
+
+func TestStripANSI(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"\x1b[1;31mred\x1b[0m", "red"},
+		{"\x1b[?25lhidden\x1b[?2026h", "hidden"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\a", "link"},
+		{"a\x1b7b\x1b8c", "abc"},
+		{"cut\x1b[3", "cut"},
+	}
+
+	for _, tt := range tests {
+		if got := StripANSI(tt.input); got != tt.expected {
+			t.Errorf("StripANSI(%q): expected %q, got %q", tt.input, tt.expected, got)
+		}
+	}
+}
+
+func TestScreenPlainOutput(t *testing.T) {
+	s := NewScreen(6, 3)
+	s.SetOutputMode(OutputModePlain)
+	s.SetColorMode(ColorModeTrueColor)
+	s.SetCell(0, 0, "a", NewStyle().WithBold(true))
+	s.setGlyph(1, 0, "漢", 2, nil)
+	s.SetCell(0, 2, "c", nil)
+
+	if expected := "a漢\n\nc"; s.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, s.String())
+	}
+}
+
+func TestScreenPlainASCIIBorders(t *testing.T) {
+	s := NewScreen(4, 3)
+	s.SetOutputMode(OutputModePlain)
+	s.SetUnicode(false)
+	style := NewStyle().WithBorder(RoundedBorder)
+	s.Render(NewStyledNode(&layout.Node{Rect: layout.Rect{Width: 4, Height: 3}}, style))
+
+	if expected := "+--+\n|  |\n+--+"; s.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, s.String())
+	}
+}
@@ -0,0 +1,100 @@
+package renderer
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/muesli/cancelreader"
+)
+
+// errReplyTimeout is returned by readReplies when the terminal does not
+// answer in time
+var errReplyTimeout = errors.New("renderer: terminal did not reply")
+
+// pendingInput holds terminal input that arrived while waiting for query
+// replies but was not part of one, such as keys typed meanwhile
+var (
+	pendingMu    sync.Mutex
+	pendingInput []byte
+)
+
+// PendingInput returns and clears the input that Calibrate and
+// QuerySynchronizedOutput read from the terminal besides their replies,
+// so it can be decoded ahead of new input. app.App does this when it runs.
+func PendingInput() []byte {
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	data := pendingInput
+	pendingInput = nil
+	return data
+}
+
+// keepPendingInput adds input for PendingInput
+func keepPendingInput(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	pendingMu.Lock()
+	defer pendingMu.Unlock()
+	pendingInput = append(pendingInput, data...)
+}
+
+// readReplies reads terminal replies from in until parse reports them
+// complete, the input fails or the timeout expires. Parse is given all
+// input read so far and returns whether every reply has arrived and the
+// input that is not part of a reply, which is kept for PendingInput.
+//
+// A read from a terminal is cancelled on timeout, so nothing is left
+// reading in the background to take the app's later input. Other readers
+// cannot be interrupted; a read still blocked on one discards what it
+// returns once the timeout has passed.
+func readReplies(in io.Reader, timeout time.Duration, parse func(buf []byte) (complete bool, rest []byte)) error {
+	reader, err := cancelreader.NewReader(in)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	var (
+		mu   sync.Mutex
+		read []byte
+	)
+	done := make(chan error, 1)
+	go func() {
+		chunk := make([]byte, 256)
+		for {
+			n, err := reader.Read(chunk)
+			mu.Lock()
+			read = append(read, chunk[:n]...)
+			complete, _ := parse(read)
+			mu.Unlock()
+
+			// Stop as soon as every reply has arrived so no later input is consumed
+			if complete {
+				done <- nil
+				return
+			}
+			if err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		if reader.Cancel() {
+			<-done
+		}
+		err = errReplyTimeout
+	}
+
+	mu.Lock()
+	_, rest := parse(read)
+	mu.Unlock()
+	keepPendingInput(rest)
+	return err
+}
@@ -0,0 +1,265 @@
+package renderer
+
+import (
+	"sort"
+
+	"github.com/SCKelemen/color"
+)
+
+// Rule defines a separator line drawn in the gaps between a container's
+// children, like CSS column-rule and row-rule
+type Rule struct {
+	// Line style: Vertical is drawn for column rules, Horizontal for row rules
+	Chars BorderChars
+
+	// Rule color, falling back to the container's border color
+	Color *color.Color
+}
+
+// NewRule creates a rule with the given line style and color
+func NewRule(chars BorderChars, c *color.Color) *Rule {
+	return &Rule{
+		Chars: chars,
+		Color: c,
+	}
+}
+
+// ruleSegment is a straight run of rule cells. Vertical segments span rows
+// start..end at column pos; horizontal segments span columns at row pos.
+// Ends that meet a perpendicular rule are drawn as half arms so the two
+// lines join with a junction glyph.
+type ruleSegment struct {
+	pos        int
+	start, end int
+	vertical   bool
+	joinStart  bool
+	joinEnd    bool
+}
+
+// cellRect is an integer rectangle in screen coordinates
+type cellRect struct {
+	x, y, w, h int
+}
+
+func (r cellRect) contains(col, row int) bool {
+	return col >= r.x && col < r.x+r.w && row >= r.y && row < r.y+r.h
+}
+
+// renderRules draws the column and row rules of a container in the gaps
+// between its laid-out children. A rule sits in the middle of each gap that
+// is at least one cell wide, and spans both children it separates.
+func (s *Screen) renderRules(node *StyledNode, originX, originY int) {
+	style := node.Style
+	if style == nil || (style.ColumnRule == nil && style.RowRule == nil) {
+		return
+	}
+
+	rects := make([]cellRect, 0, len(node.Children))
+	for _, child := range node.Children {
+		if child == nil || child.Node == nil {
+			continue
+		}
+		r := cellRect{
+			x: originX + int(child.Node.Rect.X),
+			y: originY + int(child.Node.Rect.Y),
+			w: int(child.Node.Rect.Width),
+			h: int(child.Node.Rect.Height),
+		}
+		if r.w > 0 && r.h > 0 {
+			rects = append(rects, r)
+		}
+	}
+
+	var vertical, horizontal []ruleSegment
+	if style.ColumnRule != nil {
+		vertical = gapSegments(rects, true)
+	}
+	if style.RowRule != nil {
+		horizontal = gapSegments(rects, false)
+	}
+
+	// Bridge collinear rules across the gaps of the other axis, then
+	// stretch rule ends so perpendicular rules meet
+	vertical = mergeSegments(vertical, rects)
+	horizontal = mergeSegments(horizontal, rects)
+	joinSegments(vertical, horizontal, rects)
+	joinSegments(horizontal, vertical, rects)
+
+	if style.ColumnRule != nil {
+		s.drawRuleSegments(vertical, style.ColumnRule, style)
+	}
+	if style.RowRule != nil {
+		s.drawRuleSegments(horizontal, style.RowRule, style)
+	}
+}
+
+// gapSegments finds the rule segments between each child and its nearest
+// neighbour to the right (vertical rules) or below (horizontal rules)
+func gapSegments(rects []cellRect, vertical bool) []ruleSegment {
+	var segments []ruleSegment
+	for i, a := range rects {
+		nearest := -1
+		for j, b := range rects {
+			if i == j {
+				continue
+			}
+			if vertical {
+				if b.x < a.x+a.w || b.y >= a.y+a.h || a.y >= b.y+b.h {
+					continue
+				}
+				if nearest < 0 || b.x < rects[nearest].x {
+					nearest = j
+				}
+			} else {
+				if b.y < a.y+a.h || b.x >= a.x+a.w || a.x >= b.x+b.w {
+					continue
+				}
+				if nearest < 0 || b.y < rects[nearest].y {
+					nearest = j
+				}
+			}
+		}
+		if nearest < 0 {
+			continue
+		}
+
+		b := rects[nearest]
+		if vertical {
+			gap := b.x - (a.x + a.w)
+			if gap < 1 {
+				continue
+			}
+			segments = append(segments, ruleSegment{
+				pos:      a.x + a.w + (gap-1)/2,
+				start:    min(a.y, b.y),
+				end:      max(a.y+a.h, b.y+b.h) - 1,
+				vertical: true,
+			})
+		} else {
+			gap := b.y - (a.y + a.h)
+			if gap < 1 {
+				continue
+			}
+			segments = append(segments, ruleSegment{
+				pos:   a.y + a.h + (gap-1)/2,
+				start: min(a.x, b.x),
+				end:   max(a.x+a.w, b.x+b.w) - 1,
+			})
+		}
+	}
+	return segments
+}
+
+// mergeSegments joins segments on the same line that overlap or are
+// separated only by empty gap cells
+func mergeSegments(segments []ruleSegment, rects []cellRect) []ruleSegment {
+	sort.Slice(segments, func(i, j int) bool {
+		if segments[i].pos != segments[j].pos {
+			return segments[i].pos < segments[j].pos
+		}
+		return segments[i].start < segments[j].start
+	})
+
+	var merged []ruleSegment
+	for _, seg := range segments {
+		if n := len(merged); n > 0 {
+			last := &merged[n-1]
+			if last.pos == seg.pos && (seg.start <= last.end+1 || clearPath(last, last.end+1, seg.start-1, rects)) {
+				last.end = max(last.end, seg.end)
+				continue
+			}
+		}
+		merged = append(merged, seg)
+	}
+	return merged
+}
+
+// joinSegments extends the ends of segments across empty gap cells until
+// they reach a perpendicular segment. Extensions never cross a child.
+func joinSegments(segments, crossing []ruleSegment, rects []cellRect) {
+	for i := range segments {
+		seg := &segments[i]
+		for _, other := range crossing {
+			if seg.pos < other.start || seg.pos > other.end {
+				continue
+			}
+			if other.pos < seg.start && clearPath(seg, other.pos, seg.start-1, rects) {
+				seg.start = other.pos
+				seg.joinStart = true
+			}
+			if other.pos > seg.end && clearPath(seg, seg.end+1, other.pos, rects) {
+				seg.end = other.pos
+				seg.joinEnd = true
+			}
+		}
+	}
+}
+
+// clearPath reports whether the cells from..to along a segment avoid every child
+func clearPath(seg *ruleSegment, from, to int, rects []cellRect) bool {
+	for p := from; p <= to; p++ {
+		col, row := seg.pos, p
+		if !seg.vertical {
+			col, row = p, seg.pos
+		}
+		for _, r := range rects {
+			if r.contains(col, row) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// drawRuleSegments draws rule segments into the junction layer so crossing
+// rules merge into junction glyphs. Rules that cannot merge, such as block
+// rules, are drawn as they are and simply overlap.
+func (s *Screen) drawRuleSegments(segments []ruleSegment, rule *Rule, container *Style) {
+	chars := rule.Chars
+	if !s.unicode {
+		chars = ASCIIBorder
+	}
+	weight := borderWeight(chars)
+	ascii := chars == ASCIIBorder
+	joinable := chars.joinable()
+
+	c := rule.Color
+	if c == nil {
+		c = container.BorderColor
+	}
+	if c == nil {
+		c = container.Foreground
+	}
+	ruleStyle := &Style{Foreground: c}
+
+	for _, seg := range segments {
+		line := chars.Horizontal
+		if seg.vertical {
+			line = chars.Vertical
+		}
+		for p := seg.start; p <= seg.end; p++ {
+			if !joinable {
+				if seg.vertical {
+					s.SetCell(seg.pos, p, string(line), ruleStyle)
+				} else {
+					s.SetCell(p, seg.pos, string(line), ruleStyle)
+				}
+				continue
+			}
+
+			before, after := weight, weight
+			if p == seg.start && seg.joinStart {
+				before = lineNone
+			}
+			if p == seg.end && seg.joinEnd {
+				after = lineNone
+			}
+
+			if seg.vertical {
+				s.setJunction(seg.pos, p, lineArms{before, 0, after, 0}, line, false, ascii, ruleStyle)
+			} else {
+				s.setJunction(p, seg.pos, lineArms{0, after, 0, before}, line, false, ascii, ruleStyle)
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/layout"
+)
+
+func ruleContainer(width, height int, style *Style, rects ...layout.Rect) *StyledNode {
+	root := NewStyledNode(&layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: float64(width), Height: float64(height)},
+	}, style)
+	for _, r := range rects {
+		child := NewStyledNode(&layout.Node{Rect: r}, nil)
+		child.Content = "x"
+		root.AddChild(child)
+	}
+	return root
+}
+
+func TestColumnRule(t *testing.T) {
+	s := NewScreen(11, 2)
+	s.SetUnicode(true)
+
+	style := NewStyle().WithColumnRule(NewRule(NormalBorder, nil))
+	s.Render(ruleContainer(11, 2, style,
+		layout.Rect{X: 0, Y: 0, Width: 3, Height: 2},
+		layout.Rect{X: 4, Y: 0, Width: 3, Height: 2},
+		layout.Rect{X: 8, Y: 0, Width: 3, Height: 2},
+	))
+
+	expected := []string{
+		"x  │x  │x  ",
+		"   │   │   ",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestColumnRuleNeedsGap(t *testing.T) {
+	s := NewScreen(6, 1)
+	s.SetUnicode(true)
+
+	style := NewStyle().WithColumnRule(NewRule(NormalBorder, nil))
+	s.Render(ruleContainer(6, 1, style,
+		layout.Rect{X: 0, Y: 0, Width: 3, Height: 1},
+		layout.Rect{X: 3, Y: 0, Width: 3, Height: 1},
+	))
+
+	if got := rowString(s, 0); got != "x  x  " {
+		t.Errorf("Expected no rule without a gap, got %q", got)
+	}
+}
+
+func TestGridRulesCross(t *testing.T) {
+	s := NewScreen(7, 3)
+	s.SetUnicode(true)
+
+	style := NewStyle().
+		WithColumnRule(NewRule(NormalBorder, nil)).
+		WithRowRule(NewRule(DashedBorder, nil))
+	s.Render(ruleContainer(7, 3, style,
+		layout.Rect{X: 0, Y: 0, Width: 3, Height: 1},
+		layout.Rect{X: 4, Y: 0, Width: 3, Height: 1},
+		layout.Rect{X: 0, Y: 2, Width: 3, Height: 1},
+		layout.Rect{X: 4, Y: 2, Width: 3, Height: 1},
+	))
+
+	expected := []string{
+		"x  │x  ",
+		"╌╌╌┼╌╌╌",
+		"x  │x  ",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestBlockRulesOverlap(t *testing.T) {
+	s := NewScreen(7, 3)
+	s.SetUnicode(true)
+
+	// Block rules cannot join, so they are drawn as blocks where they cross
+	// a light rule
+	style := NewStyle().
+		WithColumnRule(NewRule(NormalBorder, nil)).
+		WithRowRule(NewRule(BlockBorder, nil))
+	s.Render(ruleContainer(7, 3, style,
+		layout.Rect{X: 0, Y: 0, Width: 3, Height: 1},
+		layout.Rect{X: 4, Y: 0, Width: 3, Height: 1},
+		layout.Rect{X: 0, Y: 2, Width: 3, Height: 1},
+		layout.Rect{X: 4, Y: 2, Width: 3, Height: 1},
+	))
+
+	expected := []string{
+		"x  │x  ",
+		"███████",
+		"x  │x  ",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestRowRuleMeetsColumnRule(t *testing.T) {
+	s := NewScreen(7, 3)
+	s.SetUnicode(true)
+
+	style := NewStyle().
+		WithColumnRule(NewRule(NormalBorder, nil)).
+		WithRowRule(NewRule(NormalBorder, nil))
+	s.Render(ruleContainer(7, 3, style,
+		layout.Rect{X: 0, Y: 0, Width: 7, Height: 1},
+		layout.Rect{X: 0, Y: 2, Width: 3, Height: 1},
+		layout.Rect{X: 4, Y: 2, Width: 3, Height: 1},
+	))
+
+	expected := []string{
+		"x      ",
+		"───┬───",
+		"x  │x  ",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestRulesJoinCollapsedBorder(t *testing.T) {
+	s := NewScreen(9, 5)
+	s.SetUnicode(true)
+
+	style := NewStyle().
+		WithBorder(NormalBorder).
+		WithBorderCollapse(true).
+		WithColumnRule(NewRule(NormalBorder, nil)).
+		WithRowRule(NewRule(NormalBorder, nil))
+	s.Render(ruleContainer(9, 5, style,
+		layout.Rect{X: 1, Y: 1, Width: 3, Height: 1},
+		layout.Rect{X: 5, Y: 1, Width: 3, Height: 1},
+		layout.Rect{X: 1, Y: 3, Width: 3, Height: 1},
+		layout.Rect{X: 5, Y: 3, Width: 3, Height: 1},
+	))
+
+	expected := []string{
+		"┌───┬───┐",
+		"│x  │x  │",
+		"├───┼───┤",
+		"│x  │x  │",
+		"└───┴───┘",
+	}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestRulesTouchPlainBorder(t *testing.T) {
+	s := NewScreen(9, 3)
+	s.SetUnicode(true)
+
+	// Without border collapse the border is not part of the junction layer
+	style := NewStyle().
+		WithBorder(NormalBorder).
+		WithColumnRule(NewRule(NormalBorder, nil))
+	s.Render(ruleContainer(9, 3, style,
+		layout.Rect{X: 1, Y: 1, Width: 3, Height: 1},
+		layout.Rect{X: 5, Y: 1, Width: 3, Height: 1},
+	))
+
+	if got := rowString(s, 0); got != "┌───────┐" {
+		t.Errorf("Expected plain top border, got %q", got)
+	}
+}
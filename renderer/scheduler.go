@@ -0,0 +1,228 @@
+package renderer
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultMaxFPS is the frame rate cap used when SchedulerOptions.MaxFPS is zero
+const DefaultMaxFPS = 60
+
+// FrameStats describes one frame written by a Scheduler
+type FrameStats struct {
+	Frame      uint64        // Sequence number, starting at 1
+	Start      time.Time     // When rendering began
+	RenderTime time.Duration // Time spent producing the frame
+	WriteTime  time.Duration // Time spent writing it to the output
+	Bytes      int           // Bytes written, including synchronization sequences
+	Requests   int           // Render requests coalesced into this frame
+	Skipped    int           // Frame slots lost while the previous frame was still being written
+}
+
+// SchedulerStats accumulates statistics over every frame
+type SchedulerStats struct {
+	Frames  uint64
+	Skipped uint64
+	Bytes   uint64
+	Last    FrameStats
+}
+
+// SchedulerOptions configures a Scheduler
+type SchedulerOptions struct {
+	// MaxFPS caps the frame rate (0 = DefaultMaxFPS)
+	MaxFPS int
+
+	// Synchronized wraps each frame in a synchronized update (DEC mode
+	// 2026), usually set from DetectCapabilities().SynchronizedOutput
+	Synchronized bool
+
+	// FinalFrameOnly holds every frame back until Stop, which draws the last
+	// one. Set it when the output is not a terminal, for example to
+	// DetectOutputMode() == OutputModePlain, so logs show only the final
+	// state of an animation.
+	FinalFrameOnly bool
+
+	// OnFrame is called after each frame is written
+	OnFrame func(FrameStats)
+}
+
+// Scheduler draws frames on demand. Render requests made while a frame is
+// pending are coalesced into it, frames are spaced at least 1/MaxFPS apart,
+// and a slow writer (for example over SSH) never builds a backlog: requests
+// made while a frame is being written are drawn as one frame afterwards.
+type Scheduler struct {
+	out      io.Writer
+	render   func() string
+	opts     SchedulerOptions
+	interval time.Duration
+
+	mu       sync.Mutex
+	started  bool
+	requests int
+	stats    SchedulerStats
+	err      error
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewScheduler creates a scheduler that writes the frames produced by render
+// to out. Call Start to begin drawing.
+func NewScheduler(out io.Writer, render func() string, opts SchedulerOptions) *Scheduler {
+	fps := opts.MaxFPS
+	if fps <= 0 {
+		fps = DefaultMaxFPS
+	}
+	return &Scheduler{
+		out:      out,
+		render:   render,
+		opts:     opts,
+		interval: time.Second / time.Duration(fps),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start begins drawing requested frames in a background goroutine
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started {
+		s.started = true
+		go s.run()
+	}
+}
+
+// Request asks for a frame to be drawn. It never blocks and is safe for
+// concurrent use.
+func (s *Scheduler) Request() {
+	s.mu.Lock()
+	s.requests++
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Stop draws any pending frame and stops the scheduler
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	started := s.started
+	s.mu.Unlock()
+	if !started {
+		return
+	}
+
+	s.once.Do(func() { close(s.stop) })
+	<-s.done
+}
+
+// Stats returns statistics over all frames drawn so far
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats
+}
+
+// Err returns the first error returned by the writer, if any
+func (s *Scheduler) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+func (s *Scheduler) run() {
+	defer close(s.done)
+
+	var last FrameStats
+	backlog := false
+	for {
+		select {
+		case <-s.wake:
+		case <-s.stop:
+			s.draw(last, backlog)
+			return
+		}
+		if s.opts.FinalFrameOnly {
+			continue
+		}
+
+		// Cap the frame rate; requests arriving meanwhile join this frame
+		if !last.Start.IsZero() {
+			if wait := s.interval - time.Since(last.Start); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-timer.C:
+				case <-s.stop:
+					timer.Stop()
+					s.draw(last, backlog)
+					return
+				}
+			}
+		}
+
+		if frame, ok := s.draw(last, backlog); ok {
+			last = frame
+
+			// Requests that arrived during the write were held back by it
+			s.mu.Lock()
+			backlog = s.requests > 0
+			s.mu.Unlock()
+		}
+	}
+}
+
+// draw renders and writes one frame if any requests are pending. When the
+// previous write held back requests, the frame slots it overran are
+// counted as skipped.
+func (s *Scheduler) draw(previous FrameStats, backlog bool) (FrameStats, bool) {
+	s.mu.Lock()
+	requests := s.requests
+	s.requests = 0
+	s.mu.Unlock()
+	if requests == 0 {
+		return FrameStats{}, false
+	}
+
+	start := time.Now()
+	frame := s.render()
+	if s.opts.Synchronized {
+		frame = syncBegin + frame + syncEnd
+	}
+	rendered := time.Now()
+	n, err := io.WriteString(s.out, frame)
+	written := time.Now()
+
+	stats := FrameStats{
+		Start:      start,
+		RenderTime: rendered.Sub(start),
+		WriteTime:  written.Sub(rendered),
+		Bytes:      n,
+		Requests:   requests,
+	}
+	if backlog {
+		stats.Skipped = int((previous.RenderTime + previous.WriteTime) / s.interval)
+	}
+
+	s.mu.Lock()
+	s.stats.Frames++
+	s.stats.Skipped += uint64(stats.Skipped)
+	s.stats.Bytes += uint64(n)
+	stats.Frame = s.stats.Frames
+	s.stats.Last = stats
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+	s.mu.Unlock()
+
+	if s.opts.OnFrame != nil {
+		s.opts.OnFrame(stats)
+	}
+	return stats, true
+}
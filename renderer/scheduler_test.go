@@ -0,0 +1,151 @@
+package renderer
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowWriter collects output, taking delay for every write
+type slowWriter struct {
+	mu    sync.Mutex
+	delay time.Duration
+	buf   strings.Builder
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestSchedulerSynchronizedFrame(t *testing.T) {
+	out := &slowWriter{}
+	var frames []FrameStats
+	s := NewScheduler(out, func() string { return "frame" }, SchedulerOptions{
+		Synchronized: true,
+		OnFrame:      func(f FrameStats) { frames = append(frames, f) },
+	})
+	s.Start()
+	s.Request()
+	s.Stop()
+
+	expected := "\x1b[?2026hframe\x1b[?2026l"
+	if got := out.String(); got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+	if len(frames) != 1 || frames[0].Bytes != len(expected) || frames[0].Frame != 1 {
+		t.Errorf("Expected one frame of %d bytes, got %+v", len(expected), frames)
+	}
+}
+
+func TestSchedulerCoalescesRequests(t *testing.T) {
+	var mu sync.Mutex
+	renders := 0
+	s := NewScheduler(&slowWriter{}, func() string {
+		mu.Lock()
+		renders++
+		mu.Unlock()
+		return "x"
+	}, SchedulerOptions{MaxFPS: 10})
+
+	s.Start()
+	for i := 0; i < 50; i++ {
+		s.Request()
+	}
+	s.Stop()
+
+	stats := s.Stats()
+	if stats.Frames > 2 {
+		t.Errorf("Expected requests to coalesce into at most 2 frames, got %d", stats.Frames)
+	}
+	if int(stats.Frames) != renders {
+		t.Errorf("Expected one render per frame, got %d renders for %d frames", renders, stats.Frames)
+	}
+}
+
+func TestSchedulerCapsFrameRate(t *testing.T) {
+	s := NewScheduler(&slowWriter{}, func() string { return "x" }, SchedulerOptions{MaxFPS: 20})
+	s.Start()
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		s.Request()
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+
+	// 20 FPS over 200ms allows about 4 frames, plus the final flush
+	if frames := s.Stats().Frames; frames > 6 {
+		t.Errorf("Expected frame rate to be capped, got %d frames", frames)
+	}
+}
+
+func TestSchedulerSkipsFramesForSlowWriter(t *testing.T) {
+	out := &slowWriter{delay: 40 * time.Millisecond}
+	s := NewScheduler(out, func() string { return "x" }, SchedulerOptions{MaxFPS: 100})
+	s.Start()
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		s.Request()
+		time.Sleep(time.Millisecond)
+	}
+	s.Stop()
+
+	stats := s.Stats()
+	if stats.Frames > 7 {
+		t.Errorf("Expected the slow writer to limit frames, got %d", stats.Frames)
+	}
+	if stats.Skipped == 0 {
+		t.Error("Expected frames to be skipped while writes were in progress")
+	}
+	if stats.Bytes != stats.Frames {
+		t.Errorf("Expected %d bytes, got %d", stats.Frames, stats.Bytes)
+	}
+}
+
+func TestSchedulerStopWithoutRequests(t *testing.T) {
+	out := &slowWriter{}
+	s := NewScheduler(out, func() string { return "x" }, SchedulerOptions{})
+	s.Start()
+	s.Stop()
+
+	if out.String() != "" {
+		t.Errorf("Expected no output without requests, got %q", out.String())
+	}
+}
+
+func TestSchedulerFinalFrameOnly(t *testing.T) {
+	out := &slowWriter{}
+	frame := 0
+	s := NewScheduler(out, func() string {
+		frame++
+		return strings.Repeat("x", frame)
+	}, SchedulerOptions{FinalFrameOnly: true})
+
+	s.Start()
+	for i := 0; i < 3; i++ {
+		s.Request()
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got := out.String(); got != "" {
+		t.Errorf("Expected no output before Stop, got %q", got)
+	}
+
+	s.Stop()
+	if got := out.String(); got != "x" {
+		t.Errorf("Expected only the final frame, got %q", got)
+	}
+	if stats := s.Stats(); stats.Frames != 1 || stats.Last.Requests != 3 {
+		t.Errorf("Expected one frame for 3 requests, got %+v", stats)
+	}
+}
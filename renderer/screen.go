@@ -1,14 +1,188 @@
 package renderer
 
 import (
+	"slices"
 	"strings"
+	"sync"
+	"unicode/utf8"
 
 	"github.com/SCKelemen/layout"
 	"github.com/SCKelemen/text"
 )
 
-// textMeasurer is the Unicode-aware text measurement library
-var textMeasurer = text.NewTerminal()
+// textMeasurer is the Unicode-aware text measurement library, consulting
+// the calibrated width overrides installed with SetWidthOverrides
+var textMeasurer = newMeasurer()
+
+// measurer wraps the text library so grapheme widths measured on the live
+// terminal take precedence over the built-in Unicode width tables
+type measurer struct {
+	*text.Text
+}
+
+func newMeasurer() *measurer {
+	return &measurer{
+		// Single-rune overrides also apply inside wrapping and elision
+		Text: text.New(text.Config{
+			MeasureFunc: func(r rune) float64 {
+				if width, ok := widthOverrides.Load().Lookup(string(r)); ok {
+					return float64(width)
+				}
+				return text.TerminalMeasure(r)
+			},
+		}),
+	}
+}
+
+// Width returns the cell width of s, using overrides for any grapheme
+// that was calibrated
+func (m *measurer) Width(s string) float64 {
+	table := widthOverrides.Load()
+	if table == nil || len(table.Widths) == 0 {
+		return m.Text.Width(s)
+	}
+
+	width := 0.0
+	for _, g := range m.Graphemes(s) {
+		if w, ok := table.Lookup(g); ok {
+			width += float64(w)
+		} else {
+			width += m.Text.Width(g)
+		}
+	}
+	return width
+}
+
+// overridden reports whether calibrated widths are installed. The text
+// library measures whole grapheme clusters by its own tables inside
+// wrapping and elision, so those are done here while they are.
+func (m *measurer) overridden() bool {
+	table := widthOverrides.Load()
+	return table != nil && len(table.Widths) > 0
+}
+
+// Wrap breaks text into lines of at most opts.MaxWidth cells between
+// grapheme clusters, measuring calibrated graphemes by their override
+func (m *measurer) Wrap(s string, opts text.WrapOptions) []text.Line {
+	if !m.overridden() || opts.MaxWidth <= 0 {
+		return m.Text.Wrap(s, opts)
+	}
+
+	var lines []text.Line
+	var line strings.Builder
+	width, start, pos := 0.0, 0, 0
+	for _, g := range m.Graphemes(s) {
+		w := m.Width(g)
+		if width+w > opts.MaxWidth && width > 0 {
+			lines = append(lines, text.Line{Content: line.String(), Width: width, Start: start, End: pos})
+			line.Reset()
+			width, start = 0, pos
+		}
+		line.WriteString(g)
+		width += w
+		pos += utf8.RuneCountInString(g)
+	}
+	if line.Len() > 0 {
+		lines = append(lines, text.Line{Content: line.String(), Width: width, Start: start, End: pos})
+	}
+	return lines
+}
+
+// ElideEndWith shortens s to maxWidth cells, ending it with ellipsis
+func (m *measurer) ElideEndWith(s string, maxWidth float64, ellipsis string) string {
+	if !m.overridden() {
+		return m.Text.ElideEndWith(s, maxWidth, ellipsis)
+	}
+	return m.elide(s, maxWidth, ellipsis, text.TruncateEnd)
+}
+
+// ElideStartWith shortens s to maxWidth cells, starting it with ellipsis
+func (m *measurer) ElideStartWith(s string, maxWidth float64, ellipsis string) string {
+	if !m.overridden() {
+		return m.Text.ElideStartWith(s, maxWidth, ellipsis)
+	}
+	return m.elide(s, maxWidth, ellipsis, text.TruncateStart)
+}
+
+// ElideWith shortens s to maxWidth cells with ellipsis in the middle
+func (m *measurer) ElideWith(s string, maxWidth float64, ellipsis string) string {
+	if !m.overridden() {
+		return m.Text.ElideWith(s, maxWidth, ellipsis)
+	}
+	return m.elide(s, maxWidth, ellipsis, text.TruncateMiddle)
+}
+
+// elide truncates s with ellipsis the way the text library does, measuring
+// every grapheme with Width
+func (m *measurer) elide(s string, maxWidth float64, ellipsis string, strategy text.TruncateStrategy) string {
+	if m.Width(s) <= maxWidth {
+		return s
+	}
+	ellipsisWidth := m.Width(ellipsis)
+	if ellipsisWidth >= maxWidth {
+		return ""
+	}
+	target := maxWidth - ellipsisWidth
+	graphemes := m.Graphemes(s)
+
+	// take returns the graphemes that fit in avail cells from one end
+	take := func(avail float64, fromEnd bool) string {
+		var kept []string
+		width := 0.0
+		for i := range graphemes {
+			g := graphemes[i]
+			if fromEnd {
+				g = graphemes[len(graphemes)-1-i]
+			}
+			w := m.Width(g)
+			if width+w > avail {
+				break
+			}
+			kept = append(kept, g)
+			width += w
+		}
+		if fromEnd {
+			slices.Reverse(kept)
+		}
+		return strings.Join(kept, "")
+	}
+
+	switch strategy {
+	case text.TruncateStart:
+		return ellipsis + take(target, true)
+	case text.TruncateMiddle:
+		left := target / 2
+		return take(left, false) + ellipsis + take(target-left, true)
+	default:
+		return take(target, false) + ellipsis
+	}
+}
+
+// TextWidth returns the number of cells s takes on screen, using any
+// calibrated width overrides
+func TextWidth(s string) int {
+	return int(textMeasurer.Width(s))
+}
+
+// WrapText breaks one line of text into rows of at most width cells, the
+// way the renderer wraps content. Every grapheme cluster is kept, so the
+// rows join back into s.
+func WrapText(s string, width int) []string {
+	lines := textMeasurer.Wrap(s, text.WrapOptions{MaxWidth: float64(width)})
+	if len(lines) == 0 {
+		return []string{""}
+	}
+	rows := make([]string, len(lines))
+	for i, line := range lines {
+		rows[i] = line.Content
+	}
+	return rows
+}
+
+// Graphemes splits s into the grapheme clusters that are drawn as one glyph
+func Graphemes(s string) []string {
+	return textMeasurer.Graphemes(s)
+}
 
 // Cell represents a single character cell in the terminal
 type Cell struct {
@@ -24,9 +198,32 @@ type Screen struct {
 	Cells    [][]Cell
 	Previous [][]Cell
 	renderer *ANSIRenderer
+	unicode  bool // Draw box drawing glyphs rather than ASCII fallbacks
+	output   OutputMode
+
+	// junctions is the collapsed-border layer, keyed by y*Width+x
+	junctions map[int]*junction
+
+	// clip bounds painting while rendering the content of a node whose
+	// Overflow is not visible; nil paints the whole screen
+	clip *clipRect
+
+	// cursor is where the focused node asked for the terminal cursor in
+	// the last Render; emulated cursors are drawn into the cells
+	cursor        *placedCursor
+	emulateCursor bool
 }
 
-// NewScreen creates a new screen buffer
+// detectedUnicode and detectedOutputMode are the screen defaults, read from
+// the environment and stdout once since screens are created for every
+// frame and neither changes while a program runs
+var (
+	detectedUnicode    = sync.OnceValue(DetectUnicode)
+	detectedOutputMode = sync.OnceValue(DetectOutputMode)
+)
+
+// NewScreen creates a new screen buffer. Unicode and the output mode are
+// detected on first use; SetUnicode and SetOutputMode override them.
 func NewScreen(width, height int) *Screen {
 	return &Screen{
 		Width:    width,
@@ -34,6 +231,10 @@ func NewScreen(width, height int) *Screen {
 		Cells:    makeBuffer(width, height),
 		Previous: makeBuffer(width, height),
 		renderer: NewANSIRenderer(),
+		unicode:  detectedUnicode(),
+		output:   detectedOutputMode(),
+
+		emulateCursor: true,
 	}
 }
 
@@ -66,6 +267,28 @@ func (s *Screen) SetColorMode(mode ColorMode) {
 	s.renderer = NewANSIRendererWithMode(mode)
 }
 
+// SetUnicode sets whether borders use Unicode box drawing or ASCII
+func (s *Screen) SetUnicode(enabled bool) {
+	s.unicode = enabled
+}
+
+// SetOutputMode sets how String and Lines write the screen. NewScreen uses
+// plain output when stdout is not a terminal; combine it with
+// SetUnicode(false) for ASCII borders in logs.
+func (s *Screen) SetOutputMode(mode OutputMode) {
+	s.output = mode
+}
+
+// setGlyph stores a grapheme cluster and marks the extra columns of a wide
+// glyph with spaces, which prevents other content from overlapping it.
+// String skips these continuation cells when writing the glyph.
+func (s *Screen) setGlyph(x, y int, grapheme string, width int, style *Style) {
+	s.SetCell(x, y, grapheme, style)
+	for i := 1; i < width; i++ {
+		s.SetCell(x+i, y, " ", style)
+	}
+}
+
 // Clear resets all cells to empty
 func (s *Screen) Clear() {
 	for y := 0; y < s.Height; y++ {
@@ -73,11 +296,12 @@ func (s *Screen) Clear() {
 			s.Cells[y][x] = Cell{Content: " ", Style: nil}
 		}
 	}
+	s.junctions = nil
 }
 
 // SetCell sets a single cell with content (can be a rune or grapheme cluster)
 func (s *Screen) SetCell(x, y int, content string, style *Style) {
-	if x < 0 || x >= s.Width || y < 0 || y >= s.Height {
+	if x < 0 || x >= s.Width || y < 0 || y >= s.Height || !s.clip.contains(x, y) {
 		return
 	}
 	s.Cells[y][x] = Cell{Content: content, Style: style}
@@ -86,7 +310,12 @@ func (s *Screen) SetCell(x, y int, content string, style *Style) {
 // Render renders a styled node to the screen buffer
 func (s *Screen) Render(node *StyledNode) {
 	s.Clear()
+	s.cursor = nil
 	s.renderNodeWithOffset(node, 0, 0)
+	s.connectJunctions()
+	if s.emulateCursor {
+		s.drawCursor()
+	}
 }
 
 // renderNode recursively renders a node and its children (legacy method)
@@ -96,15 +325,44 @@ func (s *Screen) renderNode(node *StyledNode) {
 
 // renderNodeWithOffset recursively renders a node and its children with accumulated offsets
 func (s *Screen) renderNodeWithOffset(node *StyledNode, offsetX, offsetY int) {
+	s.renderNodeInBox(node, offsetX, offsetY, boxGrowth{}, false)
+}
+
+// boxGrowth extends the painted box of a node beyond its layout rect
+type boxGrowth struct {
+	top, right, bottom, left int
+}
+
+// renderNodeInBox renders a node whose painted box is grown beyond its layout
+// rect. Children are still positioned relative to the layout rect. Borders
+// are drawn into the junction layer when collapse is set.
+func (s *Screen) renderNodeInBox(node *StyledNode, offsetX, offsetY int, grow boxGrowth, collapse bool) {
 	if node == nil || node.Node == nil {
 		return
 	}
 
+	// Draw focused nodes with their focus style
+	if node.focused {
+		shown := *node
+		shown.Style = focusStyle(node.Style)
+		node = &shown
+	}
+
 	// Calculate absolute position by adding parent offsets
-	x := int(node.Node.Rect.X) + offsetX
-	y := int(node.Node.Rect.Y) + offsetY
-	w := int(node.Node.Rect.Width)
-	h := int(node.Node.Rect.Height)
+	originX := int(node.Node.Rect.X) + offsetX
+	originY := int(node.Node.Rect.Y) + offsetY
+
+	x := originX - grow.left
+	y := originY - grow.top
+	w := int(node.Node.Rect.Width) + grow.left + grow.right
+	h := int(node.Node.Rect.Height) + grow.top + grow.bottom
+
+	collapseChildren := node.Style != nil && node.Style.BorderCollapse
+
+	// Render shadow first so the node paints over its own area
+	if node.Style != nil && node.Style.Shadow != nil {
+		s.renderShadow(x, y, w, h, node.Style.Shadow)
+	}
 
 	// Render background if present
 	if node.Style != nil && node.Style.Background != nil {
@@ -113,7 +371,21 @@ func (s *Screen) renderNodeWithOffset(node *StyledNode, offsetX, offsetY int) {
 
 	// Render border if present
 	if node.Style != nil && node.Style.Border != nil {
-		s.renderBorder(x, y, w, h, node.Style)
+		s.renderBorder(x, y, w, h, node.Style, collapse || collapseChildren)
+	}
+
+	// Clip content and children to the box inside the border, shifted by
+	// the scroll offset
+	scrollX, scrollY := scrollOffset(node)
+	if cx, cy, cw, ch, ok := clipBox(node, x, y, w, h); ok {
+		outer := s.clip
+		s.clip = outer.intersect(cx, cy, cw, ch)
+		defer func() { s.clip = outer }()
+	}
+
+	// The focused node may place the terminal cursor in its content box
+	if node.focused && node.Cursor != nil {
+		s.placeCursor(node, x, y, w, h)
 	}
 
 	// Render content
@@ -131,71 +403,30 @@ func (s *Screen) renderNodeWithOffset(node *StyledNode, offsetX, offsetY int) {
 			contentH -= 2
 		}
 
-		s.renderText(contentX, contentY, contentW, contentH, node.Content, node.Style)
-	}
+		// Scrolled content starts above and left of the box and is clipped
+		contentX -= scrollX
+		contentY -= scrollY
+		contentW += scrollX
+		contentH += scrollY
 
-	// Render children with accumulated offsets
-	for _, child := range node.Children {
-		s.renderNodeWithOffset(child, x, y)
-	}
-}
-
-// renderBorder renders a border around the specified rectangle
-func (s *Screen) renderBorder(x, y, w, h int, style *Style) {
-	if style.Border == nil {
-		return
-	}
-
-	border := style.Border
-	chars := border.Chars
-
-	// Use border color if specified, otherwise use foreground color
-	borderStyle := &Style{
-		Foreground: style.BorderColor,
-	}
-	if borderStyle.Foreground == nil {
-		borderStyle.Foreground = style.Foreground
-	}
-
-	// Top border
-	if border.Top && y >= 0 && y < s.Height {
-		if border.Left && x >= 0 && x < s.Width {
-			s.SetCell(x, y, string(chars.TopLeft), borderStyle)
-		}
-		for i := 1; i < w-1; i++ {
-			if x+i >= 0 && x+i < s.Width {
-				s.SetCell(x+i, y, string(chars.Horizontal), borderStyle)
-			}
-		}
-		if border.Right && x+w-1 >= 0 && x+w-1 < s.Width {
-			s.SetCell(x+w-1, y, string(chars.TopRight), borderStyle)
+		if mode := node.Node.Style.WritingMode; mode.IsVertical() {
+			s.renderVerticalText(contentX, contentY, contentW, contentH, node.Content, node.Style, mode)
+		} else {
+			s.renderText(contentX, contentY, contentW, contentH, node.Content, node.Style)
 		}
 	}
 
-	// Bottom border
-	if border.Bottom && y+h-1 >= 0 && y+h-1 < s.Height {
-		if border.Left && x >= 0 && x < s.Width {
-			s.SetCell(x, y+h-1, string(chars.BottomLeft), borderStyle)
-		}
-		for i := 1; i < w-1; i++ {
-			if x+i >= 0 && x+i < s.Width {
-				s.SetCell(x+i, y+h-1, string(chars.Horizontal), borderStyle)
-			}
-		}
-		if border.Right && x+w-1 >= 0 && x+w-1 < s.Width {
-			s.SetCell(x+w-1, y+h-1, string(chars.BottomRight), borderStyle)
+	// Render children with accumulated offsets, in stacking order
+	for _, child := range paintOrder(node.Children) {
+		var childGrow boxGrowth
+		if collapseChildren {
+			childGrow = collapseGrowth(node, child)
 		}
+		s.renderNodeInBox(child, originX-scrollX, originY-scrollY, childGrow, collapseChildren)
 	}
 
-	// Left and right borders
-	for i := 1; i < h-1; i++ {
-		if border.Left && y+i >= 0 && y+i < s.Height && x >= 0 && x < s.Width {
-			s.SetCell(x, y+i, string(chars.Vertical), borderStyle)
-		}
-		if border.Right && y+i >= 0 && y+i < s.Height && x+w-1 >= 0 && x+w-1 < s.Width {
-			s.SetCell(x+w-1, y+i, string(chars.Vertical), borderStyle)
-		}
-	}
+	// Render separator rules in the gaps between children
+	s.renderRules(node, originX-scrollX, originY-scrollY)
 }
 
 // renderBackground fills the rectangle with the background color
@@ -230,22 +461,30 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 	}
 
 	// Get lines based on wrapping mode
-	var lines []text.Line
-	wrapMode := TextWrapNone
+	// Apply white-space handling and tab stops
+	content, wrapMode := processWhiteSpace(content, style)
+
+	direction := DirectionLTR
 	if style != nil {
-		wrapMode = style.TextWrap
+		direction = style.Direction
 	}
 
+	// Each line keeps the base direction of its paragraph, so a wrapped
+	// paragraph reads in one direction throughout
+	var lines []text.Line
+	var directions []Direction
 	switch wrapMode {
 	case TextWrapNone:
 		// Manual line breaks only
 		rawLines := strings.Split(content, "\n")
 		lines = make([]text.Line, len(rawLines))
+		directions = make([]Direction, len(rawLines))
 		for i, line := range rawLines {
 			lines[i] = text.Line{
 				Content: line,
 				Width:   textMeasurer.Width(line),
 			}
+			directions[i] = paragraphDirection(line, direction)
 		}
 
 	case TextWrapNormal, TextWrapBalanced, TextWrapPretty:
@@ -261,6 +500,7 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 					Content: "",
 					Width:   0,
 				})
+				directions = append(directions, direction)
 				continue
 			}
 
@@ -280,19 +520,39 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 				})
 			}
 			lines = append(lines, paraLines...)
+			paraDirection := paragraphDirection(para, direction)
+			for range paraLines {
+				directions = append(directions, paraDirection)
+			}
 		}
 	}
 
-	// Render each line
-	for lineIdx, line := range lines {
-		if lineIdx >= h {
-			break
+	if style != nil && style.TrimTrailingSpace {
+		for i, line := range lines {
+			trimmed := strings.TrimRight(line.Content, " ")
+			lines[i] = text.Line{
+				Content: trimmed,
+				Width:   textMeasurer.Width(trimmed),
+			}
 		}
+	}
 
-		row := y + lineIdx
-		if row < 0 || row >= s.Height {
-			continue
+	lines = clampLines(lines, w, h, style, s.ellipsis())
+
+	// Vertical alignment within the content box
+	offsetY := 0
+	if style != nil && len(lines) < h {
+		switch style.VerticalAlign {
+		case VerticalAlignMiddle:
+			offsetY = (h - len(lines)) / 2
+		case VerticalAlignBottom:
+			offsetY = h - len(lines)
 		}
+	}
+
+	// Place each line: apply overflow, justification and alignment
+	placed := make([]placedLine, 0, len(lines))
+	for lineIdx, line := range lines {
 
 		lineText := line.Content
 		lineWidth := line.Width
@@ -301,11 +561,11 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 		if style != nil && lineWidth > float64(w) {
 			switch style.TextOverflow {
 			case TextOverflowEllipsis:
-				lineText = textMeasurer.ElideEndWith(lineText, float64(w), "…")
+				lineText = textMeasurer.ElideEndWith(lineText, float64(w), s.ellipsis())
 			case TextOverflowEllipsisStart:
-				lineText = textMeasurer.ElideStartWith(lineText, float64(w), "…")
+				lineText = textMeasurer.ElideStartWith(lineText, float64(w), s.ellipsis())
 			case TextOverflowEllipsisMiddle:
-				lineText = textMeasurer.ElideWith(lineText, float64(w), "…")
+				lineText = textMeasurer.ElideWith(lineText, float64(w), s.ellipsis())
 			}
 			lineWidth = textMeasurer.Width(lineText)
 		}
@@ -320,26 +580,49 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 			}
 		}
 
+		// Reorder into visual order; alignment is relative to the
+		// paragraph's resolved base direction
+		graphemes, rtl := visualGraphemes(lineText, directions[lineIdx])
+
 		// Calculate starting column based on alignment
 		col := x
 		if style != nil {
-			switch style.TextAlign {
+			switch resolveAlign(style.TextAlign, rtl) {
 			case TextAlignCenter:
 				col = x + (w-int(lineWidth))/2
 			case TextAlignRight:
 				col = x + w - int(lineWidth)
 			case TextAlignJustify:
-				// Justify was already applied above, so left-align the result
-				col = x
+				// Justify was already applied above, so align the result to the start
+				if rtl {
+					col = x + w - int(lineWidth)
+				}
 			case TextAlignLeft:
 				col = x
 			}
 		}
 
-		// Render the line with proper grapheme cluster handling
-		graphemes := textMeasurer.Graphemes(lineText)
+		placed = append(placed, placedLine{
+			graphemes: graphemes,
+			width:     int(lineWidth),
+			col:       col,
+			row:       y + offsetY + lineIdx,
+		})
+	}
 
-		for _, grapheme := range graphemes {
+	paint := s.newGlyphPainter(placed, x, w, style)
+
+	// Render each line
+	for _, line := range placed {
+		row := line.row
+		if row < 0 || row >= s.Height {
+			continue
+		}
+
+		col := line.col
+
+		// Render the line with proper grapheme cluster handling
+		for _, grapheme := range line.graphemes {
 			// Measure grapheme width
 			graphemeWidth := int(textMeasurer.Width(grapheme))
 
@@ -353,15 +636,11 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 			// For multi-rune graphemes (emoji sequences, combining marks), we store
 			// the entire sequence in the cell and it will be output correctly
 			// Note: Complex emoji sequences may not render correctly in all terminals
-			if len(grapheme) > 0 && col >= 0 {
-				// Store the complete grapheme cluster (handles emoji sequences correctly)
-				s.SetCell(col, row, grapheme, style)
+			if len(grapheme) > 0 && col >= max(x, 0) {
+				cellStyle := paint(col, row)
 
-				// For wide characters/graphemes (width=2), mark the second column
-				// This prevents other content from overlapping
-				if graphemeWidth == 2 && col+1 < s.Width {
-					s.SetCell(col+1, row, " ", style)
-				}
+				// Store the complete grapheme cluster (handles emoji sequences correctly)
+				s.setGlyph(col, row, grapheme, graphemeWidth, cellStyle)
 			}
 
 			col += graphemeWidth
@@ -369,8 +648,157 @@ func (s *Screen) renderText(x, y, w, h int, content string, style *Style) {
 	}
 }
 
-// String converts the screen buffer to a string with ANSI codes
+// processWhiteSpace expands tabs to tab stops and applies the style's
+// white-space mode, returning the processed content and the wrap mode to
+// use. Modes that wrap use the style's TextWrap, or normal wrapping if none.
+func processWhiteSpace(content string, style *Style) (string, TextWrap) {
+	if style == nil {
+		style = &Style{}
+	}
+
+	tabSize := style.TabSize
+	if tabSize <= 0 {
+		tabSize = DefaultTabSize
+	}
+	content = textMeasurer.ExpandTabs(content, text.TabSize{
+		Value: float64(tabSize),
+		Unit:  text.TabSizeSpaces,
+	})
+
+	wrapping := style.TextWrap
+	if wrapping == TextWrapNone {
+		wrapping = TextWrapNormal
+	}
+
+	switch style.WhiteSpace {
+	case WhiteSpaceNormal:
+		content, _ = textMeasurer.ProcessWhiteSpace(content, text.WhiteSpaceNormal)
+		return content, wrapping
+	case WhiteSpaceNoWrap:
+		content, _ = textMeasurer.ProcessWhiteSpace(content, text.WhiteSpaceNoWrap)
+		return content, TextWrapNone
+	case WhiteSpacePre:
+		return content, TextWrapNone
+	case WhiteSpacePreWrap:
+		return content, wrapping
+	case WhiteSpacePreLine:
+		// Collapse spaces within each line but keep the line breaks
+		paragraphs := strings.Split(content, "\n")
+		for i, para := range paragraphs {
+			paragraphs[i], _ = textMeasurer.ProcessWhiteSpace(para, text.WhiteSpaceNormal)
+		}
+		return strings.Join(paragraphs, "\n"), wrapping
+	default:
+		return content, style.TextWrap
+	}
+}
+
+// clampLines drops lines beyond the box height or the style's line clamp.
+// The last visible line ends with an ellipsis when lines were dropped and
+// either a line clamp or an ellipsis overflow mode is set.
+func clampLines(lines []text.Line, w, h int, style *Style, ellipsis string) []text.Line {
+	maxLines := h
+	if style != nil && style.LineClamp > 0 && style.LineClamp < maxLines {
+		maxLines = style.LineClamp
+	}
+	if maxLines < 0 {
+		maxLines = 0
+	}
+	if len(lines) <= maxLines {
+		return lines
+	}
+
+	lines = lines[:maxLines]
+	if maxLines == 0 || style == nil || (style.LineClamp == 0 && style.TextOverflow == TextOverflowClip) {
+		return lines
+	}
+
+	last := lines[maxLines-1]
+	content := strings.TrimRight(last.Content, " ") + ellipsis
+	if textMeasurer.Width(content) > float64(w) {
+		content = textMeasurer.ElideEndWith(content, float64(w), ellipsis)
+	}
+	lines[maxLines-1] = text.Line{
+		Content: content,
+		Width:   textMeasurer.Width(content),
+	}
+	return lines
+}
+
+// ellipsis returns the mark for cut-off text, in ASCII without Unicode
+func (s *Screen) ellipsis() string {
+	if s.unicode {
+		return "…"
+	}
+	return "..."
+}
+
+// placedLine is a line of text positioned within a content box
+type placedLine struct {
+	graphemes []string // Grapheme clusters in visual order
+	width     int
+	col       int
+	row       int
+}
+
+// newGlyphPainter returns a function that yields the style for the glyph at
+// (col, row). Without a foreground gradient every glyph shares the node style.
+// Gradients span the bounding box of the placed lines, clipped to the content
+// box, and collapse to their midpoint color in 16-color and no-color modes.
+func (s *Screen) newGlyphPainter(lines []placedLine, x, w int, style *Style) func(col, row int) *Style {
+	if style == nil || style.ForegroundGradient == nil || len(lines) == 0 {
+		return func(int, int) *Style { return style }
+	}
+
+	// Bounding box of the painted glyphs
+	minCol, maxCol := lines[0].col, lines[0].col+lines[0].width
+	for _, line := range lines[1:] {
+		if line.col < minCol {
+			minCol = line.col
+		}
+		if line.col+line.width > maxCol {
+			maxCol = line.col + line.width
+		}
+	}
+	if minCol < x {
+		minCol = x
+	}
+	if maxCol > x+w {
+		maxCol = x + w
+	}
+	minRow := lines[0].row
+	spanH := lines[len(lines)-1].row - minRow + 1
+
+	return s.newGradientPainter(minCol, minRow, maxCol-minCol, spanH, style)
+}
+
+// newGradientPainter returns a function that colors glyphs with the style's
+// foreground gradient spread over the given box
+func (s *Screen) newGradientPainter(x, y, w, h int, style *Style) func(col, row int) *Style {
+	gradient := style.ForegroundGradient
+	if s.renderer.ColorMode == ColorModeNone || s.renderer.ColorMode == ColorMode16 {
+		solid := *style
+		c := gradient.At(0.5)
+		solid.Foreground = &c
+		return func(int, int) *Style { return &solid }
+	}
+
+	return func(col, row int) *Style {
+		cellStyle := *style
+		c := gradient.At(gradient.position(col-x, row-y, w, h))
+		cellStyle.Foreground = &c
+		return &cellStyle
+	}
+}
+
+// String converts the screen buffer to a string with ANSI codes. In plain
+// output mode it returns the rows as text without escape sequences or
+// trailing spaces.
 func (s *Screen) String() string {
+	if s.output == OutputModePlain {
+		return strings.Join(s.Lines(), "\n")
+	}
+
 	var buf strings.Builder
 
 	// Start with cursor at top-left
@@ -378,21 +806,7 @@ func (s *Screen) String() string {
 
 	var lastStyle *Style
 	for y := 0; y < s.Height; y++ {
-		for x := 0; x < s.Width; x++ {
-			cell := s.Cells[y][x]
-
-			// Only output ANSI codes when style changes
-			if !stylesEqual(cell.Style, lastStyle) {
-				buf.WriteString(s.renderer.Reset())
-				if cell.Style != nil {
-					buf.WriteString(s.renderer.RenderStyle(cell.Style))
-				}
-				lastStyle = cell.Style
-			}
-
-			// Output the cell content (can be a single character or emoji sequence)
-			buf.WriteString(cell.Content)
-		}
+		lastStyle = s.writeRow(&buf, y, lastStyle)
 		if y < s.Height-1 {
 			buf.WriteString("\n")
 		}
@@ -402,6 +816,84 @@ func (s *Screen) String() string {
 	return buf.String()
 }
 
+// Lines returns each row of the screen with its ANSI styling and without
+// cursor positioning. Every row starts unstyled and ends with a reset, so
+// rows can be written anywhere on the terminal independently. In plain
+// output mode rows carry no styling and no trailing spaces.
+func (s *Screen) Lines() []string {
+	lines := make([]string, s.Height)
+	for y := 0; y < s.Height; y++ {
+		var buf strings.Builder
+		if s.output == OutputModePlain {
+			s.writePlainRow(&buf, y)
+			lines[y] = strings.TrimRight(buf.String(), " ")
+			continue
+		}
+		if s.writeRow(&buf, y, nil) != nil {
+			buf.WriteString(s.renderer.Reset())
+		}
+		lines[y] = buf.String()
+	}
+	return lines
+}
+
+// writeRow writes the cells of a row, emitting style codes only when the
+// style changes from lastStyle, and returns the style in effect at the end
+func (s *Screen) writeRow(buf *strings.Builder, y int, lastStyle *Style) *Style {
+	for x := 0; x < s.Width; x++ {
+		cell := s.Cells[y][x]
+
+		// Only output ANSI codes when style changes
+		if !stylesEqual(cell.Style, lastStyle) {
+			buf.WriteString(s.renderer.Reset())
+			if cell.Style != nil {
+				buf.WriteString(s.renderer.RenderStyle(cell.Style))
+			}
+			lastStyle = cell.Style
+		}
+
+		// Output the cell content (can be a single character or emoji sequence)
+		// The terminal advances over a wide glyph's continuation cells itself
+		content, width := s.cellGlyph(x, y)
+		buf.WriteString(content)
+		x += width - 1
+	}
+	return lastStyle
+}
+
+// writePlainRow writes the glyphs of a row without styling
+func (s *Screen) writePlainRow(buf *strings.Builder, y int) {
+	for x := 0; x < s.Width; x++ {
+		content, width := s.cellGlyph(x, y)
+		buf.WriteString(content)
+		x += width - 1
+	}
+}
+
+// cellGlyph returns the content to write for a cell and the number of
+// columns it covers. A wide glyph whose continuation cells were overwritten
+// is replaced by a space so the rest of the row stays aligned.
+func (s *Screen) cellGlyph(x, y int) (string, int) {
+	content := s.Cells[y][x].Content
+	if len(content) <= 1 {
+		return content, 1
+	}
+
+	width := int(textMeasurer.Width(content))
+	if width <= 1 {
+		return content, 1
+	}
+	if x+width > s.Width {
+		return " ", 1
+	}
+	for i := 1; i < width; i++ {
+		if s.Cells[y][x+i].Content != " " {
+			return " ", 1
+		}
+	}
+	return content, width
+}
+
 // stylesEqual checks if two styles are equal
 func stylesEqual(a, b *Style) bool {
 	if a == nil && b == nil {
@@ -422,6 +914,28 @@ type StyledNode struct {
 	Style    *Style
 	Content  string
 	Children []*StyledNode
+
+	// ScrollX and ScrollY shift content and children left and up when
+	// Style.Overflow is OverflowScroll
+	ScrollX int
+	ScrollY int
+
+	// ID identifies the node across rebuilds of the tree, so a rebuilt
+	// node keeps focus (see FocusManager.SetRoot)
+	ID string
+
+	// Focusable nodes can take keyboard focus. TabIndex orders them for
+	// Tab: positive values first, then zero in tree order; negative values
+	// are skipped by Tab.
+	Focusable bool
+	TabIndex  int
+
+	// Cursor places the terminal cursor while the node has focus
+	Cursor *Cursor
+
+	// listeners are the event handlers added with On and OnCapture
+	listeners []listener
+	focused   bool
 }
 
 // NewStyledNode creates a new styled node
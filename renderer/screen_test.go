@@ -34,6 +34,24 @@ func TestNewScreen(t *testing.T) {
 	}
 }
 
+func TestNewScreenDetectsOnce(t *testing.T) {
+	first := NewScreen(1, 1)
+
+	// Later screens keep the first detection rather than reading the
+	// environment again
+	t.Setenv("TERM", "dumb")
+	t.Setenv("LC_ALL", "C")
+	second := NewScreen(1, 1)
+	if second.unicode != first.unicode || second.output != first.output {
+		t.Errorf("Expected unicode %v and output %v, got %v and %v", first.unicode, first.output, second.unicode, second.output)
+	}
+
+	second.SetOutputMode(OutputModePlain)
+	if second.output != OutputModePlain {
+		t.Error("Expected SetOutputMode to override detection")
+	}
+}
+
 func TestScreenResize(t *testing.T) {
 	s := NewScreen(80, 24)
 	s.Resize(100, 30)
@@ -276,6 +294,7 @@ func TestTextWrapNormal(t *testing.T) {
 
 func TestTextOverflowEllipsis(t *testing.T) {
 	s := NewScreen(10, 3)
+	s.SetUnicode(true)
 
 	node := &layout.Node{
 		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 3},
@@ -305,6 +324,7 @@ func TestTextOverflowEllipsis(t *testing.T) {
 
 func TestRenderWithBorder(t *testing.T) {
 	s := NewScreen(10, 5)
+	s.SetUnicode(true)
 
 	node := &layout.Node{
 		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 5},
@@ -363,3 +383,210 @@ func TestStringOutput(t *testing.T) {
 		t.Error("Output should contain newline")
 	}
 }
+
+func TestVerticalAlign(t *testing.T) {
+	tests := []struct {
+		name     string
+		align    VerticalAlign
+		expected int
+	}{
+		{"Top", VerticalAlignTop, 0},
+		{"Middle", VerticalAlignMiddle, 2},
+		{"Bottom", VerticalAlignBottom, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScreen(10, 5)
+
+			node := &layout.Node{
+				Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 5},
+			}
+			styledNode := NewStyledNode(node, NewStyle().WithVerticalAlign(tt.align))
+			styledNode.Content = "Hi"
+
+			s.Render(styledNode)
+
+			if s.Cells[tt.expected][0].Content != "H" {
+				t.Errorf("Expected text on row %d", tt.expected)
+			}
+		})
+	}
+}
+
+func TestLineClamp(t *testing.T) {
+	s := NewScreen(10, 5)
+	s.SetUnicode(true)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 5},
+	}
+	style := &Style{TextWrap: TextWrapNormal, LineClamp: 2}
+	styledNode := NewStyledNode(node, style)
+	styledNode.Content = "one two three four five six"
+
+	s.Render(styledNode)
+
+	if !strings.Contains(rowString(s, 1), "…") {
+		t.Errorf("Expected ellipsis on last clamped line, got %q", rowString(s, 1))
+	}
+	if strings.TrimSpace(rowString(s, 2)) != "" {
+		t.Errorf("Expected row 2 to be empty, got %q", rowString(s, 2))
+	}
+}
+
+func TestLineClampASCII(t *testing.T) {
+	s := NewScreen(10, 5)
+	s.SetUnicode(false)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 5},
+	}
+	style := &Style{TextWrap: TextWrapNormal, LineClamp: 2}
+	styledNode := NewStyledNode(node, style)
+	styledNode.Content = "one two three four five six"
+
+	s.Render(styledNode)
+
+	got := rowString(s, 1)
+	if !strings.Contains(got, "...") || strings.Contains(got, "…") {
+		t.Errorf("Expected ASCII ellipsis on last clamped line, got %q", got)
+	}
+}
+
+func TestVerticalOverflowEllipsis(t *testing.T) {
+	s := NewScreen(10, 2)
+	s.SetUnicode(true)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 2},
+	}
+	style := &Style{TextOverflow: TextOverflowEllipsis}
+	styledNode := NewStyledNode(node, style)
+	styledNode.Content = "a\nb\nc"
+
+	s.Render(styledNode)
+
+	if got := rowString(s, 1); !strings.HasPrefix(got, "b…") {
+		t.Errorf("Expected ellipsis after last visible line, got %q", got)
+	}
+}
+
+func TestScrolledContent(t *testing.T) {
+	s := NewScreen(12, 3)
+	s.SetUnicode(true)
+
+	node := NewStyledNode(&layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 6, Height: 3},
+	}, &Style{Overflow: OverflowScroll, WhiteSpace: WhiteSpacePre})
+	node.Content = "abcdefghij\n0123456789\nklmnopqrst"
+	node.ScrollX, node.ScrollY = 4, 1
+
+	s.Render(node)
+
+	for y, expected := range []string{"456789", "opqrst", ""} {
+		if got := strings.TrimRight(rowString(s, y), " "); got != expected {
+			t.Errorf("Row %d: expected %q, got %q", y, expected, got)
+		}
+	}
+}
+
+func TestTabStops(t *testing.T) {
+	s := NewScreen(12, 1)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 12, Height: 1},
+	}
+	styledNode := NewStyledNode(node, NewStyle().WithTabSize(4))
+	styledNode.Content = "ab\tc\td"
+
+	s.Render(styledNode)
+
+	if got := rowString(s, 0); got != "ab  c   d   " {
+		t.Errorf("Expected tabs expanded to stops of 4, got %q", got)
+	}
+}
+
+func TestWhiteSpaceModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     WhiteSpace
+		content  string
+		expected []string
+	}{
+		{"Normal", WhiteSpaceNormal, "a   b\nc", []string{"a b c     "}},
+		{"NoWrap", WhiteSpaceNoWrap, "one two three", []string{"one two th", "          "}},
+		{"Pre", WhiteSpacePre, "a   b\n  c", []string{"a   b     ", "  c       "}},
+		{"PreLine", WhiteSpacePreLine, "  a   b  \nc", []string{"a b       ", "c         "}},
+		{"PreWrap", WhiteSpacePreWrap, "a   b", []string{"a   b     "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScreen(10, 2)
+
+			node := &layout.Node{
+				Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 2},
+			}
+			styledNode := NewStyledNode(node, NewStyle().WithWhiteSpace(tt.mode))
+			styledNode.Content = tt.content
+
+			s.Render(styledNode)
+
+			for row, want := range tt.expected {
+				if got := rowString(s, row); got != want {
+					t.Errorf("Row %d: expected %q, got %q", row, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTrimTrailingSpace(t *testing.T) {
+	s := NewScreen(10, 1)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 1},
+	}
+	style := &Style{TextAlign: TextAlignRight, TrimTrailingSpace: true}
+	styledNode := NewStyledNode(node, style)
+	styledNode.Content = "Hi   "
+
+	s.Render(styledNode)
+
+	if s.Cells[0][8].Content != "H" {
+		t.Errorf("Expected trailing spaces trimmed before right alignment, got %q", rowString(s, 0))
+	}
+}
+
+func TestStringSkipsWideContinuationCells(t *testing.T) {
+	s := NewScreen(4, 1)
+	s.setGlyph(0, 0, "漢", 2, nil)
+	s.SetCell(2, 0, "a", nil)
+	s.SetCell(3, 0, "b", nil)
+
+	if output := s.String(); !strings.Contains(output, "漢ab") {
+		t.Errorf("Expected wide glyph followed directly by %q, got %q", "ab", output)
+	}
+
+	// A continuation cell that was overwritten drops the wide glyph
+	s.SetCell(1, 0, "|", nil)
+	if output := s.String(); !strings.Contains(output, " |ab") {
+		t.Errorf("Expected overwritten wide glyph to become a space, got %q", output)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	rows := WrapText("hello 👍 world", 7)
+	if strings.Join(rows, "") != "hello 👍 world" {
+		t.Errorf("Expected the rows to join back into the text, got %q", rows)
+	}
+	for _, row := range rows {
+		if TextWidth(row) > 7 {
+			t.Errorf("Expected rows of at most 7 cells, got %q", row)
+		}
+	}
+	if rows := WrapText("", 5); len(rows) != 1 || rows[0] != "" {
+		t.Errorf("Expected one empty row for empty text, got %q", rows)
+	}
+}
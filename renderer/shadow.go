@@ -0,0 +1,121 @@
+package renderer
+
+import "github.com/SCKelemen/color"
+
+// Shadow defines a drop shadow painted to the bottom-right of a node
+type Shadow struct {
+	OffsetX int // Columns to shift the shadow right
+	OffsetY int // Rows to shift the shadow down
+	Spread  int // Cells to grow the shadow on every side
+
+	// Color fills the background of cells that have no background beneath
+	Color *color.Color
+
+	// Darken darkens the colors already beneath the shadow (0.0 to 1.0)
+	Darken float64
+
+	// Char is painted on blank cells when no color can be shown
+	Char rune
+}
+
+// DefaultShadowChar is the shade character used for shadows without color
+const DefaultShadowChar = '░'
+
+// NewShadow creates a shadow offset one row down and two columns right,
+// which looks square in most terminal fonts
+func NewShadow() *Shadow {
+	return &Shadow{
+		OffsetX: 2,
+		OffsetY: 1,
+		Darken:  0.5,
+		Char:    DefaultShadowChar,
+	}
+}
+
+// WithOffset sets the shadow offset
+func (sh *Shadow) WithOffset(x, y int) *Shadow {
+	sh.OffsetX = x
+	sh.OffsetY = y
+	return sh
+}
+
+// WithSpread sets the shadow spread
+func (sh *Shadow) WithSpread(spread int) *Shadow {
+	sh.Spread = spread
+	return sh
+}
+
+// WithColor sets the shadow color
+func (sh *Shadow) WithColor(c *color.Color) *Shadow {
+	sh.Color = c
+	return sh
+}
+
+// WithDarken sets the darkening factor applied to content beneath the shadow
+func (sh *Shadow) WithDarken(amount float64) *Shadow {
+	sh.Darken = amount
+	return sh
+}
+
+// renderShadow composites a node's shadow over the cells already on screen.
+// Cells covered by the node itself are skipped since the node paints over them.
+func (s *Screen) renderShadow(x, y, w, h int, shadow *Shadow) {
+	if shadow == nil || w <= 0 || h <= 0 {
+		return
+	}
+
+	sx := x + shadow.OffsetX - shadow.Spread
+	sy := y + shadow.OffsetY - shadow.Spread
+	sw := w + 2*shadow.Spread
+	sh := h + 2*shadow.Spread
+
+	shade := shadow.Char
+	if shade == 0 {
+		shade = DefaultShadowChar
+	}
+	noColor := s.renderer.ColorMode == ColorModeNone
+
+	for row := sy; row < sy+sh; row++ {
+		if row < 0 || row >= s.Height {
+			continue
+		}
+		for col := sx; col < sx+sw; col++ {
+			if col < 0 || col >= s.Width {
+				continue
+			}
+			if col >= x && col < x+w && row >= y && row < y+h {
+				continue
+			}
+
+			cell := s.Cells[row][col]
+			shaded := &Style{}
+			if cell.Style != nil {
+				*shaded = *cell.Style
+			}
+
+			if shadow.Darken > 0 {
+				if shaded.Background != nil {
+					bg := color.Darken(*shaded.Background, shadow.Darken)
+					shaded.Background = &bg
+				}
+				if shaded.Foreground != nil {
+					fg := color.Darken(*shaded.Foreground, shadow.Darken)
+					shaded.Foreground = &fg
+				}
+			}
+			if shaded.Background == nil && shadow.Color != nil {
+				shaded.Background = shadow.Color
+			}
+
+			content := cell.Content
+			if content == " " && (noColor || shaded.Background == nil) {
+				content = string(shade)
+				if shaded.Foreground == nil {
+					shaded.Foreground = shadow.Color
+				}
+			}
+
+			s.SetCell(col, row, content, shaded)
+		}
+	}
+}
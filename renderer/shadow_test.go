@@ -0,0 +1,76 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/color"
+	"github.com/SCKelemen/layout"
+)
+
+func TestNewShadow(t *testing.T) {
+	sh := NewShadow()
+
+	if sh.OffsetX != 2 || sh.OffsetY != 1 {
+		t.Errorf("Expected offset (2, 1), got (%d, %d)", sh.OffsetX, sh.OffsetY)
+	}
+	if sh.Char != DefaultShadowChar {
+		t.Errorf("Expected default shadow char, got %q", sh.Char)
+	}
+}
+
+func TestRenderShadowShadeChars(t *testing.T) {
+	s := NewScreen(10, 5)
+	s.SetColorMode(ColorModeNone)
+
+	node := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 4, Height: 2},
+	}
+	style := NewStyle().WithShadow(NewShadow().WithOffset(1, 1))
+	s.Render(NewStyledNode(node, style))
+
+	// Shadow cells to the bottom-right of the node
+	if s.Cells[1][4].Content != "░" {
+		t.Errorf("Expected shade char right of node, got %q", s.Cells[1][4].Content)
+	}
+	if s.Cells[2][1].Content != "░" {
+		t.Errorf("Expected shade char below node, got %q", s.Cells[2][1].Content)
+	}
+
+	// The node's own area is not shaded
+	if s.Cells[1][1].Content != " " {
+		t.Errorf("Expected node area to be unshaded, got %q", s.Cells[1][1].Content)
+	}
+
+	// Nothing above or left of the offset
+	if s.Cells[2][0].Content != " " {
+		t.Errorf("Expected no shadow at left edge, got %q", s.Cells[2][0].Content)
+	}
+}
+
+func TestRenderShadowDarkensBackground(t *testing.T) {
+	s := NewScreen(10, 5)
+	s.SetColorMode(ColorModeTrueColor)
+
+	white, _ := color.ParseColor("#FFFFFF")
+	root := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 10, Height: 5},
+	}
+	card := &layout.Node{
+		Rect: layout.Rect{X: 0, Y: 0, Width: 4, Height: 2},
+	}
+	rootStyled := NewStyledNode(root, NewStyle().WithBackground(&white))
+	rootStyled.AddChild(NewStyledNode(card, NewStyle().WithShadow(NewShadow())))
+
+	s.Render(rootStyled)
+
+	cell := s.Cells[2][3]
+	if cell.Content != " " {
+		t.Errorf("Expected background content to be kept, got %q", cell.Content)
+	}
+	if cell.Style == nil || cell.Style.Background == nil {
+		t.Fatal("Expected shadow cell to keep a background")
+	}
+	if got := color.RGBToHex(*cell.Style.Background); got == "#ffffff" {
+		t.Error("Expected background beneath shadow to be darkened")
+	}
+}
@@ -32,6 +32,50 @@ const (
 	TextAlignJustify               // Justified (with Knuth-Plass)
 )
 
+// WhiteSpace defines how spaces, tabs and line breaks in content are handled
+type WhiteSpace int
+
+const (
+	WhiteSpaceAuto    WhiteSpace = iota // Keep spaces and line breaks, wrap per TextWrap (default)
+	WhiteSpaceNormal                    // Collapse spaces and line breaks, wrap
+	WhiteSpacePre                       // Preserve spaces and line breaks, never wrap
+	WhiteSpacePreWrap                   // Preserve spaces and line breaks, wrap
+	WhiteSpacePreLine                   // Collapse spaces, preserve line breaks, wrap
+	WhiteSpaceNoWrap                    // Collapse spaces and line breaks, never wrap
+)
+
+// DefaultTabSize is the tab stop interval used when Style.TabSize is zero
+const DefaultTabSize = 8
+
+// VerticalAlign defines vertical placement of content inside a taller box
+type VerticalAlign int
+
+const (
+	VerticalAlignTop    VerticalAlign = iota // Top-aligned (default)
+	VerticalAlignMiddle                      // Centered vertically
+	VerticalAlignBottom                      // Bottom-aligned
+)
+
+// Direction defines the base direction of text, used by the Unicode
+// Bidirectional Algorithm to order mixed left-to-right and right-to-left runs
+type Direction int
+
+const (
+	DirectionLTR  Direction = iota // Left-to-right (default)
+	DirectionRTL                   // Right-to-left
+	DirectionAuto                  // From the first strong character of each paragraph
+)
+
+// Overflow defines whether a node's content and children are clipped to
+// the box inside its border
+type Overflow int
+
+const (
+	OverflowVisible Overflow = iota // Paint outside the box (default)
+	OverflowHidden                  // Clip to the box
+	OverflowScroll                  // Clip, and offset by the node's ScrollX and ScrollY
+)
+
 // Style defines visual attributes without sizing properties.
 // Sizing and layout are handled by the layout engine.
 type Style struct {
@@ -39,6 +83,9 @@ type Style struct {
 	Foreground *color.Color
 	Background *color.Color
 
+	// ForegroundGradient colors text per grapheme, overriding Foreground
+	ForegroundGradient *Gradient
+
 	// Text attributes
 	Bold          bool
 	Italic        bool
@@ -49,13 +96,40 @@ type Style struct {
 	Reverse       bool
 
 	// Text layout
-	TextWrap     TextWrap     // How text should wrap
-	TextAlign    TextAlign    // Horizontal alignment
-	TextOverflow TextOverflow // Overflow handling
+	TextWrap      TextWrap      // How text should wrap
+	TextAlign     TextAlign     // Horizontal alignment, relative to Direction
+	TextOverflow  TextOverflow  // Overflow handling
+	VerticalAlign VerticalAlign // Vertical alignment
+	LineClamp     int           // Maximum number of lines (0 = no limit)
+	Direction     Direction     // Base text direction
+
+	// White space handling
+	WhiteSpace        WhiteSpace // How spaces and line breaks are handled
+	TabSize           int        // Columns between tab stops (0 = DefaultTabSize)
+	TrimTrailingSpace bool       // Remove trailing spaces from each line
 
 	// Borders
 	Border      *BorderStyle
 	BorderColor *color.Color
+
+	// BorderCollapse merges the borders of touching children, and of
+	// children touching this node's border, into single lines with junctions
+	BorderCollapse bool
+
+	// Separator lines drawn in the gaps between children
+	ColumnRule *Rule
+	RowRule    *Rule
+
+	// Elevation
+	Shadow *Shadow
+
+	// Clipping of content and children
+	Overflow Overflow
+
+	// FocusStyle replaces the style while the node has focus. Without it
+	// focused nodes get an automatic focus ring: a thick border, or reverse
+	// video for borderless nodes.
+	FocusStyle *Style
 }
 
 // BorderStyle defines which borders to render
@@ -67,6 +141,21 @@ type BorderStyle struct {
 
 	// Border characters
 	Chars BorderChars
+
+	// Labels embedded in the top and bottom borders, elided when too long
+	Title       string
+	TitleAlign  TextAlign
+	Footer      string
+	FooterAlign TextAlign
+
+	// Per-side colors override Style.BorderColor
+	TopColor    *color.Color
+	RightColor  *color.Color
+	BottomColor *color.Color
+	LeftColor   *color.Color
+
+	// Gradient colors the border along its box, overriding all other colors
+	Gradient *Gradient
 }
 
 // BorderChars defines the characters used for borders
@@ -77,6 +166,11 @@ type BorderChars struct {
 	BottomRight rune
 	Horizontal  rune
 	Vertical    rune
+
+	// Optional characters for the bottom and right sides, for styles whose
+	// sides are not symmetric. Horizontal and Vertical are used when zero.
+	HorizontalBottom rune
+	VerticalRight    rune
 }
 
 // Predefined border styles
@@ -116,8 +210,84 @@ var (
 		Horizontal:  '─',
 		Vertical:    '│',
 	}
+
+	DashedBorder = BorderChars{
+		TopLeft:     '┌',
+		TopRight:    '┐',
+		BottomLeft:  '└',
+		BottomRight: '┘',
+		Horizontal:  '╌',
+		Vertical:    '╎',
+	}
+
+	DottedBorder = BorderChars{
+		TopLeft:     '┌',
+		TopRight:    '┐',
+		BottomLeft:  '└',
+		BottomRight: '┘',
+		Horizontal:  '┈',
+		Vertical:    '┊',
+	}
+
+	BlockBorder = BorderChars{
+		TopLeft:     '█',
+		TopRight:    '█',
+		BottomLeft:  '█',
+		BottomRight: '█',
+		Horizontal:  '█',
+		Vertical:    '█',
+	}
+
+	// InnerHalfBlockBorder hugs the content with half blocks
+	InnerHalfBlockBorder = BorderChars{
+		TopLeft:          '▗',
+		TopRight:         '▖',
+		BottomLeft:       '▝',
+		BottomRight:      '▘',
+		Horizontal:       '▄',
+		Vertical:         '▐',
+		HorizontalBottom: '▀',
+		VerticalRight:    '▌',
+	}
+
+	// OuterHalfBlockBorder draws half blocks on the outer edge of the cells
+	OuterHalfBlockBorder = BorderChars{
+		TopLeft:          '▛',
+		TopRight:         '▜',
+		BottomLeft:       '▙',
+		BottomRight:      '▟',
+		Horizontal:       '▀',
+		Vertical:         '▌',
+		HorizontalBottom: '▄',
+		VerticalRight:    '▐',
+	}
+
+	ASCIIBorder = BorderChars{
+		TopLeft:     '+',
+		TopRight:    '+',
+		BottomLeft:  '+',
+		BottomRight: '+',
+		Horizontal:  '-',
+		Vertical:    '|',
+	}
 )
 
+// bottom returns the character for the bottom side
+func (c BorderChars) bottom() rune {
+	if c.HorizontalBottom != 0 {
+		return c.HorizontalBottom
+	}
+	return c.Horizontal
+}
+
+// right returns the character for the right side
+func (c BorderChars) right() rune {
+	if c.VerticalRight != 0 {
+		return c.VerticalRight
+	}
+	return c.Vertical
+}
+
 // NewStyle creates a new empty style
 func NewStyle() *Style {
 	return &Style{}
@@ -135,6 +305,12 @@ func (s *Style) WithBackground(c *color.Color) *Style {
 	return s
 }
 
+// WithForegroundGradient sets a gradient foreground for text content
+func (s *Style) WithForegroundGradient(g *Gradient) *Style {
+	s.ForegroundGradient = g
+	return s
+}
+
 // WithBold sets bold text
 func (s *Style) WithBold(bold bool) *Style {
 	s.Bold = bold
@@ -171,6 +347,109 @@ func (s *Style) WithBorderColor(c *color.Color) *Style {
 	return s
 }
 
+// WithWhiteSpace sets the white space handling mode
+func (s *Style) WithWhiteSpace(mode WhiteSpace) *Style {
+	s.WhiteSpace = mode
+	return s
+}
+
+// WithTabSize sets the number of columns between tab stops
+func (s *Style) WithTabSize(size int) *Style {
+	s.TabSize = size
+	return s
+}
+
+// WithTrimTrailingSpace sets whether trailing spaces are removed from lines
+func (s *Style) WithTrimTrailingSpace(trim bool) *Style {
+	s.TrimTrailingSpace = trim
+	return s
+}
+
+// WithVerticalAlign sets the vertical alignment of content
+func (s *Style) WithVerticalAlign(align VerticalAlign) *Style {
+	s.VerticalAlign = align
+	return s
+}
+
+// WithLineClamp limits content to a maximum number of lines, ending the
+// last visible line with an ellipsis when text is cut off
+func (s *Style) WithLineClamp(lines int) *Style {
+	s.LineClamp = lines
+	return s
+}
+
+// WithDirection sets the base text direction. In right-to-left text,
+// TextAlignLeft and TextAlignRight align to the start (right) and end (left).
+func (s *Style) WithDirection(dir Direction) *Style {
+	s.Direction = dir
+	return s
+}
+
+// WithShadow sets a drop shadow
+func (s *Style) WithShadow(shadow *Shadow) *Style {
+	s.Shadow = shadow
+	return s
+}
+
+// WithOverflow sets whether content and children are clipped or scrolled
+func (s *Style) WithOverflow(overflow Overflow) *Style {
+	s.Overflow = overflow
+	return s
+}
+
+// WithFocusStyle sets the style used while the node has focus
+func (s *Style) WithFocusStyle(focus *Style) *Style {
+	s.FocusStyle = focus
+	return s
+}
+
+// WithBorderCollapse sets whether children's touching borders merge
+func (s *Style) WithBorderCollapse(collapse bool) *Style {
+	s.BorderCollapse = collapse
+	return s
+}
+
+// WithColumnRule draws vertical separators between side-by-side children
+func (s *Style) WithColumnRule(rule *Rule) *Style {
+	s.ColumnRule = rule
+	return s
+}
+
+// WithRowRule draws horizontal separators between stacked children
+func (s *Style) WithRowRule(rule *Rule) *Style {
+	s.RowRule = rule
+	return s
+}
+
+// WithBorderTitle embeds a title in the top border.
+// Has no effect until a border is set.
+func (s *Style) WithBorderTitle(title string, align TextAlign) *Style {
+	if s.Border != nil {
+		s.Border.Title = title
+		s.Border.TitleAlign = align
+	}
+	return s
+}
+
+// WithBorderFooter embeds a label in the bottom border.
+// Has no effect until a border is set.
+func (s *Style) WithBorderFooter(footer string, align TextAlign) *Style {
+	if s.Border != nil {
+		s.Border.Footer = footer
+		s.Border.FooterAlign = align
+	}
+	return s
+}
+
+// WithBorderGradient colors the border with a gradient.
+// Has no effect until a border is set.
+func (s *Style) WithBorderGradient(g *Gradient) *Style {
+	if s.Border != nil {
+		s.Border.Gradient = g
+	}
+	return s
+}
+
 // WithTextOverflow sets the text overflow behavior
 func (s *Style) WithTextOverflow(overflow TextOverflow) *Style {
 	s.TextOverflow = overflow
@@ -131,6 +131,12 @@ func TestBorderChars(t *testing.T) {
 		{"DoubleBorder", DoubleBorder},
 		{"ThickBorder", ThickBorder},
 		{"NormalBorder", NormalBorder},
+		{"DashedBorder", DashedBorder},
+		{"DottedBorder", DottedBorder},
+		{"BlockBorder", BlockBorder},
+		{"InnerHalfBlockBorder", InnerHalfBlockBorder},
+		{"OuterHalfBlockBorder", OuterHalfBlockBorder},
+		{"ASCIIBorder", ASCIIBorder},
 	}
 
 	for _, tt := range tests {
@@ -209,3 +215,22 @@ func TestTextAlignConstants(t *testing.T) {
 		seen[align] = true
 	}
 }
+
+func TestWhiteSpaceConstants(t *testing.T) {
+	modes := []WhiteSpace{
+		WhiteSpaceAuto,
+		WhiteSpaceNormal,
+		WhiteSpacePre,
+		WhiteSpacePreWrap,
+		WhiteSpacePreLine,
+		WhiteSpaceNoWrap,
+	}
+
+	seen := make(map[WhiteSpace]bool)
+	for _, mode := range modes {
+		if seen[mode] {
+			t.Errorf("Duplicate WhiteSpace value: %d", mode)
+		}
+		seen[mode] = true
+	}
+}
@@ -0,0 +1,267 @@
+package renderer
+
+import (
+	"strings"
+
+	"github.com/SCKelemen/layout"
+	"github.com/SCKelemen/text"
+)
+
+// verticalGlyph is a grapheme cluster set along a column. Terminals cannot
+// rotate glyphs, so a rotated glyph is drawn as is but advances along the
+// column by its cell width, as its width becomes its height when rotated.
+// Upright glyphs always advance one row.
+type verticalGlyph struct {
+	content string
+	width   int  // Cells across the column
+	advance int  // Rows along the column
+	upright bool // Set upright (CJK in vertical modes) rather than rotated
+}
+
+// verticalColumn is one line of vertical text
+type verticalColumn struct {
+	glyphs []verticalGlyph
+	length int // Rows along the column
+	width  int // Cells across the column
+}
+
+// renderVerticalText renders text in a vertical writing mode. Each line of
+// text becomes a column read top to bottom, or bottom to top in sideways-lr.
+// Columns progress right to left in vertical-rl and sideways-rl, and left to
+// right in vertical-lr and sideways-lr. In the vertical modes CJK is set
+// upright and other scripts are rotated following UAX #50; in the sideways
+// modes every glyph is rotated. Text wraps when a column reaches the bottom
+// of the box, and TextAlign places each column along its length.
+func (s *Screen) renderVerticalText(x, y, w, h int, content string, style *Style, mode layout.WritingMode) {
+	if content == "" || w <= 0 || h <= 0 {
+		return
+	}
+	if style == nil {
+		style = &Style{}
+	}
+
+	content, wrapMode := processWhiteSpace(content, style)
+	sideways := mode.IsSideways()
+
+	var columns []verticalColumn
+	for _, para := range strings.Split(content, "\n") {
+		glyphs := verticalGlyphs(para, sideways)
+		if wrapMode == TextWrapNone {
+			columns = append(columns, newVerticalColumn(glyphs))
+			continue
+		}
+		for _, glyphs := range wrapVertical(glyphs, h) {
+			columns = append(columns, newVerticalColumn(glyphs))
+		}
+	}
+
+	for i, column := range columns {
+		if style.TrimTrailingSpace {
+			column = newVerticalColumn(trimVerticalSpace(column.glyphs))
+		}
+		if column.length > h && style.TextOverflow != TextOverflowClip {
+			column = endVerticalColumn(column, h, s.verticalEllipsis())
+		}
+		columns[i] = column
+	}
+
+	columns = clampColumns(columns, w, h, style, s.verticalEllipsis())
+
+	paint := func(int, int) *Style { return style }
+	if style.ForegroundGradient != nil {
+		paint = s.newGradientPainter(x, y, w, h, style)
+	}
+
+	col := x
+	if mode.IsRightToLeft() {
+		col = x + w
+	}
+	for _, column := range columns {
+		if mode.IsRightToLeft() {
+			col -= column.width
+		}
+
+		// Offset along the column from its start
+		offset := 0
+		switch style.TextAlign {
+		case TextAlignCenter:
+			offset = (h - column.length) / 2
+		case TextAlignRight:
+			offset = h - column.length
+		}
+
+		pos := offset
+		for _, g := range column.glyphs {
+			row := y + pos
+			if mode == layout.WritingModeSidewaysLR {
+				row = y + h - 1 - pos
+			}
+			pos += g.advance
+
+			if row < y || row >= y+h || row < 0 || row >= s.Height {
+				continue
+			}
+			if col < max(x, 0) || col+g.width > x+w || col+g.width > s.Width {
+				continue
+			}
+
+			s.setGlyph(col, row, g.content, g.width, paint(col, row))
+		}
+
+		if !mode.IsRightToLeft() {
+			col += column.width
+		}
+	}
+}
+
+// verticalGlyphs splits a line into glyphs oriented for vertical text
+func verticalGlyphs(line string, sideways bool) []verticalGlyph {
+	orientation := text.VerticalTextStyle{TextOrientation: text.TextOrientationMixed}
+	if sideways {
+		orientation.TextOrientation = text.TextOrientationSideways
+	}
+
+	var glyphs []verticalGlyph
+	for _, g := range textMeasurer.Graphemes(line) {
+		width := int(textMeasurer.Width(g))
+		if width == 0 {
+			continue
+		}
+
+		upright := false
+		for _, r := range g {
+			upright = textMeasurer.IsUpright(r, orientation)
+			break
+		}
+
+		advance := width
+		if upright {
+			advance = 1
+		}
+		glyphs = append(glyphs, verticalGlyph{
+			content: g,
+			width:   width,
+			advance: advance,
+			upright: upright,
+		})
+	}
+	return glyphs
+}
+
+// newVerticalColumn measures a run of glyphs. Empty columns still take one
+// cell, like blank lines in horizontal text.
+func newVerticalColumn(glyphs []verticalGlyph) verticalColumn {
+	column := verticalColumn{glyphs: glyphs, width: 1}
+	for _, g := range glyphs {
+		column.length += g.advance
+		column.width = max(column.width, g.width)
+	}
+	return column
+}
+
+// wrapVertical breaks glyphs into columns no longer than h rows. Upright
+// text may break between any two glyphs; rotated runs break after the last
+// space so words stay in one column where they fit.
+func wrapVertical(glyphs []verticalGlyph, h int) [][]verticalGlyph {
+	var columns [][]verticalGlyph
+	var current []verticalGlyph
+	length, lastBreak := 0, 0
+
+	for _, g := range glyphs {
+		for length+g.advance > h && len(current) > 0 {
+			var next []verticalGlyph
+			if !g.upright && g.content != " " && lastBreak > 0 {
+				next = append(next, current[lastBreak:]...)
+				current = current[:lastBreak]
+			}
+			columns = append(columns, trimVerticalSpace(current))
+
+			current, length, lastBreak = next, 0, 0
+			for _, n := range next {
+				length += n.advance
+			}
+		}
+
+		// Spaces at a break are dropped rather than starting the next column
+		if len(current) == 0 && len(columns) > 0 && g.content == " " {
+			continue
+		}
+
+		current = append(current, g)
+		length += g.advance
+		if g.content == " " {
+			lastBreak = len(current)
+		}
+	}
+
+	if len(current) > 0 || len(columns) == 0 {
+		columns = append(columns, current)
+	}
+	return columns
+}
+
+// trimVerticalSpace removes spaces from the end of a column
+func trimVerticalSpace(glyphs []verticalGlyph) []verticalGlyph {
+	for len(glyphs) > 0 && glyphs[len(glyphs)-1].content == " " {
+		glyphs = glyphs[:len(glyphs)-1]
+	}
+	return glyphs
+}
+
+// verticalEllipsis returns the mark that ends columns cut off in vertical
+// writing modes, in ASCII without Unicode
+func (s *Screen) verticalEllipsis() string {
+	if s.unicode {
+		return "⋮"
+	}
+	return ":"
+}
+
+// endVerticalColumn cuts a column to fit h rows, ending it with an ellipsis
+func endVerticalColumn(column verticalColumn, h int, ellipsis string) verticalColumn {
+	glyphs := trimVerticalSpace(column.glyphs)
+	length := 0
+	for _, g := range glyphs {
+		length += g.advance
+	}
+	for len(glyphs) > 0 && length+1 > h {
+		length -= glyphs[len(glyphs)-1].advance
+		glyphs = glyphs[:len(glyphs)-1]
+	}
+
+	ended := make([]verticalGlyph, len(glyphs), len(glyphs)+1)
+	copy(ended, glyphs)
+	ended = append(ended, verticalGlyph{content: ellipsis, width: 1, advance: 1})
+	return newVerticalColumn(ended)
+}
+
+// clampColumns drops columns beyond the box width or the style's line
+// clamp. The last visible column ends with an ellipsis when columns were
+// dropped and either a line clamp or an ellipsis overflow mode is set.
+func clampColumns(columns []verticalColumn, w, h int, style *Style, ellipsis string) []verticalColumn {
+	maxColumns := len(columns)
+	if style.LineClamp > 0 && style.LineClamp < maxColumns {
+		maxColumns = style.LineClamp
+	}
+
+	used := 0
+	for i := 0; i < maxColumns; i++ {
+		if used+columns[i].width > w {
+			maxColumns = i
+			break
+		}
+		used += columns[i].width
+	}
+
+	if maxColumns == len(columns) {
+		return columns
+	}
+
+	columns = columns[:maxColumns]
+	if maxColumns == 0 || (style.LineClamp == 0 && style.TextOverflow == TextOverflowClip) {
+		return columns
+	}
+
+	columns[maxColumns-1] = endVerticalColumn(columns[maxColumns-1], h, ellipsis)
+	return columns
+}
@@ -0,0 +1,149 @@
+package renderer
+
+import (
+	"testing"
+
+	"github.com/SCKelemen/layout"
+)
+
+func renderVertical(width, height int, mode layout.WritingMode, content string, style *Style) *Screen {
+	s := NewScreen(width, height)
+	s.SetUnicode(true)
+	node := &layout.Node{
+		Rect:  layout.Rect{X: 0, Y: 0, Width: float64(width), Height: float64(height)},
+		Style: layout.Style{WritingMode: mode},
+	}
+	styled := NewStyledNode(node, style)
+	styled.Content = content
+	s.Render(styled)
+	return s
+}
+
+func TestVerticalWritingModes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     layout.WritingMode
+		width    int
+		height   int
+		content  string
+		expected []string
+	}{
+		{
+			name:    "VerticalRLUprightCJK",
+			mode:    layout.WritingModeVerticalRL,
+			width:   4,
+			height:  2,
+			content: "漢字仮名",
+			expected: []string{
+				"仮 漢 ",
+				"名 字 ",
+			},
+		},
+		{
+			name:    "VerticalLRWrapsWords",
+			mode:    layout.WritingModeVerticalLR,
+			width:   3,
+			height:  3,
+			content: "ab cd",
+			expected: []string{
+				"ac ",
+				"bd ",
+				"   ",
+			},
+		},
+		{
+			name:    "SidewaysRLRotatesCJK",
+			mode:    layout.WritingModeSidewaysRL,
+			width:   2,
+			height:  4,
+			content: "漢字",
+			expected: []string{
+				"漢 ",
+				"  ",
+				"字 ",
+				"  ",
+			},
+		},
+		{
+			name:    "SidewaysLRReadsBottomToTop",
+			mode:    layout.WritingModeSidewaysLR,
+			width:   1,
+			height:  3,
+			content: "abc",
+			expected: []string{
+				"c",
+				"b",
+				"a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			style := NewStyle()
+			style.TextWrap = TextWrapNormal
+			s := renderVertical(tt.width, tt.height, tt.mode, tt.content, style)
+
+			for row, want := range tt.expected {
+				if got := rowString(s, row); got != want {
+					t.Errorf("Row %d: expected %q, got %q", row, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWritingModeTextAlign(t *testing.T) {
+	style := NewStyle()
+	style.TextAlign = TextAlignRight
+	s := renderVertical(1, 4, layout.WritingModeVerticalLR, "ab", style)
+
+	expected := []string{" ", " ", "a", "b"}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestWritingModeOverflowEllipsis(t *testing.T) {
+	style := NewStyle().WithTextOverflow(TextOverflowEllipsis)
+	s := renderVertical(1, 3, layout.WritingModeVerticalLR, "abcdef", style)
+
+	expected := []string{"a", "b", "⋮"}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestWritingModeOverflowEllipsisASCII(t *testing.T) {
+	s := NewScreen(1, 3)
+	s.SetUnicode(false)
+	styled := NewStyledNode(&layout.Node{
+		Rect:  layout.Rect{Width: 1, Height: 3},
+		Style: layout.Style{WritingMode: layout.WritingModeVerticalLR},
+	}, NewStyle().WithTextOverflow(TextOverflowEllipsis))
+	styled.Content = "abcdef"
+	s.Render(styled)
+
+	expected := []string{"a", "b", ":"}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
+
+func TestWritingModeLineClamp(t *testing.T) {
+	style := NewStyle().WithLineClamp(1)
+	s := renderVertical(3, 2, layout.WritingModeVerticalLR, "ab\ncd", style)
+
+	expected := []string{"a  ", "⋮  "}
+	for row, want := range expected {
+		if got := rowString(s, row); got != want {
+			t.Errorf("Row %d: expected %q, got %q", row, want, got)
+		}
+	}
+}
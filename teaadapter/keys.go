@@ -0,0 +1,104 @@
+package teaadapter
+
+import (
+	"github.com/SCKelemen/cli/input"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// teaKey is an input key with modifiers
+type teaKey struct {
+	key input.Key
+	mod input.Modifier
+}
+
+var teaKeys = map[tea.KeyType]teaKey{
+	tea.KeyEnter:            {input.KeyEnter, 0},
+	tea.KeyTab:              {input.KeyTab, 0},
+	tea.KeyShiftTab:         {input.KeyTab, input.ModShift},
+	tea.KeyBackspace:        {input.KeyBackspace, 0},
+	tea.KeyEsc:              {input.KeyEscape, 0},
+	tea.KeyUp:               {input.KeyUp, 0},
+	tea.KeyDown:             {input.KeyDown, 0},
+	tea.KeyRight:            {input.KeyRight, 0},
+	tea.KeyLeft:             {input.KeyLeft, 0},
+	tea.KeyHome:             {input.KeyHome, 0},
+	tea.KeyEnd:              {input.KeyEnd, 0},
+	tea.KeyPgUp:             {input.KeyPageUp, 0},
+	tea.KeyPgDown:           {input.KeyPageDown, 0},
+	tea.KeyCtrlPgUp:         {input.KeyPageUp, input.ModCtrl},
+	tea.KeyCtrlPgDown:       {input.KeyPageDown, input.ModCtrl},
+	tea.KeyDelete:           {input.KeyDelete, 0},
+	tea.KeyInsert:           {input.KeyInsert, 0},
+	tea.KeyCtrlUp:           {input.KeyUp, input.ModCtrl},
+	tea.KeyCtrlDown:         {input.KeyDown, input.ModCtrl},
+	tea.KeyCtrlRight:        {input.KeyRight, input.ModCtrl},
+	tea.KeyCtrlLeft:         {input.KeyLeft, input.ModCtrl},
+	tea.KeyCtrlHome:         {input.KeyHome, input.ModCtrl},
+	tea.KeyCtrlEnd:          {input.KeyEnd, input.ModCtrl},
+	tea.KeyShiftUp:          {input.KeyUp, input.ModShift},
+	tea.KeyShiftDown:        {input.KeyDown, input.ModShift},
+	tea.KeyShiftRight:       {input.KeyRight, input.ModShift},
+	tea.KeyShiftLeft:        {input.KeyLeft, input.ModShift},
+	tea.KeyShiftHome:        {input.KeyHome, input.ModShift},
+	tea.KeyShiftEnd:         {input.KeyEnd, input.ModShift},
+	tea.KeyCtrlShiftUp:      {input.KeyUp, input.ModCtrl | input.ModShift},
+	tea.KeyCtrlShiftDown:    {input.KeyDown, input.ModCtrl | input.ModShift},
+	tea.KeyCtrlShiftLeft:    {input.KeyLeft, input.ModCtrl | input.ModShift},
+	tea.KeyCtrlShiftRight:   {input.KeyRight, input.ModCtrl | input.ModShift},
+	tea.KeyCtrlShiftHome:    {input.KeyHome, input.ModCtrl | input.ModShift},
+	tea.KeyCtrlShiftEnd:     {input.KeyEnd, input.ModCtrl | input.ModShift},
+	tea.KeyF1:               {input.KeyF1, 0},
+	tea.KeyF2:               {input.KeyF2, 0},
+	tea.KeyF3:               {input.KeyF3, 0},
+	tea.KeyF4:               {input.KeyF4, 0},
+	tea.KeyF5:               {input.KeyF5, 0},
+	tea.KeyF6:               {input.KeyF6, 0},
+	tea.KeyF7:               {input.KeyF7, 0},
+	tea.KeyF8:               {input.KeyF8, 0},
+	tea.KeyF9:               {input.KeyF9, 0},
+	tea.KeyF10:              {input.KeyF10, 0},
+	tea.KeyF11:              {input.KeyF11, 0},
+	tea.KeyF12:              {input.KeyF12, 0},
+	tea.KeyCtrlBackslash:    {input.KeyRune, input.ModCtrl},
+	tea.KeyCtrlCloseBracket: {input.KeyRune, input.ModCtrl},
+	tea.KeyCtrlCaret:        {input.KeyRune, input.ModCtrl},
+	tea.KeyCtrlUnderscore:   {input.KeyRune, input.ModCtrl},
+}
+
+// keyEvent converts a bubbletea key message to the event the renderer
+// dispatches to the focused node. Ok is false for pastes and for text of
+// more than one rune, which are not single key presses.
+func keyEvent(msg tea.KeyMsg) (event input.KeyEvent, ok bool) {
+	if msg.Paste {
+		return event, false
+	}
+
+	switch {
+	case msg.Type == tea.KeyRunes:
+		if len(msg.Runes) != 1 {
+			return event, false
+		}
+		event = input.KeyEvent{Key: input.KeyRune, Rune: msg.Runes[0]}
+	case msg.Type == tea.KeySpace:
+		event = input.KeyEvent{Key: input.KeyRune, Rune: ' '}
+	case msg.Type == tea.KeyCtrlAt:
+		event = input.KeyEvent{Key: input.KeyRune, Rune: ' ', Mod: input.ModCtrl}
+	case msg.Type >= tea.KeyCtrlA && msg.Type <= tea.KeyCtrlZ && msg.Type != tea.KeyTab && msg.Type != tea.KeyEnter:
+		event = input.KeyEvent{Key: input.KeyRune, Rune: rune('a' + msg.Type - tea.KeyCtrlA), Mod: input.ModCtrl}
+	default:
+		k, found := teaKeys[msg.Type]
+		if !found {
+			return event, false
+		}
+		event = input.KeyEvent{Key: k.key, Mod: k.mod}
+		if k.key == input.KeyRune {
+			// Ctrl+\ through Ctrl+_ are sent as the control codes 0x1c–0x1f
+			event.Rune = rune(msg.Type) + 0x40
+		}
+	}
+
+	if msg.Alt {
+		event.Mod |= input.ModAlt
+	}
+	return event, true
+}
@@ -0,0 +1,187 @@
+package teaadapter
+
+import (
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/layout"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultQuitKeys quit the program unless changed with WithQuitKeys
+var DefaultQuitKeys = []string{"q", "ctrl+c", "esc"}
+
+// Component builds the tree drawn at a terminal size. Components may also
+// implement Initializer, Updater and MouseHandler.
+type Component interface {
+	Render(width, height int) *renderer.StyledNode
+}
+
+// ComponentFunc adapts a render function to Component
+type ComponentFunc func(width, height int) *renderer.StyledNode
+
+// Render calls f
+func (f ComponentFunc) Render(width, height int) *renderer.StyledNode {
+	return f(width, height)
+}
+
+// Initializer is implemented by components with a startup command
+type Initializer interface {
+	Init() tea.Cmd
+}
+
+// Updater is implemented by components that handle messages
+type Updater interface {
+	Update(msg tea.Msg) tea.Cmd
+}
+
+// MouseHandler is implemented by components that handle mouse events.
+// Target is the deepest node under the pointer in the last drawn tree, or
+// nil outside it.
+type MouseHandler interface {
+	HandleMouse(msg tea.MouseMsg, target *renderer.StyledNode) tea.Cmd
+}
+
+// Model is a tea.Model that draws a Component through one Screen, resized
+// on tea.WindowSizeMsg rather than allocated on every View. Each View lays
+// the component's tree out at the window size with a LayoutContext for
+// that viewport, so viewport and font-relative units resolve correctly.
+//
+// Key presses go to the focused node of the last drawn tree first (see
+// renderer.FocusManager). The tree is rebuilt on every View, so focusable
+// nodes need an ID to keep focus.
+type Model struct {
+	component    Component
+	screen       *renderer.Screen
+	tree         *renderer.StyledNode // Last drawn tree, for hit-testing
+	events       *renderer.EventDispatcher
+	focus        *renderer.FocusManager
+	width        int
+	height       int
+	rootFontSize float64
+	quitKeys     []string
+	placeholder  string
+}
+
+// New creates a model drawing component
+func New(component Component) *Model {
+	screen := renderer.NewScreen(0, 0)
+	screen.SetOutputMode(renderer.OutputModeANSI)
+
+	m := &Model{
+		component:    component,
+		screen:       screen,
+		events:       renderer.NewEventDispatcher(nil),
+		focus:        renderer.NewFocusManager(nil),
+		rootFontSize: 16,
+		quitKeys:     DefaultQuitKeys,
+		placeholder:  "Initializing...",
+	}
+	m.events.SetFocusManager(m.focus)
+	return m
+}
+
+// WithQuitKeys sets the keys that quit the program. Pass none to leave
+// every key to the component.
+func (m *Model) WithQuitKeys(keys ...string) *Model {
+	m.quitKeys = keys
+	return m
+}
+
+// WithRootFontSize sets the root font size used to resolve rem and em units
+func (m *Model) WithRootFontSize(size float64) *Model {
+	m.rootFontSize = size
+	return m
+}
+
+// WithPlaceholder sets the view shown before the first window size arrives
+func (m *Model) WithPlaceholder(placeholder string) *Model {
+	m.placeholder = placeholder
+	return m
+}
+
+// Size returns the window size, or zero before the first tea.WindowSizeMsg
+func (m *Model) Size() (width, height int) {
+	return m.width, m.height
+}
+
+// Screen returns the screen the component is drawn on
+func (m *Model) Screen() *renderer.Screen {
+	return m.screen
+}
+
+// Focus returns the focus manager of the drawn tree
+func (m *Model) Focus() *renderer.FocusManager {
+	return m.focus
+}
+
+// Init runs the component's startup command
+func (m *Model) Init() tea.Cmd {
+	if c, ok := m.component.(Initializer); ok {
+		return c.Init()
+	}
+	return nil
+}
+
+// Update resizes the screen and handles quit keys. Key presses are first
+// delivered to the focused node, and those it uses or that move focus go no
+// further. Mouse events are dispatched to the listeners of the last drawn
+// tree (see StyledNode.On), then handed with their hit-tested node to the
+// component. Every other message, including window sizes, is passed on to
+// the component's Update.
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.screen.Resize(msg.Width, msg.Height)
+
+	case tea.KeyMsg:
+		if event, ok := keyEvent(msg); ok && m.focus.HandleKey(event) {
+			return m, nil
+		}
+		// Pastes and runes typed faster than they were read arrive as text
+		if msg.Type == tea.KeyRunes && (msg.Paste || len(msg.Runes) > 1) && m.focus.HandlePaste(string(msg.Runes)) {
+			return m, nil
+		}
+		key := msg.String()
+		for _, quit := range m.quitKeys {
+			if key == quit {
+				return m, tea.Quit
+			}
+		}
+
+	case tea.MouseMsg:
+		m.events.Dispatch(mouseEvent(msg))
+		if c, ok := m.component.(MouseHandler); ok {
+			return m, c.HandleMouse(msg, renderer.HitTest(m.tree, msg.X, msg.Y))
+		}
+	}
+
+	if c, ok := m.component.(Updater); ok {
+		return m, c.Update(msg)
+	}
+	return m, nil
+}
+
+// View lays out and draws the component's tree
+func (m *Model) View() string {
+	if m.width <= 0 || m.height <= 0 {
+		return m.placeholder
+	}
+
+	tree := m.component.Render(m.width, m.height)
+	if tree == nil || tree.Node == nil {
+		m.tree = nil
+		m.events.SetRoot(nil)
+		m.focus.SetRoot(nil)
+		return ""
+	}
+
+	width, height := float64(m.width), float64(m.height)
+	ctx := layout.NewLayoutContext(width, height, m.rootFontSize)
+	layout.Layout(tree.Node, layout.Tight(width, height), ctx)
+	m.focus.SetRoot(tree)
+
+	m.screen.Render(tree)
+	m.tree = tree
+	m.events.SetRoot(tree)
+	return m.screen.String()
+}
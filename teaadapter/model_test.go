@@ -0,0 +1,191 @@
+package teaadapter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/SCKelemen/cli/renderer"
+	"github.com/SCKelemen/layout"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// testComponent draws a 4-cell wide button at the top left
+type testComponent struct {
+	button  *renderer.StyledNode
+	clicked *renderer.StyledNode
+	msgs    int
+}
+
+func (c *testComponent) Render(width, height int) *renderer.StyledNode {
+	root := renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{Display: layout.DisplayFlex, FlexDirection: layout.FlexDirectionRow},
+	}, nil)
+	c.button = renderer.NewStyledNode(&layout.Node{
+		Style: layout.Style{Display: layout.DisplayBlock, Width: layout.Vw(50), Height: layout.Px(1)},
+	}, nil)
+	c.button.Content = "OK"
+	root.AddChild(c.button)
+	return root
+}
+
+func (c *testComponent) Update(msg tea.Msg) tea.Cmd {
+	c.msgs++
+	return nil
+}
+
+func (c *testComponent) HandleMouse(msg tea.MouseMsg, target *renderer.StyledNode) tea.Cmd {
+	c.clicked = target
+	return nil
+}
+
+func TestModelLaysOutAtWindowSize(t *testing.T) {
+	c := &testComponent{}
+	m := New(c)
+
+	if view := m.View(); view != "Initializing..." {
+		t.Errorf("Expected placeholder before the window size, got %q", view)
+	}
+
+	m.Update(tea.WindowSizeMsg{Width: 8, Height: 2})
+	screen := m.Screen()
+	m.View()
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 3})
+	view := m.View()
+
+	if m.Screen() != screen || screen.Width != 10 || screen.Height != 3 {
+		t.Errorf("Expected the same screen resized to 10x3, got %dx%d", screen.Width, screen.Height)
+	}
+	if got := c.button.Node.Rect.Width; got != 5 {
+		t.Errorf("Expected Vw(50) to resolve against the window width, got %v", got)
+	}
+	if !strings.Contains(view, "OK") {
+		t.Errorf("Expected the view to contain the button, got %q", view)
+	}
+	if c.msgs != 2 {
+		t.Errorf("Expected window sizes to reach the component, got %d messages", c.msgs)
+	}
+}
+
+func TestModelHitTestsMouse(t *testing.T) {
+	c := &testComponent{}
+	m := New(c)
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 3})
+	m.View()
+
+	m.Update(tea.MouseMsg{X: 2, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if c.clicked != c.button {
+		t.Error("Expected the click to target the button")
+	}
+	m.Update(tea.MouseMsg{X: 7, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	if c.clicked == c.button || c.clicked == nil {
+		t.Error("Expected a click beside the button to target the root")
+	}
+}
+
+func TestModelDispatchesToListeners(t *testing.T) {
+	clicks := 0
+	m := New(ComponentFunc(func(width, height int) *renderer.StyledNode {
+		root := renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{Display: layout.DisplayFlex, FlexDirection: layout.FlexDirectionRow},
+		}, nil)
+		root.On(renderer.EventClick, func(e *renderer.Event) { clicks++ })
+		root.AddChild(renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{Display: layout.DisplayBlock, Width: layout.Vw(50), Height: layout.Px(1)},
+		}, nil))
+		return root
+	}))
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 3})
+	m.View()
+
+	m.Update(tea.MouseMsg{X: 2, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	m.Update(tea.MouseMsg{X: 2, Y: 0, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+	if clicks != 1 {
+		t.Errorf("Expected one click to bubble to the root, got %d", clicks)
+	}
+}
+
+func TestModelClickAcrossFrames(t *testing.T) {
+	clicks, enters := 0, 0
+	m := New(ComponentFunc(func(width, height int) *renderer.StyledNode {
+		root := renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{Display: layout.DisplayFlex, FlexDirection: layout.FlexDirectionRow},
+		}, nil)
+		button := renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{Display: layout.DisplayBlock, Width: layout.Vw(50), Height: layout.Px(1)},
+		}, nil)
+		button.On(renderer.EventClick, func(e *renderer.Event) { clicks++ })
+		button.On(renderer.EventMouseEnter, func(e *renderer.Event) { enters++ })
+		root.AddChild(button)
+		return root
+	}))
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 3})
+	m.View()
+
+	// Bubbletea draws a new tree between the press and the release
+	m.Update(tea.MouseMsg{X: 2, Y: 0, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+	m.View()
+	m.Update(tea.MouseMsg{X: 2, Y: 0, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})
+	m.View()
+	m.Update(tea.MouseMsg{X: 3, Y: 0, Action: tea.MouseActionMotion})
+	if clicks != 1 {
+		t.Errorf("Expected one click on the rebuilt button, got %d", clicks)
+	}
+	if enters != 1 {
+		t.Errorf("Expected the pointer to enter the button once, got %d", enters)
+	}
+}
+
+func TestModelFocusesKeys(t *testing.T) {
+	var keys []string
+	m := New(ComponentFunc(func(width, height int) *renderer.StyledNode {
+		root := renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{Display: layout.DisplayFlex, FlexDirection: layout.FlexDirectionRow},
+		}, nil)
+		field := renderer.NewStyledNode(&layout.Node{
+			Style: layout.Style{Display: layout.DisplayBlock, Width: layout.Vw(50), Height: layout.Px(1)},
+		}, nil)
+		field.Focusable = true
+		field.ID = "field"
+		field.On(renderer.EventKeyDown, func(e *renderer.Event) {
+			keys = append(keys, e.Key.String())
+			e.PreventDefault()
+		})
+		field.On(renderer.EventPaste, func(e *renderer.Event) {
+			keys = append(keys, "paste:"+e.Text)
+			e.PreventDefault()
+		})
+		root.AddChild(field)
+		return root
+	}))
+	m.Update(tea.WindowSizeMsg{Width: 10, Height: 3})
+	m.View()
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyTab}); cmd != nil {
+		t.Error("Expected Tab to move focus rather than reach the component")
+	}
+	m.View()
+	if focused := m.Focus().Focused(); focused == nil || focused.ID != "field" {
+		t.Fatal("Expected the field to keep focus across a rebuilt tree")
+	}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("Expected the focused field to take q before the quit keys")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyCtrlA, Alt: true})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("hi"), Paste: true})
+	if strings.Join(keys, " ") != "q ctrl+alt+a paste:hi" {
+		t.Errorf("Expected the field to receive q, ctrl+alt+a and the paste, got %v", keys)
+	}
+}
+
+func TestModelQuitKeys(t *testing.T) {
+	m := New(ComponentFunc(func(width, height int) *renderer.StyledNode { return nil }))
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("Expected q to quit by default")
+	}
+
+	m.WithQuitKeys()
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd != nil {
+		t.Error("Expected no quit keys after WithQuitKeys()")
+	}
+}
@@ -0,0 +1,44 @@
+package teaadapter
+
+import (
+	"github.com/SCKelemen/cli/input"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var mouseButtons = map[tea.MouseButton]input.MouseButton{
+	tea.MouseButtonLeft:       input.MouseLeft,
+	tea.MouseButtonMiddle:     input.MouseMiddle,
+	tea.MouseButtonRight:      input.MouseRight,
+	tea.MouseButtonWheelUp:    input.MouseWheelUp,
+	tea.MouseButtonWheelDown:  input.MouseWheelDown,
+	tea.MouseButtonWheelLeft:  input.MouseWheelLeft,
+	tea.MouseButtonWheelRight: input.MouseWheelRight,
+	tea.MouseButtonBackward:   input.MouseBackward,
+	tea.MouseButtonForward:    input.MouseForward,
+}
+
+// mouseEvent converts a bubbletea mouse message to the event the renderer
+// dispatches to StyledNode listeners
+func mouseEvent(msg tea.MouseMsg) input.MouseEvent {
+	event := input.MouseEvent{X: msg.X, Y: msg.Y, Button: mouseButtons[msg.Button]}
+
+	switch msg.Action {
+	case tea.MouseActionRelease:
+		event.Action = input.MouseRelease
+	case tea.MouseActionMotion:
+		event.Action = input.MouseMotion
+	default:
+		event.Action = input.MousePress
+	}
+
+	if msg.Shift {
+		event.Mod |= input.ModShift
+	}
+	if msg.Alt {
+		event.Mod |= input.ModAlt
+	}
+	if msg.Ctrl {
+		event.Mod |= input.ModCtrl
+	}
+	return event
+}